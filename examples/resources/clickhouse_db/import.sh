@@ -0,0 +1,2 @@
+# Databases are imported by cluster and database name. Leave the cluster empty for non clustered databases.
+terraform import clickhouse_db.awesome_database cluster:awesome_database
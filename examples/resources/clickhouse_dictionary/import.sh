@@ -0,0 +1,3 @@
+# Dictionaries are imported by cluster, database and dictionary name. Leave the cluster empty for non clustered dictionaries.
+# The source is not read back, it is set on the next apply.
+terraform import clickhouse_dictionary.regions :awesome_database:regions
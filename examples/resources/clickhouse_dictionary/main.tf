@@ -0,0 +1,86 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_db" "awesome_database" {
+  name    = "awesome_database"
+  comment = "This is an awesome database"
+}
+
+resource "clickhouse_dictionary" "regions" {
+  database    = clickhouse_db.awesome_database.name
+  name        = "regions"
+  comment     = "Region names by id"
+  primary_key = ["id"]
+  attribute {
+    name = "id"
+    type = "UInt64"
+  }
+  attribute {
+    name         = "parent_id"
+    type         = "UInt64"
+    default      = "0"
+    hierarchical = true
+  }
+  attribute {
+    name    = "name"
+    type    = "String"
+    default = "'unknown'"
+  }
+  source {
+    clickhouse {
+      user  = "default"
+      db    = "awesome_database"
+      table = "regions_source"
+    }
+  }
+  layout = "hashed"
+  lifetime {
+    min = 300
+    max = 600
+  }
+}
+
+resource "clickhouse_dictionary" "prices" {
+  database    = clickhouse_db.awesome_database.name
+  name        = "prices"
+  primary_key = ["product_id"]
+  attribute {
+    name = "product_id"
+    type = "UInt64"
+  }
+  attribute {
+    name = "valid_from"
+    type = "Date"
+  }
+  attribute {
+    name = "valid_to"
+    type = "Date"
+  }
+  attribute {
+    name = "price"
+    type = "Decimal(18, 2)"
+  }
+  source {
+    named_collection {
+      name       = "pricing_postgres"
+      type       = "POSTGRESQL"
+      parameters = { table = "prices" }
+    }
+  }
+  layout    = "range_hashed"
+  range_min = "valid_from"
+  range_max = "valid_to"
+  lifetime {
+    max = 3600
+  }
+}
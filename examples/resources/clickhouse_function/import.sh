@@ -0,0 +1,2 @@
+# Functions are imported by cluster and function name. Leave the cluster empty for non clustered functions.
+terraform import clickhouse_function.linear_equation :linear_equation
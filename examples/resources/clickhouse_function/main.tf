@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_function" "linear_equation" {
+  name       = "linear_equation"
+  parameters = ["x", "k", "b"]
+  expression = "k * x + b"
+}
@@ -0,0 +1,3 @@
+# Grants are imported by cluster, database, table and grantee. Leave the cluster empty for non clustered grants
+# and the table empty for database grants. The imported grant owns every privilege of the grantee on the target.
+terraform import clickhouse_grant.analyst_clicks :events:clicks:analyst
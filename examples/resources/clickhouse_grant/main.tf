@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_grant" "analyst_logs" {
+  grantee    = "analyst"
+  database   = "logs"
+  privileges = ["SELECT"]
+}
+
+resource "clickhouse_grant" "analyst_clicks" {
+  grantee    = "analyst"
+  database   = "events"
+  table      = "clicks"
+  columns    = ["id", "url"]
+  privileges = ["SELECT"]
+}
@@ -0,0 +1,2 @@
+# Materialized views are imported by cluster, database and view name. Leave the cluster empty for non clustered views.
+terraform import clickhouse_materialized_view.events_daily_mv :awesome_database:events_daily_mv
@@ -0,0 +1,46 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_db" "awesome_database" {
+  name    = "awesome_database"
+  comment = "This is an awesome database"
+}
+
+resource "clickhouse_materialized_view" "events_daily_mv" {
+  database = clickhouse_db.awesome_database.name
+  name     = "events_daily_mv"
+  to       = "awesome_database.events_daily"
+  query    = "SELECT toDate(event_time) AS day, count() AS total FROM awesome_database.events GROUP BY day"
+}
+
+resource "clickhouse_materialized_view" "events_by_type" {
+  database     = clickhouse_db.awesome_database.name
+  name         = "events_by_type"
+  engine       = "SummingMergeTree"
+  order_by     = ["event_type"]
+  partition_by = "toYYYYMM(day)"
+  populate     = true
+  query        = "SELECT event_type, toDate(event_time) AS day, count() AS total FROM awesome_database.events GROUP BY event_type, day"
+}
+
+resource "clickhouse_materialized_view" "events_snapshot" {
+  database = clickhouse_db.awesome_database.name
+  name     = "events_snapshot"
+  to       = "awesome_database.events_snapshot"
+  query    = "SELECT event_type, count() AS total FROM awesome_database.events GROUP BY event_type"
+  refresh {
+    kind       = "EVERY"
+    interval   = "1 HOUR"
+    depends_on = ["awesome_database.events_daily_mv"]
+  }
+}
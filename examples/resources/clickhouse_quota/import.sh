@@ -0,0 +1,2 @@
+# Quotas are imported by name.
+terraform import clickhouse_quota.analysts analysts
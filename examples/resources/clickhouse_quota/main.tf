@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_quota" "analysts" {
+  name     = "analysts"
+  keyed_by = "user_name"
+
+  interval {
+    duration           = 3600
+    max_queries        = 1000
+    max_errors         = 100
+    max_execution_time = 600
+  }
+
+  interval {
+    duration       = 86400
+    randomized     = true
+    max_read_bytes = 1000000000000
+  }
+
+  to = ["analyst"]
+}
@@ -0,0 +1,2 @@
+# Roles are imported by name.
+terraform import clickhouse_role.awesome_role awesome_role
@@ -0,0 +1,2 @@
+# Role grants are imported by cluster, role and grantee. Leave the cluster empty for non clustered grants.
+terraform import clickhouse_role_grant.analyst_engineer :analyst:engineer
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+# analyst is included in engineer, which is included in admin
+resource "clickhouse_role_grant" "analyst_engineer" {
+  role    = "analyst"
+  grantee = "engineer"
+}
+
+resource "clickhouse_role_grant" "engineer_admin" {
+  role              = "engineer"
+  grantee           = "admin"
+  with_admin_option = true
+}
+
+resource "clickhouse_role_grant" "admin_alice" {
+  role    = "admin"
+  grantee = "alice"
+  default = true
+}
@@ -0,0 +1,2 @@
+# Row policies are imported by cluster, database, table and policy name. Leave the cluster empty for non clustered policies.
+terraform import clickhouse_row_policy.tenant :events:clicks:tenant
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_role" "tenant_42" {
+  name       = "tenant_42"
+  database   = "events"
+  privileges = ["SELECT"]
+}
+
+resource "clickhouse_row_policy" "tenant" {
+  name     = "tenant"
+  database = "events"
+  table    = "clicks"
+  filter   = "tenant_id = 42"
+  kind     = "permissive"
+  to       = [clickhouse_role.tenant_42.name]
+}
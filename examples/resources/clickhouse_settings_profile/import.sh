@@ -0,0 +1,2 @@
+# Settings profiles are imported by name.
+terraform import clickhouse_settings_profile.analysts analysts
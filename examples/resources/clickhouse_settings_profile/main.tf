@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_settings_profile" "analysts" {
+  name    = "analysts"
+  inherit = ["default"]
+
+  setting {
+    name        = "max_memory_usage"
+    value       = "10000000000"
+    max         = "20000000000"
+    writability = "CHANGEABLE_IN_READONLY"
+  }
+
+  setting {
+    name        = "readonly"
+    value       = "1"
+    writability = "CONST"
+  }
+}
+
+resource "clickhouse_user" "analyst" {
+  name              = "analyst"
+  password          = "awesome_user_password"
+  settings_profiles = [clickhouse_settings_profile.analysts.name]
+}
@@ -48,6 +48,12 @@ resource "clickhouse_table" "replicated_table" {
     by                 = "event_date"
     partition_function = "toYYYYMM"
   }
+  ttl {
+    expression = "event_date + INTERVAL 1 YEAR"
+  }
+  settings = {
+    index_granularity = "8192"
+  }
 }
 
 
@@ -56,11 +62,12 @@ resource "clickhouse_table" "distributed_table" {
   name     = "distributed_table"
   cluster  = "'{cluster}'"
   engine   = "Distributed"
-  engine_params = [
-    "'{cluster}'",
-    clickhouse_db.test_db_clustered.name,
-    clickhouse_table.replicated_table.name,
-  "rand()"]
+  distributed {
+    cluster         = "{cluster}"
+    remote_database = clickhouse_db.test_db_clustered.name
+    remote_table    = clickhouse_table.replicated_table.name
+    sharding_key    = "rand()"
+  }
 }
 
 
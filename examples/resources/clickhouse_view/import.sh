@@ -0,0 +1,2 @@
+# Views are imported by cluster, database and view name. Leave the cluster empty for non clustered views.
+terraform import clickhouse_view.recent_events :awesome_database:recent_events
@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    clickhouse = {
+      version = "0.0.1"
+      source  = "registry.terraform.io/fox052-byte/clickhouse"
+    }
+  }
+}
+
+provider "clickhouse" {
+  port = 8123
+}
+
+resource "clickhouse_db" "awesome_database" {
+  name    = "awesome_database"
+  comment = "This is an awesome database"
+}
+
+resource "clickhouse_view" "recent_events" {
+  database   = clickhouse_db.awesome_database.name
+  name       = "recent_events"
+  query      = "SELECT * FROM awesome_database.events WHERE event_date >= today() - 7"
+  comment    = "Events of the last week"
+  or_replace = true
+}
+
+resource "clickhouse_view" "user_events" {
+  database     = clickhouse_db.awesome_database.name
+  name         = "user_events"
+  query        = "SELECT * FROM awesome_database.events WHERE user_id = {user_id:UInt64}"
+  sql_security = "DEFINER"
+  definer      = "CURRENT_USER"
+}
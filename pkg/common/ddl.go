@@ -0,0 +1,699 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateQuery is the definition of a table-like object parsed from its CREATE statement,
+// as found in system.tables create_table_query or engine_full columns.
+type CreateQuery struct {
+	// Kind is TABLE, VIEW, MATERIALIZED VIEW, LIVE VIEW, WINDOW VIEW or DICTIONARY
+	Kind     string
+	Database string
+	Name     string
+	UUID     string
+	Cluster  string
+	// To is the target table of materialized views, as written in the query
+	To          string
+	Columns     []ColumnDefinition
+	Indexes     []string
+	Projections []string
+	Constraints []string
+	Engine      *EngineDefinition
+	OrderBy     []string
+	PartitionBy []string
+	PrimaryKey  []string
+	SampleBy    string
+	TTL         string
+	Settings    map[string]string
+	Comment     string
+	// Clauses holds the raw value of every top level clause, e.g. "REFRESH", "SOURCE" or "LIFETIME"
+	Clauses map[string]string
+	// Select is the query after AS of views
+	Select string
+}
+
+type ColumnDefinition struct {
+	Name string
+	Type string
+	// DefaultKind is DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL
+	DefaultKind       string
+	DefaultExpression string
+	// Codec is the list of codecs without the CODEC keyword
+	Codec   string
+	TTL     string
+	Comment string
+	// Attributes holds the raw value of other column attributes, e.g. dictionary EXPRESSION or HIERARCHICAL
+	Attributes map[string]string
+}
+
+type EngineDefinition struct {
+	Name   string
+	Params []string
+}
+
+// HasClause tells if the top level clause is present, even without value (e.g. POPULATE)
+func (q *CreateQuery) HasClause(clause string) bool {
+	_, ok := q.Clauses[clause]
+	return ok
+}
+
+// Column returns the column with the given name or nil
+func (q *CreateQuery) Column(name string) *ColumnDefinition {
+	for i := range q.Columns {
+		if q.Columns[i].Name == name {
+			return &q.Columns[i]
+		}
+	}
+	return nil
+}
+
+type ddlTokenKind int
+
+const (
+	ddlEOF ddlTokenKind = iota
+	ddlWord
+	ddlQuotedIdentifier
+	ddlString
+	ddlNumber
+	ddlSymbol
+)
+
+type ddlToken struct {
+	Kind ddlTokenKind
+	// Text is the token as written, except for quoted identifiers which are unquoted
+	Text  string
+	Start int
+	End   int
+}
+
+func tokenizeDDL(query string) ([]ddlToken, error) {
+	var tokens []ddlToken
+	for i := 0; i < len(query); {
+		char := query[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end == -1 {
+				end = len(query) - i
+			}
+			i += end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated comment at position %d", i)
+			}
+			i += end + 4
+		case char == '\'' || char == '`' || char == '"':
+			end := i + 1
+			for ; end < len(query) && query[end] != char; end++ {
+				if query[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(query) {
+				return nil, fmt.Errorf("unterminated quoted text at position %d", i)
+			}
+			token := ddlToken{Kind: ddlString, Text: query[i : end+1], Start: i, End: end + 1}
+			if char != '\'' {
+				token.Kind = ddlQuotedIdentifier
+				token.Text = query[i+1 : end]
+			}
+			tokens = append(tokens, token)
+			i = end + 1
+		case char >= '0' && char <= '9':
+			end := i + 1
+			for ; end < len(query) && (isDDLWordChar(query[end]) || query[end] == '.'); end++ {
+			}
+			tokens = append(tokens, ddlToken{Kind: ddlNumber, Text: query[i:end], Start: i, End: end})
+			i = end
+		case isDDLWordChar(char):
+			end := i + 1
+			for ; end < len(query) && isDDLWordChar(query[end]); end++ {
+			}
+			tokens = append(tokens, ddlToken{Kind: ddlWord, Text: query[i:end], Start: i, End: end})
+			i = end
+		default:
+			tokens = append(tokens, ddlToken{Kind: ddlSymbol, Text: string(char), Start: i, End: i + 1})
+			i++
+		}
+	}
+	return append(tokens, ddlToken{Kind: ddlEOF, Start: len(query), End: len(query)}), nil
+}
+
+func isDDLWordChar(char byte) bool {
+	return char == '_' || char == '$' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9'
+}
+
+type ddlParser struct {
+	query  string
+	tokens []ddlToken
+	pos    int
+}
+
+func (p *ddlParser) peek() ddlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *ddlParser) next() ddlToken {
+	token := p.tokens[p.pos]
+	if token.Kind != ddlEOF {
+		p.pos++
+	}
+	return token
+}
+
+// isKeyword tells if the next tokens are the given case insensitive words
+func (p *ddlParser) isKeyword(words ...string) bool {
+	for i, word := range words {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		token := p.tokens[p.pos+i]
+		if token.Kind != ddlWord || !strings.EqualFold(token.Text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeyword consumes the given words when they are next
+func (p *ddlParser) acceptKeyword(words ...string) bool {
+	if !p.isKeyword(words...) {
+		return false
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *ddlParser) isSymbol(symbol string) bool {
+	token := p.peek()
+	return token.Kind == ddlSymbol && token.Text == symbol
+}
+
+func (p *ddlParser) expectSymbol(symbol string) error {
+	if token := p.next(); token.Kind != ddlSymbol || token.Text != symbol {
+		return p.unexpected(token, fmt.Sprintf("%q", symbol))
+	}
+	return nil
+}
+
+func (p *ddlParser) unexpected(token ddlToken, expected string) error {
+	if token.Kind == ddlEOF {
+		return fmt.Errorf("unexpected end of query, expected %s", expected)
+	}
+	return fmt.Errorf("unexpected %q at position %d, expected %s", token.Text, token.Start, expected)
+}
+
+func (p *ddlParser) identifier() (string, error) {
+	token := p.next()
+	if token.Kind != ddlWord && token.Kind != ddlQuotedIdentifier {
+		return "", p.unexpected(token, "an identifier")
+	}
+	return token.Text, nil
+}
+
+// qualifiedName parses [database.]name
+func (p *ddlParser) qualifiedName() (string, string, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return "", "", err
+	}
+	if !p.isSymbol(".") {
+		return "", name, nil
+	}
+	p.next()
+	table, err := p.identifier()
+	return name, table, err
+}
+
+// expression consumes tokens up to the first top level token for which stop returns true
+// and returns the source text they span.
+func (p *ddlParser) expression(stop func() bool) (string, error) {
+	start := p.peek().Start
+	end := start
+	depth := 0
+	for {
+		token := p.peek()
+		if token.Kind == ddlEOF {
+			if depth > 0 {
+				return "", p.unexpected(token, `")"`)
+			}
+			break
+		}
+		if depth == 0 && (stop() || token.Kind == ddlSymbol && (token.Text == ")" || token.Text == "]")) {
+			break
+		}
+		if token.Kind == ddlSymbol {
+			switch token.Text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+			}
+		}
+		p.next()
+		end = token.End
+	}
+	return strings.TrimSpace(p.query[start:end]), nil
+}
+
+// list parses a parenthesized list of comma separated expressions
+func (p *ddlParser) list() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	items := make([]string, 0)
+	for !p.isSymbol(")") {
+		item, err := p.expression(func() bool { return p.isSymbol(",") })
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.isSymbol(",") {
+			p.next()
+		} else if !p.isSymbol(")") {
+			return nil, p.unexpected(p.peek(), `")"`)
+		}
+	}
+	p.next()
+	return items, nil
+}
+
+// Clauses that may follow the columns of a CREATE statement, longest first so that they are matched greedily
+var ddlClauses = [][]string{
+	{"ORDER", "BY"}, {"PARTITION", "BY"}, {"PRIMARY", "KEY"}, {"SAMPLE", "BY"}, {"SQL", "SECURITY"},
+	{"ENGINE"}, {"TTL"}, {"SETTINGS"}, {"COMMENT"}, {"POPULATE"}, {"EMPTY"}, {"DEFINER"}, {"AS"},
+	{"REFRESH"}, {"APPEND"}, {"TO"}, {"SOURCE"}, {"LAYOUT"}, {"LIFETIME"}, {"RANGE"},
+}
+
+func (p *ddlParser) clause() string {
+	for _, words := range ddlClauses {
+		if p.isKeyword(words...) {
+			return strings.Join(words, " ")
+		}
+	}
+	return ""
+}
+
+// Column attributes, ClickHouse writes them in this order
+var ddlColumnAttributes = [][]string{
+	{"NOT", "NULL"}, {"NULL"}, {"DEFAULT"}, {"MATERIALIZED"}, {"ALIAS"}, {"EPHEMERAL"}, {"COMMENT"}, {"CODEC"},
+	{"STATISTICS"}, {"TTL"}, {"PRIMARY", "KEY"}, {"SETTINGS"},
+	{"EXPRESSION"}, {"HIERARCHICAL"}, {"BIDIRECTIONAL"}, {"INJECTIVE"}, {"IS_OBJECT_ID"},
+}
+
+func (p *ddlParser) columnAttribute() string {
+	for _, words := range ddlColumnAttributes {
+		if p.isKeyword(words...) {
+			return strings.Join(words, " ")
+		}
+	}
+	return ""
+}
+
+// ParseCreateQuery parses a CREATE TABLE, VIEW, MATERIALIZED VIEW or DICTIONARY statement
+func ParseCreateQuery(query string) (*CreateQuery, error) {
+	tokens, err := tokenizeDDL(query)
+	if err != nil {
+		return nil, err
+	}
+	p := ddlParser{query: query, tokens: tokens}
+	createQuery := CreateQuery{Clauses: make(map[string]string), Settings: make(map[string]string)}
+
+	if !p.acceptKeyword("CREATE") && !p.acceptKeyword("ATTACH") {
+		return nil, p.unexpected(p.peek(), "CREATE")
+	}
+	p.acceptKeyword("OR", "REPLACE")
+	p.acceptKeyword("TEMPORARY")
+	switch {
+	case p.acceptKeyword("TABLE"):
+		createQuery.Kind = "TABLE"
+	case p.acceptKeyword("VIEW"):
+		createQuery.Kind = "VIEW"
+	case p.acceptKeyword("MATERIALIZED", "VIEW"):
+		createQuery.Kind = "MATERIALIZED VIEW"
+	case p.acceptKeyword("LIVE", "VIEW"):
+		createQuery.Kind = "LIVE VIEW"
+	case p.acceptKeyword("WINDOW", "VIEW"):
+		createQuery.Kind = "WINDOW VIEW"
+	case p.acceptKeyword("DICTIONARY"):
+		createQuery.Kind = "DICTIONARY"
+	default:
+		return nil, p.unexpected(p.peek(), "TABLE, VIEW, MATERIALIZED VIEW or DICTIONARY")
+	}
+	p.acceptKeyword("IF", "NOT", "EXISTS")
+
+	if createQuery.Database, createQuery.Name, err = p.qualifiedName(); err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("UUID") {
+		token := p.next()
+		if token.Kind != ddlString {
+			return nil, p.unexpected(token, "a UUID")
+		}
+		createQuery.UUID = UnquoteString(token.Text)
+	}
+	if p.acceptKeyword("ON", "CLUSTER") {
+		if createQuery.Cluster, err = p.identifierOrString(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.body(&createQuery); err != nil {
+		return nil, err
+	}
+	return &createQuery, nil
+}
+
+// ParseEngineFull parses the engine_full column of system.tables, i.e. the engine and the storage clauses
+func ParseEngineFull(engineFull string) (*CreateQuery, error) {
+	tokens, err := tokenizeDDL(engineFull)
+	if err != nil {
+		return nil, err
+	}
+	p := ddlParser{query: engineFull, tokens: tokens}
+	createQuery := CreateQuery{Clauses: make(map[string]string), Settings: make(map[string]string)}
+	if createQuery.Engine, err = p.engine(); err != nil {
+		return nil, err
+	}
+	if err := p.body(&createQuery); err != nil {
+		return nil, err
+	}
+	return &createQuery, nil
+}
+
+func (p *ddlParser) identifierOrString() (string, error) {
+	if token := p.peek(); token.Kind == ddlString {
+		p.next()
+		return UnquoteString(token.Text), nil
+	}
+	return p.identifier()
+}
+
+// body parses the columns and the clauses that follow the name of the object
+func (p *ddlParser) body(createQuery *CreateQuery) error {
+	for {
+		token := p.peek()
+		if token.Kind == ddlEOF || p.isSymbol(";") {
+			return nil
+		}
+		if p.isSymbol("(") && createQuery.Columns == nil && createQuery.Engine == nil {
+			if err := p.columns(createQuery); err != nil {
+				return err
+			}
+			continue
+		}
+
+		clause := p.clause()
+		if clause == "" || clause == "TO" && createQuery.Kind != "MATERIALIZED VIEW" {
+			return p.unexpected(token, "a clause")
+		}
+		p.pos += len(strings.Split(clause, " "))
+		if err := p.clauseValue(createQuery, clause); err != nil {
+			return fmt.Errorf("parsing %s: %v", clause, err)
+		}
+	}
+}
+
+func (p *ddlParser) clauseValue(createQuery *CreateQuery, clause string) error {
+	var err error
+	switch clause {
+	case "ENGINE":
+		p.acceptSymbol("=")
+		createQuery.Engine, err = p.engine()
+		return err
+	case "TO":
+		var database, name string
+		if database, name, err = p.qualifiedName(); err != nil {
+			return err
+		}
+		if database != "" {
+			name = database + "." + name
+		}
+		createQuery.To = name
+		createQuery.Clauses[clause] = name
+		return nil
+	case "SQL SECURITY":
+		value, err := p.identifier()
+		createQuery.Clauses[clause] = value
+		return err
+	case "AS":
+		// Views end with their query, only followed by the comment
+		createQuery.Select, err = p.expression(func() bool { return p.isKeyword("COMMENT") || p.isSymbol(";") })
+		createQuery.Clauses[clause] = createQuery.Select
+		return err
+	case "POPULATE", "EMPTY", "APPEND":
+		createQuery.Clauses[clause] = ""
+		return nil
+	}
+
+	// TO only introduces the target of materialized views, afterwards it is part of expressions like TTL ... TO DISK
+	targetAllowed := createQuery.Kind == "MATERIALIZED VIEW" && createQuery.To == "" && createQuery.Engine == nil && createQuery.Columns == nil
+	value, err := p.expression(func() bool {
+		next := p.clause()
+		return next != "" && (next != "TO" || targetAllowed) || p.isSymbol(";")
+	})
+	if err != nil {
+		return err
+	}
+	createQuery.Clauses[clause] = value
+
+	switch clause {
+	case "ORDER BY":
+		createQuery.OrderBy = SplitTuple(value)
+	case "PARTITION BY":
+		createQuery.PartitionBy = SplitTuple(value)
+	case "PRIMARY KEY":
+		createQuery.PrimaryKey = SplitTuple(value)
+	case "SAMPLE BY":
+		createQuery.SampleBy = value
+	case "TTL":
+		createQuery.TTL = value
+	case "SETTINGS":
+		// Dictionaries enclose their settings in parentheses
+		if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			value = value[1 : len(value)-1]
+		}
+		createQuery.Settings = parseSettings(value)
+	case "COMMENT":
+		createQuery.Comment = UnquoteString(value)
+	}
+	return nil
+}
+
+func (p *ddlParser) acceptSymbol(symbol string) bool {
+	if !p.isSymbol(symbol) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+func (p *ddlParser) engine() (*EngineDefinition, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	engine := EngineDefinition{Name: name, Params: make([]string, 0)}
+	if p.isSymbol("(") {
+		if engine.Params, err = p.list(); err != nil {
+			return nil, err
+		}
+	}
+	return &engine, nil
+}
+
+// columns parses the list of columns, indexes, projections and constraints
+func (p *ddlParser) columns(createQuery *CreateQuery) error {
+	p.next()
+	createQuery.Columns = make([]ColumnDefinition, 0)
+	for !p.isSymbol(")") {
+		isElementEnd := func() bool { return p.isSymbol(",") }
+		switch {
+		case p.acceptKeyword("INDEX"):
+			index, err := p.expression(isElementEnd)
+			if err != nil {
+				return err
+			}
+			createQuery.Indexes = append(createQuery.Indexes, index)
+		case p.acceptKeyword("PROJECTION"):
+			projection, err := p.expression(isElementEnd)
+			if err != nil {
+				return err
+			}
+			createQuery.Projections = append(createQuery.Projections, projection)
+		case p.acceptKeyword("CONSTRAINT"):
+			constraint, err := p.expression(isElementEnd)
+			if err != nil {
+				return err
+			}
+			createQuery.Constraints = append(createQuery.Constraints, constraint)
+		case p.acceptKeyword("PRIMARY", "KEY"):
+			primaryKey, err := p.expression(isElementEnd)
+			if err != nil {
+				return err
+			}
+			createQuery.PrimaryKey = SplitTuple(primaryKey)
+		default:
+			column, err := p.column()
+			if err != nil {
+				return err
+			}
+			createQuery.Columns = append(createQuery.Columns, column)
+		}
+		if !p.acceptSymbol(",") && !p.isSymbol(")") {
+			return p.unexpected(p.peek(), `"," or ")"`)
+		}
+	}
+	p.next()
+	return nil
+}
+
+func (p *ddlParser) column() (ColumnDefinition, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return ColumnDefinition{}, err
+	}
+	column := ColumnDefinition{Name: name, Attributes: make(map[string]string)}
+	isAttributeEnd := func() bool { return p.isSymbol(",") || p.columnAttribute() != "" }
+	if p.columnAttribute() == "" {
+		if column.Type, err = p.expression(isAttributeEnd); err != nil {
+			return ColumnDefinition{}, err
+		}
+	}
+
+	for {
+		attribute := p.columnAttribute()
+		if attribute == "" {
+			return column, nil
+		}
+		p.pos += len(strings.Split(attribute, " "))
+		value, err := p.expression(isAttributeEnd)
+		if err != nil {
+			return ColumnDefinition{}, err
+		}
+		switch attribute {
+		case "DEFAULT", "MATERIALIZED", "ALIAS", "EPHEMERAL":
+			column.DefaultKind = attribute
+			column.DefaultExpression = value
+		case "COMMENT":
+			column.Comment = UnquoteString(value)
+		case "CODEC":
+			column.Codec = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, "("), ")"))
+		case "TTL":
+			column.TTL = value
+		default:
+			column.Attributes[attribute] = value
+		}
+	}
+}
+
+// SplitTuple splits a tuple expression like "(a, cityHash64(b))" into its elements, other expressions are
+// returned as the only element. Empty tuples return no elements.
+func SplitTuple(expression string) []string {
+	expression = strings.TrimSpace(expression)
+	if expression == "" || expression == "tuple()" || expression == "()" {
+		return []string{}
+	}
+	tokens, err := tokenizeDDL(expression)
+	if err != nil || len(tokens) < 2 {
+		return []string{expression}
+	}
+	p := ddlParser{query: expression, tokens: tokens}
+	if strings.EqualFold(tokens[0].Text, "tuple") && tokens[0].Kind == ddlWord && tokens[1].Text == "(" {
+		p.next()
+	}
+	if !p.isSymbol("(") {
+		return []string{expression}
+	}
+	items, err := p.list()
+	if err != nil || p.peek().Kind != ddlEOF {
+		return []string{expression}
+	}
+	return items
+}
+
+// SplitTopLevel splits s by commas outside of quotes and parentheses
+func SplitTopLevel(s string) []string {
+	tokens, err := tokenizeDDL(s)
+	if err != nil {
+		return []string{strings.TrimSpace(s)}
+	}
+	p := ddlParser{query: s, tokens: tokens}
+	items := make([]string, 0)
+	for p.peek().Kind != ddlEOF {
+		item, err := p.expression(func() bool { return p.isSymbol(",") })
+		if err != nil || !p.acceptSymbol(",") && p.peek().Kind != ddlEOF {
+			return []string{strings.TrimSpace(s)}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// DefinitionParameter is a parameter of a definition like SOURCE(CLICKHOUSE(HOST 'localhost' PORT 9000)), its value is
+// kept as written, with the parentheses of nested definitions
+type DefinitionParameter struct {
+	Name  string
+	Value string
+}
+
+// SplitDefinitionParameters splits the parameters of a definition, like HOST 'localhost' CREDENTIALS(USER 'default'),
+// into names and values
+func SplitDefinitionParameters(s string) ([]DefinitionParameter, error) {
+	tokens, err := tokenizeDDL(s)
+	if err != nil {
+		return nil, err
+	}
+	p := ddlParser{query: s, tokens: tokens}
+	var parameters []DefinitionParameter
+	for p.peek().Kind != ddlEOF {
+		name := p.next()
+		if name.Kind != ddlWord {
+			return nil, p.unexpected(name, "a parameter name")
+		}
+		value := p.next()
+		switch {
+		case value.Kind == ddlEOF || value.Kind == ddlSymbol && value.Text != "(":
+			return nil, p.unexpected(value, "a parameter value")
+		case value.Kind == ddlSymbol:
+			for depth := 1; depth > 0; {
+				token := p.next()
+				switch {
+				case token.Kind == ddlEOF:
+					return nil, p.unexpected(token, "\")\"")
+				case token.Kind == ddlSymbol && token.Text == "(":
+					depth++
+				case token.Kind == ddlSymbol && token.Text == ")":
+					depth--
+				}
+			}
+		}
+		parameters = append(parameters, DefinitionParameter{Name: name.Text, Value: s[value.Start:p.tokens[p.pos-1].End]})
+	}
+	return parameters, nil
+}
+
+func parseSettings(settings string) map[string]string {
+	parsed := make(map[string]string)
+	for _, setting := range SplitTopLevel(settings) {
+		keyValue := strings.SplitN(setting, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		parsed[strings.TrimSpace(keyValue[0])] = UnquoteString(strings.TrimSpace(keyValue[1]))
+	}
+	return parsed
+}
+
+// UnquoteString removes the quotes and escapes of a string literal, other values are returned as they are
+func UnquoteString(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.NewReplacer(`\'`, `'`, `\\`, `\`, `''`, `'`).Replace(value[1 : len(value)-1])
+	}
+	return value
+}
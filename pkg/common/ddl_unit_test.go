@@ -0,0 +1,164 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCreateQuery_Table(t *testing.T) {
+	query := "CREATE TABLE dm.events UUID '5ba6f7d2-0d1e-4b52-9f6b-6a7d0e3c4f11' ON CLUSTER bi_cluster (" +
+		"`key` Int64, " +
+		"`ts` DateTime DEFAULT now() COMMENT 'event time', " +
+		"`region` LowCardinality(String), " +
+		"`value` Nullable(Decimal(10, 2)) CODEC(Delta, ZSTD(1)) TTL ts + toIntervalDay(7), " +
+		"`day` Date MATERIALIZED toDate(ts), " +
+		"INDEX idx_value value TYPE minmax GRANULARITY 4" +
+		") ENGINE = ReplicatedReplacingMergeTree('/clickhouse/tables/{shard}/dm/events', '{replica}', ts) " +
+		"PARTITION BY (toYYYYMM(ts), region) PRIMARY KEY key ORDER BY (key, cityHash64(region)) SAMPLE BY key " +
+		"TTL ts + toIntervalMonth(1) TO VOLUME 'cold', ts + toIntervalYear(1) " +
+		"SETTINGS index_granularity = 8192, storage_policy = 'tiered' COMMENT 'it''s events'"
+
+	got, err := ParseCreateQuery(query)
+	if err != nil {
+		t.Fatalf("ParseCreateQuery() unexpected error: %v", err)
+	}
+
+	want := &CreateQuery{
+		Kind:     "TABLE",
+		Database: "dm",
+		Name:     "events",
+		UUID:     "5ba6f7d2-0d1e-4b52-9f6b-6a7d0e3c4f11",
+		Cluster:  "bi_cluster",
+		Columns: []ColumnDefinition{
+			{Name: "key", Type: "Int64", Attributes: map[string]string{}},
+			{Name: "ts", Type: "DateTime", DefaultKind: "DEFAULT", DefaultExpression: "now()", Comment: "event time", Attributes: map[string]string{}},
+			{Name: "region", Type: "LowCardinality(String)", Attributes: map[string]string{}},
+			{Name: "value", Type: "Nullable(Decimal(10, 2))", Codec: "Delta, ZSTD(1)", TTL: "ts + toIntervalDay(7)", Attributes: map[string]string{}},
+			{Name: "day", Type: "Date", DefaultKind: "MATERIALIZED", DefaultExpression: "toDate(ts)", Attributes: map[string]string{}},
+		},
+		Indexes: []string{"idx_value value TYPE minmax GRANULARITY 4"},
+		Engine: &EngineDefinition{
+			Name:   "ReplicatedReplacingMergeTree",
+			Params: []string{"'/clickhouse/tables/{shard}/dm/events'", "'{replica}'", "ts"},
+		},
+		OrderBy:     []string{"key", "cityHash64(region)"},
+		PartitionBy: []string{"toYYYYMM(ts)", "region"},
+		PrimaryKey:  []string{"key"},
+		SampleBy:    "key",
+		TTL:         "ts + toIntervalMonth(1) TO VOLUME 'cold', ts + toIntervalYear(1)",
+		Settings:    map[string]string{"index_granularity": "8192", "storage_policy": "tiered"},
+		Comment:     "it's events",
+		Clauses: map[string]string{
+			"PARTITION BY": "(toYYYYMM(ts), region)",
+			"PRIMARY KEY":  "key",
+			"ORDER BY":     "(key, cityHash64(region))",
+			"SAMPLE BY":    "key",
+			"TTL":          "ts + toIntervalMonth(1) TO VOLUME 'cold', ts + toIntervalYear(1)",
+			"SETTINGS":     "index_granularity = 8192, storage_policy = 'tiered'",
+			"COMMENT":      "'it''s events'",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCreateQuery() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCreateQuery_MaterializedView(t *testing.T) {
+	query := "CREATE MATERIALIZED VIEW dm.events_mv REFRESH EVERY 1 HOUR DEPENDS ON dm.events TO dm.events_daily " +
+		"(`day` Date, `total` UInt64) AS SELECT toDate(ts) AS day, count() AS total FROM dm.events GROUP BY day " +
+		"SETTINGS max_threads = 1 COMMENT 'daily'"
+
+	got, err := ParseCreateQuery(query)
+	if err != nil {
+		t.Fatalf("ParseCreateQuery() unexpected error: %v", err)
+	}
+	if got.Kind != "MATERIALIZED VIEW" || got.To != "dm.events_daily" || got.Comment != "daily" {
+		t.Errorf("ParseCreateQuery() kind = %q, to = %q, comment = %q", got.Kind, got.To, got.Comment)
+	}
+	if refresh := got.Clauses["REFRESH"]; refresh != "EVERY 1 HOUR DEPENDS ON dm.events" {
+		t.Errorf("ParseCreateQuery() refresh = %q", refresh)
+	}
+	if want := "SELECT toDate(ts) AS day, count() AS total FROM dm.events GROUP BY day SETTINGS max_threads = 1"; got.Select != want {
+		t.Errorf("ParseCreateQuery() select = %q, want %q", got.Select, want)
+	}
+	if len(got.Columns) != 2 {
+		t.Errorf("ParseCreateQuery() columns = %+v", got.Columns)
+	}
+}
+
+func TestParseCreateQuery_Errors(t *testing.T) {
+	tests := []string{
+		"SELECT 1",
+		"CREATE DATABASE dm",
+		"CREATE TABLE dm.events (`key` Int64 ENGINE = MergeTree",
+		"CREATE TABLE dm.events (`key` Int64) ENGINE = MergeTree ORDER BY (key",
+		"CREATE TABLE dm.events (`key` Int64) ENGINE = MergeTree WHATEVER key",
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseCreateQuery(tt); err == nil {
+			t.Errorf("ParseCreateQuery(%q) expected an error", tt)
+		}
+	}
+}
+
+func TestParseEngineFull(t *testing.T) {
+	got, err := ParseEngineFull("Distributed('bi_cluster', 'dm', 'events_local', cityHash64(key))")
+	if err != nil {
+		t.Fatalf("ParseEngineFull() unexpected error: %v", err)
+	}
+	want := &EngineDefinition{Name: "Distributed", Params: []string{"'bi_cluster'", "'dm'", "'events_local'", "cityHash64(key)"}}
+	if !reflect.DeepEqual(got.Engine, want) {
+		t.Errorf("ParseEngineFull() engine = %+v, want %+v", got.Engine, want)
+	}
+
+	got, err = ParseEngineFull("MergeTree ORDER BY tuple() SETTINGS index_granularity = 8192")
+	if err != nil {
+		t.Fatalf("ParseEngineFull() unexpected error: %v", err)
+	}
+	if len(got.OrderBy) != 0 || got.Settings["index_granularity"] != "8192" || len(got.Engine.Params) != 0 {
+		t.Errorf("ParseEngineFull() = %+v", got)
+	}
+}
+
+func TestSplitDefinitionParameters(t *testing.T) {
+	got, err := SplitDefinitionParameters("HOST 'localhost' PORT 9000 NAME `pg prices` CREDENTIALS(USER 'u' PASSWORD '[HIDDEN]')")
+	if err != nil {
+		t.Fatalf("SplitDefinitionParameters() unexpected error: %v", err)
+	}
+	want := []DefinitionParameter{
+		{Name: "HOST", Value: "'localhost'"},
+		{Name: "PORT", Value: "9000"},
+		{Name: "NAME", Value: "`pg prices`"},
+		{Name: "CREDENTIALS", Value: "(USER 'u' PASSWORD '[HIDDEN]')"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitDefinitionParameters() = %q, want %q", got, want)
+	}
+
+	for _, s := range []string{"HOST", "HOST 'localhost' PORT", "CREDENTIALS(USER 'u'", "'localhost' HOST"} {
+		if _, err := SplitDefinitionParameters(s); err == nil {
+			t.Errorf("SplitDefinitionParameters(%q) expected an error", s)
+		}
+	}
+}
+
+func TestSplitTuple(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"key", []string{"key"}},
+		{"(key, cityHash64(value))", []string{"key", "cityHash64(value)"}},
+		{"tuple(key, value)", []string{"key", "value"}},
+		{"tuple()", []string{}},
+		{"toYYYYMM(ts)", []string{"toYYYYMM(ts)"}},
+		{"(a + 1) * 2", []string{"(a + 1) * 2"}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitTuple(tt.expression); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitTuple(%q) = %q, want %q", tt.expression, got, tt.want)
+		}
+	}
+}
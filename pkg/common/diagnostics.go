@@ -0,0 +1,42 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// DiagnosticsToError turns error diagnostics into an error, each one prefixed by its attribute path,
+// so plan time validations in CustomizeDiff point at the offending attribute
+func DiagnosticsToError(diags diag.Diagnostics) error {
+	var result *multierror.Error
+	for _, diagnostic := range diags {
+		if diagnostic.Severity != diag.Error {
+			continue
+		}
+		result = multierror.Append(result, fmt.Errorf("%s: %s", FormatAttributePath(diagnostic.AttributePath), diagnostic.Detail))
+	}
+	return result.ErrorOrNil()
+}
+
+// FormatAttributePath renders a path the way it is written in state, e.g. partition_by.0.by
+func FormatAttributePath(path cty.Path) string {
+	steps := make([]string, 0, len(path))
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			steps = append(steps, step.Name)
+		case cty.IndexStep:
+			if step.Key.Type() == cty.Number {
+				index, _ := step.Key.AsBigFloat().Int64()
+				steps = append(steps, fmt.Sprintf("%d", index))
+			} else {
+				steps = append(steps, step.Key.AsString())
+			}
+		}
+	}
+	return strings.Join(steps, ".")
+}
@@ -0,0 +1,219 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expressionKeywords are the words of an expression that are neither columns nor functions
+var expressionKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true, "LIKE": true, "ILIKE": true,
+	"BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true, "INTERVAL": true,
+	"AS": true, "TRUE": true, "FALSE": true, "DISTINCT": true, "GLOBAL": true, "ASC": true, "DESC": true,
+}
+
+// ExpressionColumns returns the columns referenced by an expression, in order of appearance.
+// Function names, keywords, literals, interval units, cast types and lambda parameters are skipped.
+// Compound names like "nested.field" are returned as written.
+func ExpressionColumns(expression string) ([]string, error) {
+	tokens, err := tokenizeDDL(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	seen := make(map[string]bool)
+	lambdaParams := make(map[string]bool)
+	depth := 0
+	for i := 0; i < len(tokens)-1; i++ {
+		token := tokens[i]
+		switch token.Kind {
+		case ddlSymbol:
+			switch token.Text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+				if depth < 0 {
+					return nil, fmt.Errorf("unexpected %q at position %d", token.Text, token.Start)
+				}
+			case ";":
+				return nil, fmt.Errorf("unexpected %q at position %d", token.Text, token.Start)
+			}
+			continue
+		case ddlWord, ddlQuotedIdentifier:
+		default:
+			continue
+		}
+
+		next := tokens[i+1]
+		if token.Kind == ddlWord {
+			if next.Kind == ddlSymbol && next.Text == "(" || expressionKeywords[strings.ToUpper(token.Text)] {
+				continue
+			}
+			if i > 0 {
+				// INTERVAL 1 DAY and CAST(x AS Type)
+				previous := tokens[i-1]
+				if previous.Kind == ddlNumber || previous.Kind == ddlWord && strings.EqualFold(previous.Text, "AS") {
+					continue
+				}
+			}
+		}
+		if next.Kind == ddlSymbol && next.Text == "-" && tokens[i+2].Kind == ddlSymbol && tokens[i+2].Text == ">" {
+			lambdaParams[token.Text] = true
+			continue
+		}
+
+		name := token.Text
+		for i+2 < len(tokens) && tokens[i+1].Kind == ddlSymbol && tokens[i+1].Text == "." &&
+			(tokens[i+2].Kind == ddlWord || tokens[i+2].Kind == ddlQuotedIdentifier) {
+			name += "." + tokens[i+2].Text
+			i += 2
+		}
+		if !seen[name] {
+			seen[name] = true
+			columns = append(columns, name)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+
+	result := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !lambdaParams[column] {
+			result = append(result, column)
+		}
+	}
+	return result, nil
+}
+
+// queryKeywords are the keywords the server uppercases when it formats a query
+var queryKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "PREWHERE": true, "GROUP": true, "BY": true, "ORDER": true,
+	"HAVING": true, "LIMIT": true, "OFFSET": true, "WITH": true, "UNION": true, "ALL": true, "JOIN": true,
+	"ON": true, "USING": true, "LEFT": true, "RIGHT": true, "INNER": true, "OUTER": true, "FULL": true,
+	"CROSS": true, "ANY": true, "ARRAY": true, "SETTINGS": true, "FORMAT": true, "FINAL": true, "SAMPLE": true,
+	"EXCEPT": true, "INTERSECT": true, "TOTALS": true, "ROLLUP": true, "CUBE": true, "FILL": true, "TIES": true,
+}
+
+// NormalizeQuery renders a query in a canonical form, so a query as written and as formatted by the server
+// (e.g. system.tables as_select) compare equal: comments, whitespace, identifier quotes, keyword case,
+// trailing semicolons and the parentheses the server adds around the operands of AND, OR and NOT are ignored.
+func NormalizeQuery(query string) string {
+	tokens, err := tokenizeDDL(query)
+	if err != nil {
+		return strings.Join(strings.Fields(query), " ")
+	}
+	for len(tokens) > 1 && tokens[len(tokens)-2].Kind == ddlSymbol && tokens[len(tokens)-2].Text == ";" {
+		tokens = append(tokens[:len(tokens)-2], tokens[len(tokens)-1])
+	}
+	tokens = stripRedundantParentheses(tokens)
+
+	parts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		switch token.Kind {
+		case ddlEOF:
+			continue
+		case ddlWord:
+			if upper := strings.ToUpper(token.Text); queryKeywords[upper] || expressionKeywords[upper] {
+				parts = append(parts, upper)
+				continue
+			}
+		}
+		parts = append(parts, token.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// clauseKeywords are the keywords an expression can start or end at
+var clauseKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "PREWHERE": true, "BY": true, "HAVING": true, "LIMIT": true,
+	"ON": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true, "SETTINGS": true, "UNION": true, "FORMAT": true,
+}
+
+// stripRedundantParentheses drops parentheses around an expression when its neighbours bind looser than it, like the
+// ones the server adds in (a = 1) AND (b = 2). Parentheses of function calls, tuples, subqueries and the ones changing
+// the precedence, like (a OR b) AND c, are kept.
+func stripRedundantParentheses(tokens []ddlToken) []ddlToken {
+	for stripped := true; stripped; {
+		stripped = false
+		var opened []int
+		for i, token := range tokens {
+			if token.Kind != ddlSymbol {
+				continue
+			}
+			if token.Text == "(" {
+				opened = append(opened, i)
+				continue
+			}
+			if token.Text != ")" || len(opened) == 0 {
+				continue
+			}
+			start := opened[len(opened)-1]
+			opened = opened[:len(opened)-1]
+			if parenthesesRedundant(tokens, start, i) {
+				tokens = append(append(append([]ddlToken{}, tokens[:start]...), tokens[start+1:i]...), tokens[i+1:]...)
+				stripped = true
+				break
+			}
+		}
+	}
+	return tokens
+}
+
+// parenthesesRedundant tells if the parentheses at start and end can be dropped: the operator of lowest precedence
+// between them, none, NOT, AND or OR, must bind at least as tight as the operators next to them
+func parenthesesRedundant(tokens []ddlToken, start int, end int) bool {
+	if end == start+1 {
+		return false
+	}
+	// 0 for comparisons and other operators binding tighter than NOT, 1 for AND and 2 for OR
+	level := 0
+	depth := 0
+	for i := start + 1; i < end; i++ {
+		token := tokens[i]
+		switch {
+		case token.Kind == ddlSymbol && (token.Text == "(" || token.Text == "["):
+			depth++
+		case token.Kind == ddlSymbol && (token.Text == ")" || token.Text == "]"):
+			depth--
+		case depth > 0:
+		case token.Kind == ddlSymbol && (token.Text == "," || token.Text == "?" || token.Text == ">" && tokens[i-1].Text == "-"):
+			return false
+		case token.Kind == ddlWord:
+			switch strings.ToUpper(token.Text) {
+			case "SELECT", "WITH":
+				return false
+			case "AND":
+				if level == 0 {
+					level = 1
+				}
+			case "OR":
+				level = 2
+			}
+		}
+	}
+
+	bindsLooser := func(token ddlToken) bool {
+		switch token.Kind {
+		case ddlEOF:
+			return true
+		case ddlSymbol:
+			return token.Text == "(" || token.Text == ")" || token.Text == ","
+		case ddlWord:
+			switch upper := strings.ToUpper(token.Text); upper {
+			case "OR":
+				return true
+			case "AND":
+				return level <= 1
+			case "NOT":
+				return level == 0
+			default:
+				return clauseKeywords[upper]
+			}
+		}
+		return false
+	}
+	return (start == 0 || bindsLooser(tokens[start-1])) && bindsLooser(tokens[end+1])
+}
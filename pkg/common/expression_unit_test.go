@@ -0,0 +1,82 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpressionColumns(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"ts", []string{"ts"}},
+		{"toMonday(ts)", []string{"ts"}},
+		{"intDiv(id, 1000000)", []string{"id"}},
+		{"(toYYYYMM(ts), region)", []string{"ts", "region"}},
+		{"tuple(toStartOfInterval(ts, INTERVAL 1 DAY), `event type`)", []string{"ts", "event type"}},
+		{"cityHash64(user_id) % 16", []string{"user_id"}},
+		{"CAST(id AS String)", []string{"id"}},
+		{"arrayMap(x -> x * 2, values)", []string{"values"}},
+		{"if(status = 'ok', 1, 0)", []string{"status"}},
+		{"nested.field", []string{"nested.field"}},
+		{"toYYYYMM(ts), ts", []string{"ts"}},
+		{"tuple()", []string{}},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpressionColumns(tt.expression)
+		if err != nil {
+			t.Errorf("ExpressionColumns(%q) unexpected error: %v", tt.expression, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpressionColumns(%q) = %q, want %q", tt.expression, got, tt.want)
+		}
+	}
+
+	for _, expression := range []string{"toMonday(ts", "ts)", "ts; DROP TABLE x", "'unterminated"} {
+		if _, err := ExpressionColumns(expression); err == nil {
+			t.Errorf("ExpressionColumns(%q) expected an error", expression)
+		}
+	}
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		written   string
+		formatted string
+	}{
+		{"select a, b\nfrom db.events\nwhere x = 1;", "SELECT a, b FROM db.events WHERE x = 1"},
+		{"SELECT count() AS c FROM `db`.`events` -- totals\nGROUP BY k", "SELECT count() AS c FROM db.events GROUP BY k"},
+		{"select * from db.events where user = {user:String}", "SELECT * FROM db.events WHERE user = {user:String}"},
+		{"select a from db.events where a = 1 and b = 2", "SELECT a FROM db.events WHERE (a = 1) AND (b = 2)"},
+		{"a = 1 AND b = 2 OR NOT c = 3", "((a = 1) AND (b = 2)) OR (NOT (c = 3))"},
+		{"(a = 1 OR b = 2) AND c IN (1, 2)", "((a = 1) OR (b = 2)) AND (c IN (1, 2))"},
+		{"if(a = 1 AND b, x, y)", "if((a = 1) AND b, x, y)"},
+	}
+
+	for _, tt := range tests {
+		if got, want := NormalizeQuery(tt.written), NormalizeQuery(tt.formatted); got != want {
+			t.Errorf("NormalizeQuery(%q) = %q, want %q", tt.written, got, want)
+		}
+	}
+	if NormalizeQuery("SELECT 'a'") == NormalizeQuery("SELECT 'A'") {
+		t.Errorf("NormalizeQuery() should keep string literals as written")
+	}
+	if NormalizeQuery("SELECT A FROM t") == NormalizeQuery("SELECT a FROM t") {
+		t.Errorf("NormalizeQuery() should keep identifiers case")
+	}
+
+	for _, pair := range [][2]string{
+		{"(a OR b) AND c", "a OR b AND c"},
+		{"(a + b) * c", "a + b * c"},
+		{"NOT (a AND b)", "NOT a AND b"},
+		{"(a ? b : c) AND d", "a ? b : c AND d"},
+		{"x IN (1)", "x IN 1"},
+	} {
+		if NormalizeQuery(pair[0]) == NormalizeQuery(pair[1]) {
+			t.Errorf("NormalizeQuery() should keep the parentheses of %q", pair[0])
+		}
+	}
+}
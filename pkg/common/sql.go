@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var identifierEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// QuoteIdentifier backtick-quotes a database, table, column, user or role name,
+// so names with dashes, spaces or reserved words can be used and can't inject SQL
+func QuoteIdentifier(name string) string {
+	return "`" + identifierEscaper.Replace(name) + "`"
+}
+
+// QuoteIdentifiers quotes every name with QuoteIdentifier
+func QuoteIdentifiers(names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, QuoteIdentifier(name))
+	}
+	return quoted
+}
+
+// QuoteTableName quotes a database qualified name, like database.table
+func QuoteTableName(database string, name string) string {
+	return QuoteIdentifier(database) + "." + QuoteIdentifier(name)
+}
+
+// QuoteString renders value as a single quoted string literal
+func QuoteString(value string) string {
+	return "'" + stringEscaper.Replace(value) + "'"
+}
+
+var numericRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// settingNameRegex matches setting names, they are written unquoted in SETTINGS, MODIFY SETTING and RESET SETTING
+var settingNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateSettingNames rejects settings maps whose names are not plain identifiers, so they can't inject SQL
+var ValidateSettingNames = validation.MapKeyMatch(settingNameRegex, "setting names can only contain letters, digits and underscores")
+
+// BuildSettingsSentence renders settings as "key = value" pairs sorted by name, non numeric values are quoted
+func BuildSettingsSentence(settings map[string]string) string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assignments := make([]string, 0, len(names))
+	for _, name := range names {
+		value := settings[name]
+		if !numericRegex.MatchString(value) {
+			value = QuoteString(value)
+		}
+		assignments = append(assignments, fmt.Sprintf("%s = %s", name, value))
+	}
+	return strings.Join(assignments, ", ")
+}
+
+// QueryParameters binds server-side query parameters to the context. Parameters are referenced in the query
+// as {name:Type}, so lookups in system tables never interpolate user values into the SQL text.
+func QueryParameters(ctx context.Context, params map[string]string) context.Context {
+	return clickhouse.Context(ctx, clickhouse.WithParameters(clickhouse.Parameters(params)))
+}
+
+// GetClusterStatement returns the ON CLUSTER clause for cluster, or nothing when it is empty
+func GetClusterStatement(cluster string) (clusterStatement string) {
+	if cluster != "" {
+		return fmt.Sprintf("ON CLUSTER %s", QuoteIdentifier(cluster))
+	}
+	return ""
+}
+
+// SplitQualifiedName splits a name like database.table, either part possibly quoted, into its unquoted parts
+func SplitQualifiedName(name string) []string {
+	tokens, err := tokenizeDDL(name)
+	if err != nil {
+		return strings.SplitN(name, ".", 2)
+	}
+	var parts []string
+	expectName := true
+	for _, token := range tokens {
+		switch {
+		case token.Kind == ddlEOF:
+		case expectName && (token.Kind == ddlWord || token.Kind == ddlQuotedIdentifier || token.Kind == ddlNumber):
+			parts = append(parts, token.Text)
+			expectName = false
+		case !expectName && token.Kind == ddlSymbol && token.Text == ".":
+			expectName = true
+		default:
+			return strings.SplitN(name, ".", 2)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,91 @@
+package common
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"events", "`events`"},
+		{"my-table", "`my-table`"},
+		{"select", "`select`"},
+		{"evil` DROP TABLE x; --", "`evil\\` DROP TABLE x; --`"},
+		{"back\\`", "`back\\\\\\``"},
+	}
+
+	for _, tt := range tests {
+		if got := QuoteIdentifier(tt.name); got != tt.want {
+			t.Errorf("QuoteIdentifier(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if got := QuoteTableName("my-db", "events"); got != "`my-db`.`events`" {
+		t.Errorf("QuoteTableName() = %s", got)
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"tiered", "'tiered'"},
+		{"it's", "'it\\'s'"},
+		{"x' OR '1'='1", "'x\\' OR \\'1\\'=\\'1'"},
+		{"trailing\\", "'trailing\\\\'"},
+		{"\\' injected", "'\\\\\\' injected'"},
+	}
+
+	for _, tt := range tests {
+		got := QuoteString(tt.value)
+		if got != tt.want {
+			t.Errorf("QuoteString(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+		if unquoted := UnquoteString(got); unquoted != tt.value {
+			t.Errorf("UnquoteString(QuoteString(%q)) = %q", tt.value, unquoted)
+		}
+	}
+}
+
+func TestBuildSettingsSentence(t *testing.T) {
+	settings := map[string]string{"storage_policy": "tiered", "index_granularity": "8192", "ratio": "-0.5", "comment": "it's"}
+	want := "comment = 'it\\'s', index_granularity = 8192, ratio = -0.5, storage_policy = 'tiered'"
+	if got := BuildSettingsSentence(settings); got != want {
+		t.Errorf("BuildSettingsSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestValidateSettingNames(t *testing.T) {
+	if diags := ValidateSettingNames(map[string]interface{}{"index_granularity": "8192", "_custom2": "x"}, nil); diags.HasError() {
+		t.Errorf("ValidateSettingNames() unexpected error: %v", diags)
+	}
+	for _, name := range []string{"index_granularity = 1, ttl_only_drop_parts", "storage policy", "2pc", ""} {
+		if diags := ValidateSettingNames(map[string]interface{}{name: "1"}, nil); !diags.HasError() {
+			t.Errorf("ValidateSettingNames(%q) expected an error", name)
+		}
+	}
+}
+
+func TestSplitQualifiedName(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"events", []string{"events"}},
+		{"db.events", []string{"db", "events"}},
+		{"`my-db`.`my.table`", []string{"my-db", "my.table"}},
+	}
+
+	for _, tt := range tests {
+		got := SplitQualifiedName(tt.name)
+		if len(got) != len(tt.want) {
+			t.Errorf("SplitQualifiedName(%q) = %q, want %q", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SplitQualifiedName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		}
+	}
+}
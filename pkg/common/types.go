@@ -7,4 +7,6 @@ import (
 type ApiClient struct {
 	ClickhouseConnection *driver.Conn
 	DefaultCluster       string
+	// ReplicationPathTemplate is the ZooKeeper path used by Replicated tables when none is given
+	ReplicationPathTemplate string
 }
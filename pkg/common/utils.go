@@ -7,32 +7,39 @@ import (
 	"strings"
 )
 
-func GetComment(comment string, cluster string) string {
-	storingComment := fmt.Sprintf(`{"comment":"%v","cluster":"%v"}`, comment, cluster)
-	storingComment = strings.Replace(storingComment, "'", "\\'", -1)
-	return storingComment
-}
-
-func UnmarshalComment(storedComment string) (comment string, cluster string, err error) {
-	if storedComment == "" {
-		return "", "", nil
-	}
-	storedComment = strings.Replace(storedComment, "\\'", "'", -1)
-	byteStreamComment := []byte(storedComment)
-	var dat map[string]interface{}
-	if err := json.Unmarshal(byteStreamComment, &dat); err != nil {
-		return "", "", err
-	}
-	comment = dat["comment"].(string)
-	cluster = dat["cluster"].(string)
-	return comment, cluster, nil
+// ParseLegacyComment decodes comments stored by older provider versions, which wrapped the user comment
+// in a json along with the cluster, like {"comment":"...","cluster":"..."}. Other comments are returned
+// verbatim with ok set to false, as comments are now stored as given.
+func ParseLegacyComment(storedComment string) (comment string, cluster string, ok bool) {
+	var legacy map[string]interface{}
+	if err := json.Unmarshal([]byte(storedComment), &legacy); err != nil {
+		return storedComment, "", false
+	}
+	comment, commentOk := legacy["comment"].(string)
+	cluster, clusterOk := legacy["cluster"].(string)
+	if !commentOk || !clusterOk || len(legacy) != 2 {
+		return storedComment, "", false
+	}
+	return comment, cluster, true
 }
 
-func GetClusterStatement(cluster string) (clusterStatement string) {
-	if cluster != "" {
-		return fmt.Sprintf("ON CLUSTER %s", cluster)
+// UpgradeLegacyCommentState decodes a json comment kept in the state of resources created by older provider
+// versions, the cluster is taken from it when the state has none. Only the state is rewritten: the comment
+// on the server is decoded on read until the resource is updated or recreated.
+func UpgradeLegacyCommentState(rawState map[string]interface{}) map[string]interface{} {
+	if rawState == nil {
+		return rawState
+	}
+	storedComment, _ := rawState["comment"].(string)
+	comment, cluster, ok := ParseLegacyComment(storedComment)
+	if !ok {
+		return rawState
 	}
-	return ""
+	rawState["comment"] = comment
+	if stateCluster, _ := rawState["cluster"].(string); stateCluster == "" {
+		rawState["cluster"] = cluster
+	}
+	return rawState
 }
 
 func Quote(elems []string) []string {
@@ -58,3 +65,13 @@ func StringListToSet(list []string) *schema.Set {
 	}
 	return schema.NewSet(schema.HashString, set)
 }
+
+// ParseImportId splits a composite import id like "cluster:database:table" into its parts.
+// Leading parts may be empty (e.g. ":database:table" for non clustered resources) but the last one may not.
+func ParseImportId(id string, parts ...string) ([]string, error) {
+	values := strings.Split(id, ":")
+	if len(values) != len(parts) || values[len(values)-1] == "" {
+		return nil, fmt.Errorf("unexpected import id %q, expected format %q", id, strings.Join(parts, ":"))
+	}
+	return values, nil
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLegacyComment(t *testing.T) {
+	tests := []struct {
+		stored      string
+		wantComment string
+		wantCluster string
+		wantOk      bool
+	}{
+		{`{"comment":"events table","cluster":"bi_cluster"}`, "events table", "bi_cluster", true},
+		{`{"comment":"","cluster":""}`, "", "", true},
+		{"events table", "events table", "", false},
+		{`{"comment":"events table"}`, `{"comment":"events table"}`, "", false},
+		{`{"comment":"a","cluster":"b","owner":"c"}`, `{"comment":"a","cluster":"b","owner":"c"}`, "", false},
+		{`it's "quoted" \ text`, `it's "quoted" \ text`, "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		comment, cluster, ok := ParseLegacyComment(tt.stored)
+		if comment != tt.wantComment || cluster != tt.wantCluster || ok != tt.wantOk {
+			t.Errorf("ParseLegacyComment(%q) = %q, %q, %v, want %q, %q, %v", tt.stored, comment, cluster, ok, tt.wantComment, tt.wantCluster, tt.wantOk)
+		}
+	}
+}
+
+func TestUpgradeLegacyCommentState(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawState map[string]interface{}
+		want     map[string]interface{}
+	}{
+		{
+			name:     "legacy comment",
+			rawState: map[string]interface{}{"name": "events", "comment": `{"comment":"events table","cluster":"bi_cluster"}`},
+			want:     map[string]interface{}{"name": "events", "comment": "events table", "cluster": "bi_cluster"},
+		},
+		{
+			name:     "legacy comment with cluster in the state",
+			rawState: map[string]interface{}{"comment": `{"comment":"events table","cluster":"bi_cluster"}`, "cluster": "other"},
+			want:     map[string]interface{}{"comment": "events table", "cluster": "other"},
+		},
+		{
+			name:     "verbatim comment",
+			rawState: map[string]interface{}{"comment": "events table", "cluster": ""},
+			want:     map[string]interface{}{"comment": "events table", "cluster": ""},
+		},
+		{
+			name:     "no comment",
+			rawState: map[string]interface{}{"name": "events"},
+			want:     map[string]interface{}{"name": "events"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpgradeLegacyCommentState(tt.rawState); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpgradeLegacyCommentState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
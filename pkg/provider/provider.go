@@ -10,9 +10,17 @@ import (
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/datasources"
 	resourcedb "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/db"
+	resourcedictionary "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/dictionary"
+	resourcefunction "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/function"
+	resourcegrant "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/grant"
+	resourcequota "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/quota"
 	resourcerole "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/role"
+	resourcerolegrant "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/rolegrant"
+	resourcerowpolicy "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/rowpolicy"
+	resourcesettingsprofile "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/settingsprofile"
 	resourcetable "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/table"
 	resourceuser "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/user"
+	resourceview "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/view"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/joho/godotenv"
@@ -44,6 +52,12 @@ func New(version string) func() *schema.Provider {
 					Optional:    true,
 					Default:     "",
 				},
+				"replication_path_template": {
+					Description: "ZooKeeper path template for Replicated tables without explicit replication params, e.g. `/clickhouse/tables/{shard}/{database}/{table}`. `{database}` and `{table}` are replaced by the provider, other macros like `{shard}` or `{uuid}` are resolved by the server",
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "",
+				},
 				"username": {
 					Description: "Clickhouse username with admin privileges",
 					Type:        schema.TypeString,
@@ -92,11 +106,20 @@ func New(version string) func() *schema.Provider {
 				"clickhouse_dbs": datasources.DataSourceDbs(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
-				"clickhouse_db":               resourcedb.ResourceDb(),
-				"clickhouse_table":            resourcetable.ResourceTable(),
-				"clickhouse_postgresql_table": resourcetable.ResourcePostgreSQLTable(),
-				"clickhouse_role":             resourcerole.ResourceRole(),
-				"clickhouse_user":             resourceuser.ResourceUser(),
+				"clickhouse_db":                resourcedb.ResourceDb(),
+				"clickhouse_table":             resourcetable.ResourceTable(),
+				"clickhouse_postgresql_table":  resourcetable.ResourcePostgreSQLTable(),
+				"clickhouse_role":              resourcerole.ResourceRole(),
+				"clickhouse_user":              resourceuser.ResourceUser(),
+				"clickhouse_view":              resourceview.ResourceView(),
+				"clickhouse_materialized_view": resourceview.ResourceMaterializedView(),
+				"clickhouse_dictionary":        resourcedictionary.ResourceDictionary(),
+				"clickhouse_function":          resourcefunction.ResourceFunction(),
+				"clickhouse_settings_profile":  resourcesettingsprofile.ResourceSettingsProfile(),
+				"clickhouse_quota":             resourcequota.ResourceQuota(),
+				"clickhouse_row_policy":        resourcerowpolicy.ResourceRowPolicy(),
+				"clickhouse_grant":             resourcegrant.ResourceGrant(),
+				"clickhouse_role_grant":        resourcerolegrant.ResourceRoleGrant(),
 			},
 			ConfigureContextFunc: configure(),
 		}
@@ -121,6 +144,7 @@ func configure() func(context.Context, *schema.ResourceData) (any, diag.Diagnost
 		port := d.Get("port").(int)
 		username := d.Get("username").(string)
 		defaultCluster := d.Get("default_cluster").(string)
+		replicationPathTemplate := d.Get("replication_path_template").(string)
 		password := d.Get("password").(string)
 		secure := d.Get("secure").(bool)
 
@@ -153,6 +177,10 @@ func configure() func(context.Context, *schema.ResourceData) (any, diag.Diagnost
 			return nil, diag.FromErr(fmt.Errorf("ping clickhouse database: %w", err))
 		}
 
-		return &common.ApiClient{ClickhouseConnection: &conn, DefaultCluster: defaultCluster}, diags
+		return &common.ApiClient{
+			ClickhouseConnection:    &conn,
+			DefaultCluster:          defaultCluster,
+			ReplicationPathTemplate: replicationPathTemplate,
+		}, diags
 	}
 }
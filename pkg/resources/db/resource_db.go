@@ -12,13 +12,18 @@ import (
 )
 
 func ResourceDb() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		// This description is used by the documentation generator and the language server.
 		Description: "Resource to handle clickhouse databases.",
 
+		SchemaVersion: 1,
+
 		CreateContext: resourceDbCreate,
 		ReadContext:   resourceDbRead,
 		DeleteContext: resourceDbDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDbImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cluster": &schema.Schema{
@@ -62,6 +67,13 @@ func ResourceDb() *schema.Resource {
 			},
 		},
 	}
+	// Version 0 stored the comment along with the cluster in a json
+	resource.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    resource.CoreConfigSchema().ImpliedType(),
+		Upgrade: upgradeDbStateV0,
+	}}
+	return resource
 }
 
 func resourceDbRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -71,7 +83,10 @@ func resourceDbRead(ctx context.Context, d *schema.ResourceData, meta any) diag.
 	defaultCluster := client.DefaultCluster
 
 	database_name := d.Get("name").(string)
-	row := conn.QueryRow(ctx, fmt.Sprintf("SELECT name, engine, data_path, metadata_path, uuid, comment FROM system.databases where name = '%v'", database_name))
+	row := conn.QueryRow(
+		common.QueryParameters(ctx, map[string]string{"name": database_name}),
+		"SELECT name, engine, data_path, metadata_path, uuid, comment FROM system.databases where name = {name:String}",
+	)
 
 	if row.Err() != nil {
 		return diag.FromErr(fmt.Errorf("reading database from Clickhouse: %v", row.Err()))
@@ -93,14 +108,11 @@ func resourceDbRead(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diags
 	}
 
-	comment, cluster, err := common.UnmarshalComment(storedComment)
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Warning,
-			Summary:  fmt.Sprintf("Unable to unmarshal comments for db %q", name),
-			Detail:   "Unable to unmarshal comments in order to retrieve cluster information for the table, so that default cluster is going to be used instead.",
-		})
-		comment, cluster = storedComment, defaultCluster
+	// Comments are stored verbatim and the cluster is kept in the state (or given by the import id),
+	// databases created by older provider versions still carry both in a json comment.
+	comment, cluster, _ := common.ParseLegacyComment(storedComment)
+	if cluster == "" {
+		cluster = d.Get("cluster").(string)
 	}
 
 	err = d.Set("name", name)
@@ -153,6 +165,10 @@ func resourceDbRead(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		})
 	}
 
+	// Databases created without cluster were created on the default one
+	if cluster == "" {
+		cluster = defaultCluster
+	}
 	d.SetId(cluster + ":" + database_name)
 
 	tflog.Trace(ctx, "DB resource created.")
@@ -160,6 +176,22 @@ func resourceDbRead(ctx context.Context, d *schema.ResourceData, meta any) diag.
 	return diags
 }
 
+func resourceDbImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("name", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceDbCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	client := meta.(*common.ApiClient)
 	var diags diag.Diagnostics
@@ -173,7 +205,7 @@ func resourceDbCreate(ctx context.Context, d *schema.ResourceData, meta any) dia
 	databaseName := d.Get("name").(string)
 	comment := d.Get("comment").(string)
 
-	query := fmt.Sprintf("CREATE DATABASE %v %v COMMENT '%v'", databaseName, clusterStatement, common.GetComment(comment, cluster))
+	query := fmt.Sprintf("CREATE DATABASE %v %v COMMENT %v", common.QuoteIdentifier(databaseName), clusterStatement, common.QuoteString(comment))
 
 	err := conn.Exec(ctx, query)
 	if err != nil {
@@ -228,7 +260,7 @@ func resourceDbDelete(ctx context.Context, d *schema.ResourceData, meta any) dia
 	}
 	clusterStatement := common.GetClusterStatement(cluster)
 
-	query := fmt.Sprintf("DROP DATABASE %v %v SYNC", databaseName, clusterStatement)
+	query := fmt.Sprintf("DROP DATABASE %v %v SYNC", common.QuoteIdentifier(databaseName), clusterStatement)
 
 	err = (*conn).Exec(ctx, query)
 	if err != nil {
@@ -237,3 +269,10 @@ func resourceDbDelete(ctx context.Context, d *schema.ResourceData, meta any) dia
 	d.SetId("")
 	return diags
 }
+
+// upgradeDbStateV0 migrates databases created while comments were stored as {"comment":"...","cluster":"..."}.
+// Upgraders only rewrite the state, the json comment is kept on the server and decoded on read
+// until the database is recreated, as changing the comment replaces it.
+func upgradeDbStateV0(ctx context.Context, rawState map[string]interface{}, meta any) (map[string]interface{}, error) {
+	return common.UpgradeLegacyCommentState(rawState), nil
+}
@@ -27,6 +27,12 @@ func TestAccResourceDb(t *testing.T) {
 						"clickhouse_db.new_db", "comment", regexp.MustCompile("^"+testResourceDBDatabaseComment)),
 				),
 			},
+			// IMPORT
+			{
+				ResourceName:      "clickhouse_db.new_db",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 			// RECREATE WITH A DIFFERENT NAME
 			{
 				Config: dbConfig(testResourceDBDatabaseName2, testResourceDBDatabaseComment),
@@ -0,0 +1,233 @@
+package resourcedictionary
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// Layouts supported by the resource, as written in terraform
+var dictionaryLayouts = []string{"flat", "hashed", "complex_key_hashed", "range_hashed", "cache", "ip_trie", "direct"}
+
+// rangeRegex matches the RANGE clause of range_hashed dictionaries, e.g. (MIN start_date MAX end_date)
+var rangeRegex = regexp.MustCompile(`(?is)^\(\s*MIN\s+(.+?)\s+MAX\s+(.+?)\s*\)$`)
+
+type CHDictionary struct {
+	Database         string   `ch:"database"`
+	Name             string   `ch:"name"`
+	Status           string   `ch:"status"`
+	KeyNames         []string `ch:"key_names"`
+	LifetimeMin      uint64   `ch:"lifetime_min"`
+	LifetimeMax      uint64   `ch:"lifetime_max"`
+	Comment          string   `ch:"comment"`
+	CreateTableQuery string   `ch:"create_table_query"`
+}
+
+type DictionaryResource struct {
+	Database       string
+	Name           string
+	Cluster        string
+	Comment        string
+	PrimaryKey     []string
+	Attributes     []AttributeResource
+	Source         *SourceResource
+	Layout         string
+	LayoutSettings map[string]string
+	RangeMin       string
+	RangeMax       string
+	Lifetime       *LifetimeResource
+	Status         string
+}
+
+type AttributeResource struct {
+	Name         string
+	Type         string
+	Default      string
+	Expression   string
+	Hierarchical bool
+	Injective    bool
+}
+
+// SourceResource is a SOURCE clause, like CLICKHOUSE(HOST 'localhost' TABLE 'events')
+type SourceResource struct {
+	Type       string
+	Parameters []SourceParameter
+}
+
+// SourceParameter is a parameter of the source, its value is already rendered as SQL
+type SourceParameter struct {
+	Name  string
+	Value string
+}
+
+type LifetimeResource struct {
+	Min uint64
+	Max uint64
+}
+
+func (d *CHDictionary) ToResource() (*DictionaryResource, error) {
+	dictionaryResource := DictionaryResource{
+		Database:   d.Database,
+		Name:       d.Name,
+		Comment:    d.Comment,
+		PrimaryKey: d.KeyNames,
+		Status:     d.Status,
+	}
+	if d.LifetimeMin != 0 || d.LifetimeMax != 0 {
+		dictionaryResource.Lifetime = &LifetimeResource{Min: d.LifetimeMin, Max: d.LifetimeMax}
+	}
+	if d.CreateTableQuery == "" {
+		return &dictionaryResource, nil
+	}
+
+	// system.dictionaries only reports attribute names and types, the rest of the structure comes from the DDL
+	createQuery, err := common.ParseCreateQuery(d.CreateTableQuery)
+	if err != nil {
+		return nil, fmt.Errorf("parsing create query of dictionary %s.%s: %v", d.Database, d.Name, err)
+	}
+	if len(dictionaryResource.PrimaryKey) == 0 {
+		dictionaryResource.PrimaryKey = common.SplitTopLevel(createQuery.Clauses["PRIMARY KEY"])
+	}
+	// LIFETIME(MIN 0 MAX 0) is only told apart from a missing lifetime by the DDL
+	if createQuery.HasClause("LIFETIME") && dictionaryResource.Lifetime == nil {
+		dictionaryResource.Lifetime = &LifetimeResource{}
+	}
+	if source, ok := createQuery.Clauses["SOURCE"]; ok {
+		dictionaryResource.Source = parseSource(source)
+	}
+	for _, column := range createQuery.Columns {
+		attribute := AttributeResource{Name: column.Name, Type: column.Type, Expression: column.Attributes["EXPRESSION"]}
+		if column.DefaultKind == "DEFAULT" {
+			attribute.Default = column.DefaultExpression
+		}
+		_, attribute.Hierarchical = column.Attributes["HIERARCHICAL"]
+		_, attribute.Injective = column.Attributes["INJECTIVE"]
+		dictionaryResource.Attributes = append(dictionaryResource.Attributes, attribute)
+	}
+	if layout, ok := createQuery.Clauses["LAYOUT"]; ok {
+		dictionaryResource.Layout, dictionaryResource.LayoutSettings = parseLayout(layout)
+	}
+	if parts := rangeRegex.FindStringSubmatch(createQuery.Clauses["RANGE"]); parts != nil {
+		dictionaryResource.RangeMin, dictionaryResource.RangeMax = strings.Trim(parts[1], "`"), strings.Trim(parts[2], "`")
+	}
+	return &dictionaryResource, nil
+}
+
+// parseSource parses a SOURCE clause like (CLICKHOUSE(HOST 'localhost' TABLE 'events')), it returns nil when the clause
+// can't be parsed
+func parseSource(clause string) *SourceResource {
+	definitions, err := splitNestedParameters(clause)
+	if err != nil || len(definitions) != 1 {
+		return nil
+	}
+	parameters, err := splitNestedParameters(definitions[0].Value)
+	if err != nil {
+		return nil
+	}
+	source := SourceResource{Type: strings.ToUpper(definitions[0].Name)}
+	for _, parameter := range parameters {
+		source.Parameters = append(source.Parameters, SourceParameter{Name: strings.ToUpper(parameter.Name), Value: parameter.Value})
+	}
+	return &source
+}
+
+// splitNestedParameters splits the parameters of a nested definition like (USER 'default' PASSWORD '[HIDDEN]')
+func splitNestedParameters(definition string) ([]common.DefinitionParameter, error) {
+	definition = strings.TrimSpace(definition)
+	if !strings.HasPrefix(definition, "(") || !strings.HasSuffix(definition, ")") {
+		return nil, fmt.Errorf("%s is not enclosed in parentheses", definition)
+	}
+	return common.SplitDefinitionParameters(definition[1 : len(definition)-1])
+}
+
+// Parameter returns the value of the source parameter with the given upper case name
+func (s *SourceResource) Parameter(name string) (string, bool) {
+	for _, parameter := range s.Parameters {
+		if parameter.Name == name {
+			return parameter.Value, true
+		}
+	}
+	return "", false
+}
+
+// parseLayout splits a LAYOUT clause like (HASHED(SHARDS 4)) into the layout and its settings
+func parseLayout(layout string) (string, map[string]string) {
+	layout = strings.TrimSpace(layout)
+	if strings.HasPrefix(layout, "(") && strings.HasSuffix(layout, ")") {
+		layout = strings.TrimSpace(layout[1 : len(layout)-1])
+	}
+	settings := map[string]string{}
+	open := strings.Index(layout, "(")
+	if open == -1 || !strings.HasSuffix(layout, ")") {
+		return strings.ToLower(layout), settings
+	}
+	fields := strings.Fields(layout[open+1 : len(layout)-1])
+	for i := 0; i+1 < len(fields); i += 2 {
+		settings[strings.ToLower(fields[i])] = fields[i+1]
+	}
+	return strings.ToLower(strings.TrimSpace(layout[:open])), settings
+}
+
+// Validate checks the structure of the dictionary before it is sent to the server
+func (r *DictionaryResource) Validate() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(r.Attributes) == 0 {
+		return diags
+	}
+	attributes := make(map[string]bool, len(r.Attributes))
+	for _, attribute := range r.Attributes {
+		attributes[attribute.Name] = true
+	}
+
+	for i, key := range r.PrimaryKey {
+		if !attributes[key] {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Unknown primary key attribute",
+				Detail:        fmt.Sprintf("%q must be declared as an attribute", key),
+				AttributePath: cty.GetAttrPath("primary_key").IndexInt(i),
+			})
+		}
+	}
+	if len(r.PrimaryKey) > 1 && (r.Layout == "flat" || r.Layout == "hashed" || r.Layout == "range_hashed" || r.Layout == "direct") {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Composite primary key",
+			Detail:        fmt.Sprintf("layout %s only supports a single key, use a complex_key layout", r.Layout),
+			AttributePath: cty.GetAttrPath("primary_key"),
+		})
+	}
+
+	if r.Layout == "range_hashed" && (r.RangeMin == "" || r.RangeMax == "") {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Missing range",
+			Detail:        "range_hashed layout requires range_min and range_max",
+			AttributePath: cty.GetAttrPath("layout"),
+		})
+	}
+	for _, rangeAttribute := range []struct{ path, name string }{{"range_min", r.RangeMin}, {"range_max", r.RangeMax}} {
+		if rangeAttribute.name != "" && !attributes[rangeAttribute.name] {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Unknown range attribute",
+				Detail:        fmt.Sprintf("%q must be declared as an attribute", rangeAttribute.name),
+				AttributePath: cty.GetAttrPath(rangeAttribute.path),
+			})
+		}
+	}
+
+	if _, ok := r.LayoutSettings["size_in_cells"]; r.Layout == "cache" && !ok {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Missing cache size",
+			Detail:        "cache layout requires the size_in_cells layout setting",
+			AttributePath: cty.GetAttrPath("layout_settings"),
+		})
+	}
+	return diags
+}
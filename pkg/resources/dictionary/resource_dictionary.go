@@ -0,0 +1,782 @@
+package resourcedictionary
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// databaseSourceParameters are the parameters of the ClickHouse, PostgreSQL and MySQL sources, in the order they are written
+var databaseSourceParameters = []string{
+	"host", "port", "user", "password", "db", "table", "query", "where", "invalidate_query", "update_field", "update_lag",
+}
+
+// sourceTypes are the source blocks, in schema order, with the name of the source in the SOURCE clause
+// and their plain parameters
+var sourceTypes = []struct {
+	block      string
+	name       string
+	parameters []string
+}{
+	{"clickhouse", "CLICKHOUSE", append(append([]string{}, databaseSourceParameters...), "secure")},
+	{"postgresql", "POSTGRESQL", databaseSourceParameters},
+	{"mysql", "MYSQL", databaseSourceParameters},
+	{"http", "HTTP", []string{"url", "format"}},
+	{"file", "FILE", []string{"path", "format"}},
+	{"executable", "EXECUTABLE", []string{"command", "format", "implicit_key"}},
+	{"named_collection", "", nil},
+}
+
+func ResourceDictionary() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage dictionaries. Changes other than the database, name or cluster are applied in place with CREATE OR REPLACE DICTIONARY",
+
+		CreateContext: resourceDictionaryCreate,
+		ReadContext:   resourceDictionaryRead,
+		UpdateContext: resourceDictionaryUpdate,
+		DeleteContext: resourceDictionaryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDictionaryImport,
+		},
+		CustomizeDiff: validateDictionary,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Description: "DB Name where the dictionary will bellow",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "Dictionary Name",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the dictionary is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"comment": {
+				Description: "Dictionary comment, stored as given",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"primary_key": {
+				Description: "Key attributes of the dictionary, more than one requires a complex_key layout",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"attribute": {
+				Description: "Dictionary attributes, including the key and range attributes",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "Attribute name",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"type": {
+							Description: "Attribute type, e.g. `UInt64` or `Nullable(String)`",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"default": {
+							Description: "Default value for missing keys, as a SQL literal, e.g. `0` or `'unknown'`",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"expression": {
+							Description: "Expression computed by the source for the attribute",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"hierarchical": {
+							Description: "The attribute holds the parent key, for hierarchical dictionaries",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"injective": {
+							Description: "The key to attribute mapping is injective, which lets GROUP BY apply dictGet after grouping",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
+			"source": {
+				Description: "Source of the dictionary data, exactly one of the source blocks. The server hides passwords, they are kept from the configuration and the other parameters are read back",
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"clickhouse": databaseSourceSchema("ClickHouse table or query", true),
+						"postgresql": databaseSourceSchema("PostgreSQL table or query", false),
+						"mysql":      databaseSourceSchema("MySQL table or query", false),
+						"http": {
+							Description:  "HTTP(s) endpoint",
+							Type:         schema.TypeList,
+							Optional:     true,
+							MaxItems:     1,
+							ExactlyOneOf: sourceBlockPaths(),
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"url": {
+										Description: "URL returning the dictionary data",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"format": {
+										Description: "Format of the data, e.g. `TabSeparated` or `JSONEachRow`",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"user": {
+										Description: "User of the basic authentication",
+										Type:        schema.TypeString,
+										Optional:    true,
+									},
+									"password": {
+										Description: "Password of the basic authentication",
+										Type:        schema.TypeString,
+										Optional:    true,
+										Sensitive:   true,
+									},
+									"headers": {
+										Description: "HTTP headers sent with the request",
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+						"file": {
+							Description:  "Local file in the server `user_files` directory",
+							Type:         schema.TypeList,
+							Optional:     true,
+							MaxItems:     1,
+							ExactlyOneOf: sourceBlockPaths(),
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"path": {
+										Description: "Absolute path of the file",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"format": {
+										Description: "Format of the file, e.g. `CSV`",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+								},
+							},
+						},
+						"executable": {
+							Description:  "Command writing the dictionary data to its standard output",
+							Type:         schema.TypeList,
+							Optional:     true,
+							MaxItems:     1,
+							ExactlyOneOf: sourceBlockPaths(),
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"command": {
+										Description: "Command to run, relative to the server `user_scripts` directory",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"format": {
+										Description: "Format of the output, e.g. `TabSeparated`",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"implicit_key": {
+										Description: "The command only returns attributes, keys are taken from the request order",
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Default:     false,
+									},
+								},
+							},
+						},
+						"named_collection": {
+							Description:  "Source configured by a named collection on the server",
+							Type:         schema.TypeList,
+							Optional:     true,
+							MaxItems:     1,
+							ExactlyOneOf: sourceBlockPaths(),
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Description: "Named collection name",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"type": {
+										Description:  "Source type of the collection, one of CLICKHOUSE, POSTGRESQL, MYSQL or HTTP",
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"CLICKHOUSE", "POSTGRESQL", "MYSQL", "HTTP"}, false),
+									},
+									"parameters": {
+										Description: "Parameters overriding or completing the collection, e.g. `{ table = \"events\" }`",
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"layout": {
+				Description:  "Layout of the dictionary in memory, one of " + strings.Join(dictionaryLayouts, ", "),
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(dictionaryLayouts, false),
+			},
+			"layout_settings": {
+				Description: "Layout settings, e.g. `{ size_in_cells = 1000000 }` for cache layouts",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"range_min": {
+				Description:  "Attribute holding the start of the validity range, for range_hashed layouts",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"range_max"},
+			},
+			"range_max": {
+				Description:  "Attribute holding the end of the validity range, for range_hashed layouts",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"range_min"},
+			},
+			"lifetime": {
+				Description: "Interval in seconds between dictionary updates, the server picks a random time within it",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"min": {
+							Description:  "Minimum seconds between updates",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max": {
+							Description:  "Maximum seconds between updates, 0 disables updates",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+					},
+				},
+			},
+			"status": {
+				Description: "Loading status reported by system.dictionaries, e.g. LOADED or NOT_LOADED",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func databaseSourceSchema(description string, clickhouse bool) *schema.Schema {
+	source := map[string]*schema.Schema{
+		"host": {
+			Description: "Host of the server",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"port": {
+			Description: "Port of the server",
+			Type:        schema.TypeInt,
+			Optional:    true,
+		},
+		"user": {
+			Description: "User to connect with",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"password": {
+			Description: "Password of the user",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+		},
+		"db": {
+			Description: "Database of the table",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"table": {
+			Description: "Table to load, conflicts with `query`",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"query": {
+			Description: "Query to load the dictionary with, instead of a table",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"where": {
+			Description: "Condition on the table rows",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"invalidate_query": {
+			Description: "Query whose result change triggers a dictionary update",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"update_field": {
+			Description: "Column used to only load the rows changed since the last update",
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"update_lag": {
+			Description: "Seconds subtracted from the last update time when loading changed rows",
+			Type:        schema.TypeInt,
+			Optional:    true,
+		},
+	}
+	if clickhouse {
+		source["secure"] = &schema.Schema{
+			Description: "Connect with TLS",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		}
+	}
+	return &schema.Schema{
+		Description:  description,
+		Type:         schema.TypeList,
+		Optional:     true,
+		MaxItems:     1,
+		ExactlyOneOf: sourceBlockPaths(),
+		Elem:         &schema.Resource{Schema: source},
+	}
+}
+
+func sourceBlockPaths() []string {
+	paths := make([]string, 0, len(sourceTypes))
+	for _, sourceType := range sourceTypes {
+		paths = append(paths, "source.0."+sourceType.block)
+	}
+	return paths
+}
+
+func validateDictionary(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	dictionaryResource := DictionaryResource{
+		Layout:   d.Get("layout").(string),
+		RangeMin: d.Get("range_min").(string),
+		RangeMax: d.Get("range_max").(string),
+	}
+	if !d.NewValueKnown("primary_key") || !d.NewValueKnown("attribute") || !d.NewValueKnown("layout_settings") {
+		return nil
+	}
+	dictionaryResource.PrimaryKey = common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{}))
+	dictionaryResource.Attributes = attributesFromList(d.Get("attribute").([]interface{}))
+	dictionaryResource.LayoutSettings = stringMap(d.Get("layout_settings").(map[string]interface{}))
+	return common.DiagnosticsToError(dictionaryResource.Validate())
+}
+
+func resourceDictionaryRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	conn := client.ClickhouseConnection
+
+	database := d.Get("database").(string)
+	dictionaryName := d.Get("name").(string)
+
+	chDictionaryService := CHDictionaryService{CHConnection: conn}
+	chDictionary, err := chDictionaryService.GetDictionary(ctx, database, dictionaryName)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse dictionary: %v", err))
+	}
+	if chDictionary == nil {
+		d.SetId("")
+		return diags
+	}
+
+	dictionaryResource, err := chDictionary.ToResource()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("transforming Clickhouse dictionary to resource: %v", err))
+	}
+
+	if err := d.Set("database", dictionaryResource.Database); err != nil {
+		return diag.FromErr(fmt.Errorf("setting database: %v", err))
+	}
+	if err := d.Set("name", dictionaryResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("comment", dictionaryResource.Comment); err != nil {
+		return diag.FromErr(fmt.Errorf("setting comment: %v", err))
+	}
+	if err := d.Set("primary_key", dictionaryResource.PrimaryKey); err != nil {
+		return diag.FromErr(fmt.Errorf("setting primary_key: %v", err))
+	}
+	if dictionaryResource.Attributes != nil {
+		if err := d.Set("attribute", attributesToList(dictionaryResource.Attributes)); err != nil {
+			return diag.FromErr(fmt.Errorf("setting attribute: %v", err))
+		}
+	}
+	if dictionaryResource.Layout != "" {
+		if err := d.Set("layout", dictionaryResource.Layout); err != nil {
+			return diag.FromErr(fmt.Errorf("setting layout: %v", err))
+		}
+		if err := d.Set("layout_settings", dictionaryResource.LayoutSettings); err != nil {
+			return diag.FromErr(fmt.Errorf("setting layout_settings: %v", err))
+		}
+	}
+	if err := d.Set("range_min", dictionaryResource.RangeMin); err != nil {
+		return diag.FromErr(fmt.Errorf("setting range_min: %v", err))
+	}
+	if err := d.Set("range_max", dictionaryResource.RangeMax); err != nil {
+		return diag.FromErr(fmt.Errorf("setting range_max: %v", err))
+	}
+	if err := d.Set("lifetime", lifetimeToList(dictionaryResource.Lifetime)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting lifetime: %v", err))
+	}
+	if dictionaryResource.Source != nil {
+		if source := sourceToList(*dictionaryResource.Source, d.Get("source").([]interface{})); source != nil {
+			if err := d.Set("source", source); err != nil {
+				return diag.FromErr(fmt.Errorf("setting source: %v", err))
+			}
+		}
+	}
+	if err := d.Set("status", dictionaryResource.Status); err != nil {
+		return diag.FromErr(fmt.Errorf("setting status: %v", err))
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + database + ":" + dictionaryName)
+
+	return diags
+}
+
+func resourceDictionaryImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "database", "dictionary")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("database", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting database: %v", err)
+	}
+	if err := d.Set("name", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceDictionaryCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chDictionaryService := CHDictionaryService{CHConnection: client.ClickhouseConnection}
+
+	dictionaryResource := dictionaryResourceFromData(d, client)
+	if validationDiags := dictionaryResource.Validate(); validationDiags.HasError() {
+		return validationDiags
+	}
+	if err := chDictionaryService.CreateDictionary(ctx, dictionaryResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + dictionaryResource.Database + ":" + dictionaryResource.Name)
+
+	return resourceDictionaryRead(ctx, d, meta)
+}
+
+func resourceDictionaryUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chDictionaryService := CHDictionaryService{CHConnection: client.ClickhouseConnection}
+
+	dictionaryResource := dictionaryResourceFromData(d, client)
+	if validationDiags := dictionaryResource.Validate(); validationDiags.HasError() {
+		return validationDiags
+	}
+	if err := chDictionaryService.ReplaceDictionary(ctx, dictionaryResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDictionaryRead(ctx, d, meta)
+}
+
+func resourceDictionaryDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chDictionaryService := CHDictionaryService{CHConnection: client.ClickhouseConnection}
+
+	dictionaryResource := DictionaryResource{
+		Database: d.Get("database").(string),
+		Name:     d.Get("name").(string),
+		Cluster:  d.Get("cluster").(string),
+	}
+	if dictionaryResource.Cluster == "" {
+		dictionaryResource.Cluster = client.DefaultCluster
+	}
+	if err := chDictionaryService.DeleteDictionary(ctx, dictionaryResource); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func dictionaryResourceFromData(d *schema.ResourceData, client *common.ApiClient) DictionaryResource {
+	dictionaryResource := DictionaryResource{
+		Database:       d.Get("database").(string),
+		Name:           d.Get("name").(string),
+		Cluster:        d.Get("cluster").(string),
+		Comment:        d.Get("comment").(string),
+		PrimaryKey:     common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{})),
+		Attributes:     attributesFromList(d.Get("attribute").([]interface{})),
+		Source:         sourceFromList(d.Get("source").([]interface{})),
+		Layout:         d.Get("layout").(string),
+		LayoutSettings: stringMap(d.Get("layout_settings").(map[string]interface{})),
+		RangeMin:       d.Get("range_min").(string),
+		RangeMax:       d.Get("range_max").(string),
+	}
+	if dictionaryResource.Cluster == "" {
+		dictionaryResource.Cluster = client.DefaultCluster
+	}
+	if lifetime := d.Get("lifetime").([]interface{}); len(lifetime) > 0 && lifetime[0] != nil {
+		lifetimeMap := lifetime[0].(map[string]interface{})
+		dictionaryResource.Lifetime = &LifetimeResource{Min: uint64(lifetimeMap["min"].(int)), Max: uint64(lifetimeMap["max"].(int))}
+	}
+	return dictionaryResource
+}
+
+func attributesFromList(list []interface{}) []AttributeResource {
+	attributes := make([]AttributeResource, 0, len(list))
+	for _, item := range list {
+		if item == nil {
+			continue
+		}
+		attributeMap := item.(map[string]interface{})
+		attributes = append(attributes, AttributeResource{
+			Name:         attributeMap["name"].(string),
+			Type:         attributeMap["type"].(string),
+			Default:      attributeMap["default"].(string),
+			Expression:   attributeMap["expression"].(string),
+			Hierarchical: attributeMap["hierarchical"].(bool),
+			Injective:    attributeMap["injective"].(bool),
+		})
+	}
+	return attributes
+}
+
+func attributesToList(attributes []AttributeResource) []interface{} {
+	list := make([]interface{}, 0, len(attributes))
+	for _, attribute := range attributes {
+		list = append(list, map[string]interface{}{
+			"name":         attribute.Name,
+			"type":         attribute.Type,
+			"default":      attribute.Default,
+			"expression":   attribute.Expression,
+			"hierarchical": attribute.Hierarchical,
+			"injective":    attribute.Injective,
+		})
+	}
+	return list
+}
+
+func lifetimeToList(lifetime *LifetimeResource) []interface{} {
+	if lifetime == nil {
+		return []interface{}{}
+	}
+	return []interface{}{map[string]interface{}{"min": int(lifetime.Min), "max": int(lifetime.Max)}}
+}
+
+// sourceToList returns the source block of a SOURCE clause read from the server, or nil when no block matches it.
+// The server hides passwords, they are taken from the state.
+func sourceToList(source SourceResource, state []interface{}) []interface{} {
+	var stateSource map[string]interface{}
+	if len(state) > 0 && state[0] != nil {
+		stateSource = state[0].(map[string]interface{})
+	}
+	stateBlock := func(name string) map[string]interface{} {
+		blocks, _ := stateSource[name].([]interface{})
+		if len(blocks) == 0 || blocks[0] == nil {
+			return map[string]interface{}{}
+		}
+		return blocks[0].(map[string]interface{})
+	}
+
+	if name, ok := source.Parameter("NAME"); ok {
+		stateParameters, _ := stateBlock("named_collection")["parameters"].(map[string]interface{})
+		parameters := map[string]interface{}{}
+		for _, parameter := range source.Parameters {
+			name := strings.ToLower(parameter.Name)
+			switch {
+			case parameter.Name == "NAME":
+			case parameter.Name == "PASSWORD":
+				if password, ok := stateParameters[name]; ok {
+					parameters[name] = password
+				}
+			default:
+				parameters[name] = common.UnquoteString(parameter.Value)
+			}
+		}
+		block := map[string]interface{}{"name": strings.Trim(name, "`"), "type": source.Type, "parameters": parameters}
+		return []interface{}{map[string]interface{}{"named_collection": []interface{}{block}}}
+	}
+
+	for _, sourceType := range sourceTypes {
+		if sourceType.name != source.Type {
+			continue
+		}
+		blockSchema := ResourceDictionary().Schema["source"].Elem.(*schema.Resource).Schema[sourceType.block].Elem.(*schema.Resource).Schema
+		block := map[string]interface{}{}
+		for _, name := range sourceType.parameters {
+			value, ok := source.Parameter(strings.ToUpper(name))
+			switch {
+			case name == "password":
+				block[name], _ = stateBlock(sourceType.block)[name].(string)
+			case !ok:
+			case blockSchema[name].Type == schema.TypeInt:
+				block[name], _ = strconv.Atoi(common.UnquoteString(value))
+			case blockSchema[name].Type == schema.TypeBool:
+				block[name] = common.UnquoteString(value) == "1" || strings.EqualFold(common.UnquoteString(value), "true")
+			default:
+				block[name] = common.UnquoteString(value)
+			}
+		}
+
+		if sourceType.block == "http" {
+			credentials, _ := source.Parameter("CREDENTIALS")
+			parameters, _ := splitNestedParameters(credentials)
+			for _, parameter := range parameters {
+				if strings.EqualFold(parameter.Name, "USER") {
+					block["user"] = common.UnquoteString(parameter.Value)
+					block["password"], _ = stateBlock("http")["password"].(string)
+				}
+			}
+			headers := map[string]interface{}{}
+			headerDefinitions, _ := source.Parameter("HEADERS")
+			definitions, _ := splitNestedParameters(headerDefinitions)
+			for _, definition := range definitions {
+				parameters, _ := splitNestedParameters(definition.Value)
+				header := map[string]string{}
+				for _, parameter := range parameters {
+					header[strings.ToUpper(parameter.Name)] = common.UnquoteString(parameter.Value)
+				}
+				if name, ok := header["NAME"]; ok {
+					headers[name] = header["VALUE"]
+				}
+			}
+			block["headers"] = headers
+		}
+		return []interface{}{map[string]interface{}{sourceType.block: []interface{}{block}}}
+	}
+	return nil
+}
+
+// sourceFromList renders the configured source block as the parameters of the SOURCE clause
+func sourceFromList(list []interface{}) *SourceResource {
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	sourceMap := list[0].(map[string]interface{})
+	for _, sourceType := range sourceTypes {
+		blocks, _ := sourceMap[sourceType.block].([]interface{})
+		if len(blocks) == 0 || blocks[0] == nil {
+			continue
+		}
+		block := blocks[0].(map[string]interface{})
+
+		source := SourceResource{Type: sourceType.name}
+		if sourceType.block == "named_collection" {
+			source.Type = block["type"].(string)
+			source.Parameters = append(source.Parameters, SourceParameter{Name: "NAME", Value: common.QuoteIdentifier(block["name"].(string))})
+			for _, name := range sortedKeys(block["parameters"].(map[string]interface{})) {
+				source.Parameters = append(source.Parameters, SourceParameter{
+					Name:  strings.ToUpper(name),
+					Value: common.QuoteString(block["parameters"].(map[string]interface{})[name].(string)),
+				})
+			}
+			return &source
+		}
+
+		for _, name := range sourceType.parameters {
+			switch value := block[name].(type) {
+			case string:
+				if value != "" {
+					source.Parameters = append(source.Parameters, SourceParameter{Name: strings.ToUpper(name), Value: common.QuoteString(value)})
+				}
+			case int:
+				if value != 0 {
+					source.Parameters = append(source.Parameters, SourceParameter{Name: strings.ToUpper(name), Value: strconv.Itoa(value)})
+				}
+			case bool:
+				if value {
+					source.Parameters = append(source.Parameters, SourceParameter{Name: strings.ToUpper(name), Value: "1"})
+				}
+			}
+		}
+
+		if sourceType.block == "http" {
+			if user := block["user"].(string); user != "" {
+				credentials := fmt.Sprintf("(USER %s PASSWORD %s)", common.QuoteString(user), common.QuoteString(block["password"].(string)))
+				source.Parameters = append(source.Parameters, SourceParameter{Name: "CREDENTIALS", Value: credentials})
+			}
+			if headers := block["headers"].(map[string]interface{}); len(headers) > 0 {
+				headerDefinitions := make([]string, 0, len(headers))
+				for _, name := range sortedKeys(headers) {
+					headerDefinitions = append(headerDefinitions, fmt.Sprintf("HEADER(NAME %s VALUE %s)", common.QuoteString(name), common.QuoteString(headers[name].(string))))
+				}
+				source.Parameters = append(source.Parameters, SourceParameter{Name: "HEADERS", Value: "(" + strings.Join(headerDefinitions, " ") + ")"})
+			}
+		}
+		return &source
+	}
+	return nil
+}
+
+func stringMap(values map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		result[name] = value.(string)
+	}
+	return result
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
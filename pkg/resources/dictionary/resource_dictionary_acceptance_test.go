@@ -0,0 +1,94 @@
+package resourcedictionary_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceDictionaryDatabaseName = "test_dictionary_database"
+
+func TestAccResourceDictionary(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: dictionaryConfig(testResourceDictionaryDatabaseName, "hashed", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "name", "regions"),
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "primary_key.0", "id"),
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "attribute.#", "2"),
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "attribute.1.default", "'unknown'"),
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "layout", "hashed"),
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "lifetime.0.max", "600"),
+				),
+			},
+			// REPLACE THE LAYOUT IN PLACE
+			{
+				Config: dictionaryConfig(testResourceDictionaryDatabaseName, "flat", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "layout", "flat"),
+					resource.TestCheckResourceAttr("clickhouse_dictionary.dictionary", "comment", "second"),
+				),
+			},
+		},
+	})
+}
+
+func dictionaryConfig(database string, layout string, comment string) string {
+	s := `
+	resource "clickhouse_db" "new_db_resource" {
+		name = "%_database_%"
+	}
+
+	resource "clickhouse_table" "source" {
+		database = clickhouse_db.new_db_resource.name
+		name = "regions_source"
+		engine = "MergeTree"
+		order_by = ["id"]
+		column {
+			name = "id"
+			type = "UInt64"
+		}
+		column {
+			name = "name"
+			type = "String"
+		}
+	}
+
+	resource "clickhouse_dictionary" "dictionary" {
+		database = clickhouse_db.new_db_resource.name
+		name = "regions"
+		comment = "%_comment_%"
+		primary_key = ["id"]
+		attribute {
+			name = "id"
+			type = "UInt64"
+		}
+		attribute {
+			name = "name"
+			type = "String"
+			default = "'unknown'"
+		}
+		source {
+			clickhouse {
+				user = "default"
+				db = "%_database_%"
+				table = clickhouse_table.source.name
+			}
+		}
+		layout = "%_layout_%"
+		lifetime {
+			min = 300
+			max = 600
+		}
+	}
+	`
+	s = strings.Replace(s, "%_database_%", database, -1)
+	s = strings.Replace(s, "%_layout_%", layout, -1)
+	s = strings.Replace(s, "%_comment_%", comment, -1)
+	return s
+}
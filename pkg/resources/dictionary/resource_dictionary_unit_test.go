@@ -0,0 +1,249 @@
+package resourcedictionary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestBuildCreateDictionarySentence(t *testing.T) {
+	dictionary := DictionaryResource{
+		Database:   "db",
+		Name:       "prices",
+		Cluster:    "main",
+		PrimaryKey: []string{"id"},
+		Attributes: []AttributeResource{
+			{Name: "id", Type: "UInt64"},
+			{Name: "parent", Type: "UInt64", Default: "0", Hierarchical: true},
+			{Name: "name", Type: "String", Default: "''", Expression: "upper(raw_name)", Injective: true},
+			{Name: "start", Type: "Date"},
+			{Name: "end", Type: "Date"},
+		},
+		Source: &SourceResource{Type: "CLICKHOUSE", Parameters: []SourceParameter{
+			{Name: "HOST", Value: "'localhost'"}, {Name: "PORT", Value: "9000"}, {Name: "TABLE", Value: "'prices'"},
+		}},
+		Layout:   "range_hashed",
+		RangeMin: "start",
+		RangeMax: "end",
+		Lifetime: &LifetimeResource{Min: 0, Max: 300},
+		Comment:  "prices",
+	}
+
+	want := "CREATE OR REPLACE DICTIONARY `db`.`prices` ON CLUSTER `main` " +
+		"(`id` UInt64, `parent` UInt64 DEFAULT 0 HIERARCHICAL, `name` String DEFAULT '' EXPRESSION upper(raw_name) INJECTIVE, `start` Date, `end` Date) " +
+		"PRIMARY KEY `id` SOURCE(CLICKHOUSE(HOST 'localhost' PORT 9000 TABLE 'prices')) LAYOUT(RANGE_HASHED()) " +
+		"RANGE(MIN `start` MAX `end`) LIFETIME(MIN 0 MAX 300) COMMENT 'prices'"
+	if got := buildCreateDictionarySentence(dictionary, true); got != want {
+		t.Errorf("buildCreateDictionarySentence() = %s, want %s", got, want)
+	}
+
+	if got := buildLayoutDefinition("cache", map[string]string{"size_in_cells": "1000"}); got != "CACHE(SIZE_IN_CELLS 1000)" {
+		t.Errorf("buildLayoutDefinition() = %s", got)
+	}
+}
+
+func TestSourceFromList(t *testing.T) {
+	resourceSchema := ResourceDictionary().Schema
+	tests := []struct {
+		name   string
+		source map[string]interface{}
+		want   string
+	}{
+		{
+			name: "clickhouse",
+			source: map[string]interface{}{"clickhouse": []interface{}{map[string]interface{}{
+				"host": "localhost", "port": 9000, "user": "default", "password": "it's secret", "db": "db", "table": "prices", "secure": true,
+			}}},
+			want: "CLICKHOUSE(HOST 'localhost' PORT 9000 USER 'default' PASSWORD 'it\\'s secret' DB 'db' TABLE 'prices' SECURE 1)",
+		},
+		{
+			name: "http",
+			source: map[string]interface{}{"http": []interface{}{map[string]interface{}{
+				"url": "https://example.com/prices.tsv", "format": "TabSeparated", "user": "u", "password": "p",
+				"headers": map[string]interface{}{"X-Token": "abc"},
+			}}},
+			want: "HTTP(URL 'https://example.com/prices.tsv' FORMAT 'TabSeparated' CREDENTIALS(USER 'u' PASSWORD 'p') HEADERS(HEADER(NAME 'X-Token' VALUE 'abc')))",
+		},
+		{
+			name: "named collection",
+			source: map[string]interface{}{"named_collection": []interface{}{map[string]interface{}{
+				"name": "pg_prices", "type": "POSTGRESQL", "parameters": map[string]interface{}{"table": "prices"},
+			}}},
+			want: "POSTGRESQL(NAME `pg_prices` TABLE 'prices')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"source": []interface{}{tt.source}})
+			source := sourceFromList(d.Get("source").([]interface{}))
+			if source == nil {
+				t.Fatalf("sourceFromList() = nil")
+			}
+			if got := buildSourceDefinition(*source); got != tt.want {
+				t.Errorf("buildSourceDefinition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCHDictionaryToResource(t *testing.T) {
+	chDictionary := CHDictionary{
+		Database:    "db",
+		Name:        "prices",
+		Status:      "NOT_LOADED",
+		KeyNames:    []string{"id"},
+		LifetimeMax: 300,
+		Comment:     "prices",
+		CreateTableQuery: "CREATE DICTIONARY db.prices (`id` UInt64, `parent` UInt64 DEFAULT 0 HIERARCHICAL, `start` Date, `end` Date) " +
+			"PRIMARY KEY id SOURCE(CLICKHOUSE(HOST 'localhost' PASSWORD '[HIDDEN]' TABLE 'prices')) LIFETIME(MIN 0 MAX 300) " +
+			"LAYOUT(RANGE_HASHED(RANGE_LOOKUP_STRATEGY 'max')) RANGE(MIN start MAX end) COMMENT 'prices'",
+	}
+	dictionaryResource, err := chDictionary.ToResource()
+	if err != nil {
+		t.Fatalf("ToResource() unexpected error: %v", err)
+	}
+	want := &DictionaryResource{
+		Database:   "db",
+		Name:       "prices",
+		Comment:    "prices",
+		PrimaryKey: []string{"id"},
+		Attributes: []AttributeResource{
+			{Name: "id", Type: "UInt64"},
+			{Name: "parent", Type: "UInt64", Default: "0", Hierarchical: true},
+			{Name: "start", Type: "Date"},
+			{Name: "end", Type: "Date"},
+		},
+		Layout:         "range_hashed",
+		LayoutSettings: map[string]string{"range_lookup_strategy": "'max'"},
+		RangeMin:       "start",
+		RangeMax:       "end",
+		Source: &SourceResource{Type: "CLICKHOUSE", Parameters: []SourceParameter{
+			{Name: "HOST", Value: "'localhost'"}, {Name: "PASSWORD", Value: "'[HIDDEN]'"}, {Name: "TABLE", Value: "'prices'"},
+		}},
+		Lifetime: &LifetimeResource{Min: 0, Max: 300},
+		Status:   "NOT_LOADED",
+	}
+	if !reflect.DeepEqual(dictionaryResource, want) {
+		t.Errorf("ToResource() = %+v, want %+v", dictionaryResource, want)
+	}
+
+	chDictionary.LifetimeMax = 0
+	chDictionary.CreateTableQuery = "CREATE DICTIONARY db.prices (`id` UInt64) PRIMARY KEY id SOURCE(NULL()) LIFETIME(MIN 0 MAX 0) LAYOUT(FLAT())"
+	if dictionaryResource, err = chDictionary.ToResource(); err != nil || !reflect.DeepEqual(dictionaryResource.Lifetime, &LifetimeResource{}) {
+		t.Errorf("ToResource() lifetime = %+v, %v, want MIN 0 MAX 0", dictionaryResource.Lifetime, err)
+	}
+	chDictionary.CreateTableQuery = "CREATE DICTIONARY db.prices (`id` UInt64) PRIMARY KEY id SOURCE(NULL()) LAYOUT(FLAT())"
+	if dictionaryResource, err = chDictionary.ToResource(); err != nil || dictionaryResource.Lifetime != nil {
+		t.Errorf("ToResource() lifetime = %+v, %v, want none", dictionaryResource.Lifetime, err)
+	}
+}
+
+func TestSourceToList(t *testing.T) {
+	resourceSchema := ResourceDictionary().Schema
+	tests := []struct {
+		name   string
+		clause string
+		state  map[string]interface{}
+	}{
+		{
+			name:   "clickhouse",
+			clause: "(CLICKHOUSE(HOST 'localhost' PORT 9000 USER 'default' PASSWORD '[HIDDEN]' DB 'db' TABLE 'prices' SECURE 1))",
+			state: map[string]interface{}{"clickhouse": []interface{}{map[string]interface{}{
+				"host": "localhost", "port": 9000, "user": "default", "password": "it's secret", "db": "db", "table": "prices", "secure": true,
+			}}},
+		},
+		{
+			name:   "http",
+			clause: "(HTTP(URL 'https://example.com/prices.tsv' FORMAT 'TabSeparated' CREDENTIALS(USER 'u' PASSWORD '[HIDDEN]') HEADERS(HEADER(NAME 'X-Token' VALUE 'abc'))))",
+			state: map[string]interface{}{"http": []interface{}{map[string]interface{}{
+				"url": "https://example.com/prices.tsv", "format": "TabSeparated", "user": "u", "password": "p",
+				"headers": map[string]interface{}{"X-Token": "abc"},
+			}}},
+		},
+		{
+			name:   "named collection",
+			clause: "(POSTGRESQL(NAME pg_prices TABLE 'prices'))",
+			state: map[string]interface{}{"named_collection": []interface{}{map[string]interface{}{
+				"name": "pg_prices", "type": "POSTGRESQL", "parameters": map[string]interface{}{"table": "prices"},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"source": []interface{}{tt.state}})
+			source := parseSource(tt.clause)
+			if source == nil {
+				t.Fatalf("parseSource(%q) = nil", tt.clause)
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+			if err := d.Set("source", sourceToList(*source, want.Get("source").([]interface{}))); err != nil {
+				t.Fatalf("setting source: %v", err)
+			}
+			if got, want := d.Get("source"), want.Get("source"); !reflect.DeepEqual(got, want) {
+				t.Errorf("sourceToList() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	if parseSource("(REDIS(HOST 'localhost'") != nil {
+		t.Errorf("parseSource() of an unbalanced clause should be nil")
+	}
+}
+
+func TestDictionaryResourceValidate(t *testing.T) {
+	attributes := []AttributeResource{{Name: "id", Type: "UInt64"}, {Name: "region", Type: "String"}, {Name: "start", Type: "Date"}}
+	tests := []struct {
+		name       string
+		dictionary DictionaryResource
+		wantErrors []string
+	}{
+		{
+			name:       "valid",
+			dictionary: DictionaryResource{PrimaryKey: []string{"id"}, Attributes: attributes, Layout: "hashed"},
+		},
+		{
+			name:       "unknown key",
+			dictionary: DictionaryResource{PrimaryKey: []string{"missing"}, Attributes: attributes, Layout: "hashed"},
+			wantErrors: []string{"primary_key.0"},
+		},
+		{
+			name:       "composite key with simple layout",
+			dictionary: DictionaryResource{PrimaryKey: []string{"id", "region"}, Attributes: attributes, Layout: "hashed"},
+			wantErrors: []string{"primary_key"},
+		},
+		{
+			name:       "range_hashed without range",
+			dictionary: DictionaryResource{PrimaryKey: []string{"id"}, Attributes: attributes, Layout: "range_hashed", RangeMin: "start"},
+			wantErrors: []string{"layout"},
+		},
+		{
+			name:       "unknown range attribute",
+			dictionary: DictionaryResource{PrimaryKey: []string{"id"}, Attributes: attributes, Layout: "range_hashed", RangeMin: "start", RangeMax: "end"},
+			wantErrors: []string{"range_max"},
+		},
+		{
+			name:       "cache without size",
+			dictionary: DictionaryResource{PrimaryKey: []string{"id"}, Attributes: attributes, Layout: "cache"},
+			wantErrors: []string{"layout_settings"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := tt.dictionary.Validate()
+			if len(diags) != len(tt.wantErrors) {
+				t.Fatalf("Validate() = %+v, want %d errors", diags, len(tt.wantErrors))
+			}
+			for i, diagnostic := range diags {
+				if got := common.FormatAttributePath(diagnostic.AttributePath); got != tt.wantErrors[i] {
+					t.Errorf("Validate() error %d path = %s, want %s", i, got, tt.wantErrors[i])
+				}
+			}
+		})
+	}
+}
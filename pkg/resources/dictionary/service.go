@@ -0,0 +1,67 @@
+package resourcedictionary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHDictionaryService struct {
+	CHConnection *driver.Conn
+}
+
+// GetDictionary returns the dictionary, or nil when it does not exist
+func (ds *CHDictionaryService) GetDictionary(ctx context.Context, database string, name string) (*CHDictionary, error) {
+	ctx = common.QueryParameters(ctx, map[string]string{"database": database, "dictionary": name})
+	query := "SELECT database, name, toString(status) AS status, `key.names` AS key_names, lifetime_min, lifetime_max, comment FROM system.dictionaries where database = {database:String} and name = {dictionary:String}"
+	row := (*ds.CHConnection).QueryRow(ctx, query)
+
+	if row.Err() != nil {
+		return nil, fmt.Errorf("reading dictionary from Clickhouse: %v", row.Err())
+	}
+
+	var chDictionary CHDictionary
+	err := row.ScanStruct(&chDictionary)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse dictionary row: %v", err)
+	}
+
+	row = (*ds.CHConnection).QueryRow(ctx, "SELECT create_table_query FROM system.tables where database = {database:String} and name = {dictionary:String}")
+	if err := row.Scan(&chDictionary.CreateTableQuery); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reading dictionary create query from Clickhouse: %v", err)
+	}
+
+	return &chDictionary, nil
+}
+
+func (ds *CHDictionaryService) CreateDictionary(ctx context.Context, dictionaryResource DictionaryResource) error {
+	err := (*ds.CHConnection).Exec(ctx, buildCreateDictionarySentence(dictionaryResource, false))
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse dictionary: %v", err)
+	}
+	return nil
+}
+
+func (ds *CHDictionaryService) ReplaceDictionary(ctx context.Context, dictionaryResource DictionaryResource) error {
+	err := (*ds.CHConnection).Exec(ctx, buildCreateDictionarySentence(dictionaryResource, true))
+	if err != nil {
+		return fmt.Errorf("replacing Clickhouse dictionary: %v", err)
+	}
+	return nil
+}
+
+func (ds *CHDictionaryService) DeleteDictionary(ctx context.Context, dictionaryResource DictionaryResource) error {
+	query := fmt.Sprintf("DROP DICTIONARY %s %s", common.QuoteTableName(dictionaryResource.Database, dictionaryResource.Name), common.GetClusterStatement(dictionaryResource.Cluster))
+	err := (*ds.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("deleting Clickhouse dictionary: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package resourcedictionary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildCreateDictionarySentence(dictionaryResource DictionaryResource, orReplace bool) string {
+	statement := "CREATE DICTIONARY"
+	if orReplace {
+		statement = "CREATE OR REPLACE DICTIONARY"
+	}
+	parts := []string{statement, common.QuoteTableName(dictionaryResource.Database, dictionaryResource.Name)}
+	if clusterStatement := common.GetClusterStatement(dictionaryResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+
+	attributes := make([]string, 0, len(dictionaryResource.Attributes))
+	for _, attribute := range dictionaryResource.Attributes {
+		attributes = append(attributes, buildAttributeDefinition(attribute))
+	}
+	parts = append(parts, "("+strings.Join(attributes, ", ")+")")
+	parts = append(parts, "PRIMARY KEY "+strings.Join(common.QuoteIdentifiers(dictionaryResource.PrimaryKey), ", "))
+	if dictionaryResource.Source != nil {
+		parts = append(parts, fmt.Sprintf("SOURCE(%s)", buildSourceDefinition(*dictionaryResource.Source)))
+	}
+	parts = append(parts, fmt.Sprintf("LAYOUT(%s)", buildLayoutDefinition(dictionaryResource.Layout, dictionaryResource.LayoutSettings)))
+	if dictionaryResource.RangeMin != "" && dictionaryResource.RangeMax != "" {
+		parts = append(parts, fmt.Sprintf("RANGE(MIN %s MAX %s)", common.QuoteIdentifier(dictionaryResource.RangeMin), common.QuoteIdentifier(dictionaryResource.RangeMax)))
+	}
+	if dictionaryResource.Lifetime != nil {
+		parts = append(parts, fmt.Sprintf("LIFETIME(MIN %d MAX %d)", dictionaryResource.Lifetime.Min, dictionaryResource.Lifetime.Max))
+	}
+	if dictionaryResource.Comment != "" {
+		parts = append(parts, "COMMENT "+common.QuoteString(dictionaryResource.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildAttributeDefinition(attribute AttributeResource) string {
+	parts := []string{common.QuoteIdentifier(attribute.Name), attribute.Type}
+	if attribute.Default != "" {
+		parts = append(parts, "DEFAULT "+attribute.Default)
+	}
+	if attribute.Expression != "" {
+		parts = append(parts, "EXPRESSION "+attribute.Expression)
+	}
+	if attribute.Hierarchical {
+		parts = append(parts, "HIERARCHICAL")
+	}
+	if attribute.Injective {
+		parts = append(parts, "INJECTIVE")
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildSourceDefinition(source SourceResource) string {
+	parameters := make([]string, 0, len(source.Parameters))
+	for _, parameter := range source.Parameters {
+		// Nested definitions like CREDENTIALS(USER 'user' PASSWORD 'password') are written as functions
+		if strings.HasPrefix(parameter.Value, "(") {
+			parameters = append(parameters, parameter.Name+parameter.Value)
+		} else {
+			parameters = append(parameters, parameter.Name+" "+parameter.Value)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", source.Type, strings.Join(parameters, " "))
+}
+
+func buildLayoutDefinition(layout string, settings map[string]string) string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parameters := make([]string, 0, len(names))
+	for _, name := range names {
+		parameters = append(parameters, strings.ToUpper(name)+" "+settings[name])
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(layout), strings.Join(parameters, " "))
+}
@@ -0,0 +1,46 @@
+package resourcefunction
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+// createFunctionRegex splits the create query reported by system.functions,
+// e.g. CREATE FUNCTION linear_equation AS (x, k, b) -> ((k * x) + b)
+var createFunctionRegex = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?FUNCTION\s+(` + "`(?:[^`\\\\]|\\\\.)*`" + `|\S+)(?:\s+ON\s+CLUSTER\s+\S+)?\s+AS\s+(?:\((.*?)\)|(\w+))\s*->\s*(.+?)\s*;?\s*$`)
+
+type CHFunction struct {
+	Name        string `ch:"name"`
+	CreateQuery string `ch:"create_query"`
+}
+
+type FunctionResource struct {
+	Name       string
+	Cluster    string
+	Parameters []string
+	Expression string
+	// CreateQuery is the definition as formatted by the server
+	CreateQuery string
+}
+
+func (f *CHFunction) ToResource() (*FunctionResource, error) {
+	parts := createFunctionRegex.FindStringSubmatch(f.CreateQuery)
+	if parts == nil {
+		return nil, fmt.Errorf("unexpected create query of function %s: %q", f.Name, f.CreateQuery)
+	}
+
+	functionResource := FunctionResource{Name: f.Name, Expression: parts[4], CreateQuery: f.CreateQuery, Parameters: []string{}}
+	parameters := parts[2]
+	if parts[3] != "" {
+		parameters = parts[3]
+	}
+	for _, parameter := range common.SplitTopLevel(parameters) {
+		if parameter = strings.Trim(parameter, "`"); parameter != "" {
+			functionResource.Parameters = append(functionResource.Parameters, parameter)
+		}
+	}
+	return &functionResource, nil
+}
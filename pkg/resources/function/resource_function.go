@@ -0,0 +1,187 @@
+package resourcefunction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceFunction() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage SQL user defined functions, created and updated with CREATE OR REPLACE FUNCTION",
+
+		CreateContext: resourceFunctionCreate,
+		ReadContext:   resourceFunctionRead,
+		UpdateContext: resourceFunctionUpdate,
+		DeleteContext: resourceFunctionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFunctionImport,
+		},
+		CustomizeDiff: customdiff.ComputedIf("create_query", func(ctx context.Context, d *schema.ResourceDiff, meta any) bool {
+			return d.HasChanges("parameters", "expression")
+		}),
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Function Name",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the function is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"parameters": {
+				Description: "Parameters of the lambda",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"expression": {
+				Description:      "Body of the lambda, e.g. `k * x + b`",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentExpression,
+			},
+			"create_query": {
+				Description: "Definition of the function as formatted by the server, used to detect functions redefined outside of Terraform",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceFunctionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	conn := client.ClickhouseConnection
+
+	functionName := d.Get("name").(string)
+
+	chFunctionService := CHFunctionService{CHConnection: conn}
+	chFunction, err := chFunctionService.GetFunction(ctx, functionName)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse function: %v", err))
+	}
+	if chFunction == nil {
+		d.SetId("")
+		return diags
+	}
+
+	functionResource, err := chFunction.ToResource()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("transforming Clickhouse function to resource: %v", err))
+	}
+
+	// The server reformats the lambda, so the configured definition is kept as long as the server one is the
+	// definition created by the last apply. A function redefined by hand is read back as reported by the server.
+	if common.NormalizeQuery(d.Get("create_query").(string)) != common.NormalizeQuery(functionResource.CreateQuery) {
+		if err := d.Set("parameters", functionResource.Parameters); err != nil {
+			return diag.FromErr(fmt.Errorf("setting parameters: %v", err))
+		}
+		if err := d.Set("expression", functionResource.Expression); err != nil {
+			return diag.FromErr(fmt.Errorf("setting expression: %v", err))
+		}
+	}
+	if err := d.Set("name", functionResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("create_query", functionResource.CreateQuery); err != nil {
+		return diag.FromErr(fmt.Errorf("setting create_query: %v", err))
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + functionName)
+
+	return diags
+}
+
+func resourceFunctionImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("name", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceFunctionCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	diags := resourceFunctionApply(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(d.Get("cluster").(string) + ":" + d.Get("name").(string))
+	return diags
+}
+
+func resourceFunctionUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	return resourceFunctionApply(ctx, d, meta)
+}
+
+// resourceFunctionApply replaces the function and records its definition as formatted by the server
+func resourceFunctionApply(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chFunctionService := CHFunctionService{CHConnection: client.ClickhouseConnection}
+
+	functionResource := functionResourceFromData(d, client)
+	if err := chFunctionService.CreateFunction(ctx, functionResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	chFunction, err := chFunctionService.GetFunction(ctx, functionResource.Name)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse function: %v", err))
+	}
+	if chFunction == nil {
+		return diag.FromErr(fmt.Errorf("function %s not found after creating it", functionResource.Name))
+	}
+	if err := d.Set("create_query", chFunction.CreateQuery); err != nil {
+		return diag.FromErr(fmt.Errorf("setting create_query: %v", err))
+	}
+	return diags
+}
+
+func resourceFunctionDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chFunctionService := CHFunctionService{CHConnection: client.ClickhouseConnection}
+
+	if err := chFunctionService.DeleteFunction(ctx, functionResourceFromData(d, client)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func functionResourceFromData(d *schema.ResourceData, client *common.ApiClient) FunctionResource {
+	functionResource := FunctionResource{
+		Name:       d.Get("name").(string),
+		Cluster:    d.Get("cluster").(string),
+		Parameters: common.MapArrayInterfaceToArrayOfStrings(d.Get("parameters").([]interface{})),
+		Expression: d.Get("expression").(string),
+	}
+	if functionResource.Cluster == "" {
+		functionResource.Cluster = client.DefaultCluster
+	}
+	return functionResource
+}
+
+func suppressEquivalentExpression(k, old, new string, d *schema.ResourceData) bool {
+	return common.NormalizeQuery(old) == common.NormalizeQuery(new)
+}
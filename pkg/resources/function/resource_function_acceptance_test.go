@@ -0,0 +1,48 @@
+package resourcefunction_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceFunctionName = "test_linear_equation"
+
+func TestAccResourceFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: functionConfig(testResourceFunctionName, "k * x + b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_function.function", "name", testResourceFunctionName),
+					resource.TestCheckResourceAttr("clickhouse_function.function", "parameters.#", "3"),
+					resource.TestCheckResourceAttr("clickhouse_function.function", "expression", "k * x + b"),
+				),
+			},
+			// REPLACE THE LAMBDA
+			{
+				Config: functionConfig(testResourceFunctionName, "k * x - b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_function.function", "expression", "k * x - b"),
+				),
+			},
+		},
+	})
+}
+
+func functionConfig(name string, expression string) string {
+	s := `
+	resource "clickhouse_function" "function" {
+		name = "%_name_%"
+		parameters = ["x", "k", "b"]
+		expression = "%_expression_%"
+	}
+	`
+	s = strings.Replace(s, "%_name_%", name, -1)
+	s = strings.Replace(s, "%_expression_%", expression, -1)
+	return s
+}
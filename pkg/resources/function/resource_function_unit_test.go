@@ -0,0 +1,46 @@
+package resourcefunction
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildCreateFunctionSentence(t *testing.T) {
+	functionResource := FunctionResource{Name: "linear_equation", Cluster: "main", Parameters: []string{"x", "k", "b"}, Expression: "k * x + b"}
+	want := "CREATE OR REPLACE FUNCTION `linear_equation` ON CLUSTER `main` AS (`x`, `k`, `b`) -> k * x + b"
+	if got := buildCreateFunctionSentence(functionResource); got != want {
+		t.Errorf("buildCreateFunctionSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildDropFunctionSentence(FunctionResource{Name: "linear_equation"}), "DROP FUNCTION `linear_equation`"; got != want {
+		t.Errorf("buildDropFunctionSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestCHFunctionToResource(t *testing.T) {
+	tests := []struct {
+		createQuery string
+		parameters  []string
+		expression  string
+	}{
+		{"CREATE FUNCTION linear_equation AS (x, k, b) -> ((k * x) + b)", []string{"x", "k", "b"}, "((k * x) + b)"},
+		{"CREATE FUNCTION `my-func` AS (`a b`) -> concat(`a b`, '->')", []string{"a b"}, "concat(`a b`, '->')"},
+		{"CREATE FUNCTION answer AS () -> 42", []string{}, "42"},
+		{"CREATE FUNCTION twice AS x -> (x * 2)", []string{"x"}, "(x * 2)"},
+	}
+
+	for _, tt := range tests {
+		chFunction := CHFunction{Name: "f", CreateQuery: tt.createQuery}
+		functionResource, err := chFunction.ToResource()
+		if err != nil {
+			t.Errorf("ToResource(%q) unexpected error: %v", tt.createQuery, err)
+			continue
+		}
+		if !reflect.DeepEqual(functionResource.Parameters, tt.parameters) || functionResource.Expression != tt.expression {
+			t.Errorf("ToResource(%q) = %q, %q, want %q, %q", tt.createQuery, functionResource.Parameters, functionResource.Expression, tt.parameters, tt.expression)
+		}
+	}
+
+	if _, err := (&CHFunction{Name: "f", CreateQuery: "CREATE TABLE f"}).ToResource(); err == nil {
+		t.Errorf("ToResource() expected an error")
+	}
+}
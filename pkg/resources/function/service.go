@@ -0,0 +1,51 @@
+package resourcefunction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHFunctionService struct {
+	CHConnection *driver.Conn
+}
+
+// GetFunction returns the SQL user defined function, or nil when it does not exist
+func (fs *CHFunctionService) GetFunction(ctx context.Context, name string) (*CHFunction, error) {
+	query := "SELECT name, create_query FROM system.functions where name = {function:String} and origin = 'SQLUserDefined'"
+	row := (*fs.CHConnection).QueryRow(common.QueryParameters(ctx, map[string]string{"function": name}), query)
+
+	if row.Err() != nil {
+		return nil, fmt.Errorf("reading function from Clickhouse: %v", row.Err())
+	}
+
+	var chFunction CHFunction
+	err := row.ScanStruct(&chFunction)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse function row: %v", err)
+	}
+	return &chFunction, nil
+}
+
+func (fs *CHFunctionService) CreateFunction(ctx context.Context, functionResource FunctionResource) error {
+	err := (*fs.CHConnection).Exec(ctx, buildCreateFunctionSentence(functionResource))
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse function: %v", err)
+	}
+	return nil
+}
+
+func (fs *CHFunctionService) DeleteFunction(ctx context.Context, functionResource FunctionResource) error {
+	err := (*fs.CHConnection).Exec(ctx, buildDropFunctionSentence(functionResource))
+	if err != nil {
+		return fmt.Errorf("deleting Clickhouse function: %v", err)
+	}
+	return nil
+}
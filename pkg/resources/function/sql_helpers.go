@@ -0,0 +1,23 @@
+package resourcefunction
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildCreateFunctionSentence(functionResource FunctionResource) string {
+	parts := []string{"CREATE OR REPLACE FUNCTION", common.QuoteIdentifier(functionResource.Name)}
+	if clusterStatement := common.GetClusterStatement(functionResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	lambda := fmt.Sprintf("(%s) -> %s", strings.Join(common.QuoteIdentifiers(functionResource.Parameters), ", "), functionResource.Expression)
+	return strings.Join(append(parts, "AS", lambda), " ")
+}
+
+func buildDropFunctionSentence(functionResource FunctionResource) string {
+	return strings.Join(strings.Fields(fmt.Sprintf("DROP FUNCTION %s %s",
+		common.QuoteIdentifier(functionResource.Name),
+		common.GetClusterStatement(functionResource.Cluster))), " ")
+}
@@ -0,0 +1,165 @@
+package resourcegrant
+
+import "strings"
+
+// allDatabases is the database of grants on *.*
+const allDatabases = "*"
+
+// CHGrant is the set of rows of system.grants of a grantee on a database or a table
+type CHGrant struct {
+	Grantee  string
+	Database string
+	Table    string
+	Rows     []CHGrantRow
+}
+
+type CHGrantRow struct {
+	AccessType  string  `ch:"access_type"`
+	Column      *string `ch:"column"`
+	GrantOption uint8   `ch:"grant_option"`
+}
+
+type GrantResource struct {
+	Grantee         string
+	Database        string
+	Table           string
+	Columns         []string
+	Privileges      []string
+	WithGrantOption bool
+	Cluster         string
+}
+
+// privilegeColumn is a privilege granted on a column, or on the whole target when the column is empty
+type privilegeColumn struct {
+	Privilege string
+	Column    string
+}
+
+// ToResource returns the grant made of the rows owned by the resource, or nil when there are none. The rows of the
+// privileges and columns of the state are owned by the resource when it has columns, otherwise the rows of the
+// privileges on the whole target are. Other rows on the same target belong to other grants. An imported grant,
+// without privileges in the state, owns every row on the whole target, or its column rows when there are none.
+// Privileges are matched in any case and keep the spelling of the state.
+func (g *CHGrant) ToResource(statePrivileges []string, stateColumns []string) *GrantResource {
+	ownedPrivileges := make(map[string]string, len(statePrivileges))
+	for _, privilege := range statePrivileges {
+		ownedPrivileges[privilegeKey(privilege)] = privilege
+	}
+	ownedColumns := make(map[string]bool, len(stateColumns))
+	for _, column := range stateColumns {
+		ownedColumns[column] = true
+	}
+
+	var tableRows, columnRows []CHGrantRow
+	for _, row := range g.Rows {
+		if _, owned := ownedPrivileges[privilegeKey(row.AccessType)]; len(statePrivileges) > 0 && !owned {
+			continue
+		}
+		if row.Column == nil {
+			tableRows = append(tableRows, row)
+		} else if len(stateColumns) == 0 || ownedColumns[*row.Column] {
+			columnRows = append(columnRows, row)
+		}
+	}
+	rows := tableRows
+	if len(stateColumns) > 0 || len(tableRows) == 0 {
+		rows = columnRows
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	grantResource := GrantResource{
+		Grantee:         g.Grantee,
+		Database:        g.Database,
+		Table:           g.Table,
+		Columns:         []string{},
+		Privileges:      []string{},
+		WithGrantOption: true,
+	}
+	privileges := map[string]bool{}
+	columns := map[string]bool{}
+	for _, row := range rows {
+		if key := privilegeKey(row.AccessType); !privileges[key] {
+			privileges[key] = true
+			privilege, owned := ownedPrivileges[key]
+			if !owned {
+				privilege = row.AccessType
+			}
+			grantResource.Privileges = append(grantResource.Privileges, privilege)
+		}
+		if row.Column != nil && !columns[*row.Column] {
+			columns[*row.Column] = true
+			grantResource.Columns = append(grantResource.Columns, *row.Column)
+		}
+		if row.GrantOption == 0 {
+			grantResource.WithGrantOption = false
+		}
+	}
+	return &grantResource
+}
+
+// privilegeKey returns the privilege in upper case, ClickHouse accepts privileges in any case but reports them in
+// their own spelling, like SELECT or dictGet
+func privilegeKey(privilege string) string {
+	return strings.ToUpper(privilege)
+}
+
+// privilegeColumns expands the privileges of the grant on each of its columns
+func (r *GrantResource) privilegeColumns() []privilegeColumn {
+	var privilegeColumns []privilegeColumn
+	for _, privilege := range r.Privileges {
+		if len(r.Columns) == 0 {
+			privilegeColumns = append(privilegeColumns, privilegeColumn{Privilege: privilege})
+			continue
+		}
+		for _, column := range r.Columns {
+			privilegeColumns = append(privilegeColumns, privilegeColumn{Privilege: privilege, Column: column})
+		}
+	}
+	return privilegeColumns
+}
+
+// privilegeColumns returns the privileges granted by the rows
+func (g *CHGrant) privilegeColumns() []privilegeColumn {
+	privilegeColumns := make([]privilegeColumn, 0, len(g.Rows))
+	for _, row := range g.Rows {
+		privilegeColumn := privilegeColumn{Privilege: row.AccessType}
+		if row.Column != nil {
+			privilegeColumn.Column = *row.Column
+		}
+		privilegeColumns = append(privilegeColumns, privilegeColumn)
+	}
+	return privilegeColumns
+}
+
+// overlappingPrivilegeColumns returns the privilege columns of a that ClickHouse merges with the ones of b:
+// the same privilege on the same column, or on any column when either is granted on the whole target
+func overlappingPrivilegeColumns(a []privilegeColumn, b []privilegeColumn) []privilegeColumn {
+	var overlapping []privilegeColumn
+	for _, aPrivilegeColumn := range a {
+		for _, bPrivilegeColumn := range b {
+			if privilegeKey(aPrivilegeColumn.Privilege) == privilegeKey(bPrivilegeColumn.Privilege) &&
+				(aPrivilegeColumn.Column == bPrivilegeColumn.Column || aPrivilegeColumn.Column == "" || bPrivilegeColumn.Column == "") {
+				overlapping = append(overlapping, aPrivilegeColumn)
+				break
+			}
+		}
+	}
+	return overlapping
+}
+
+// subtractPrivilegeColumns returns the privilege columns of a missing in b
+func subtractPrivilegeColumns(a []privilegeColumn, b []privilegeColumn) []privilegeColumn {
+	existing := make(map[privilegeColumn]bool, len(b))
+	for _, bPrivilegeColumn := range b {
+		existing[privilegeColumn{Privilege: privilegeKey(bPrivilegeColumn.Privilege), Column: bPrivilegeColumn.Column}] = true
+	}
+	var missing []privilegeColumn
+	for _, aPrivilegeColumn := range a {
+		if !existing[privilegeColumn{Privilege: privilegeKey(aPrivilegeColumn.Privilege), Column: aPrivilegeColumn.Column}] {
+			missing = append(missing, aPrivilegeColumn)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,203 @@
+package resourcegrant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceGrant() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage the privileges of a user or a role on a database, a table or columns of a table. " +
+			"It owns exactly the privileges it grants, so several grants can target the same grantee and target as long as their privileges don't overlap: " +
+			"privileges already granted on the target, by another grant or by a `clickhouse_role` on its database, are rejected",
+
+		CreateContext: resourceGrantCreate,
+		ReadContext:   resourceGrantRead,
+		UpdateContext: resourceGrantUpdate,
+		DeleteContext: resourceGrantDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGrantImport,
+		},
+		CustomizeDiff: validateGrantTarget,
+		Schema: map[string]*schema.Schema{
+			"grantee": {
+				Description: "User or role receiving the privileges",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"privileges": {
+				Description: "Granted privileges, like SELECT, INSERT or ALTER UPDATE. They are matched with the ones reported by system.grants in any case",
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"database": {
+				Description: "Database of the privileges, '*' grants them on all databases",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"table": {
+				Description: "Table of the privileges, they are granted on the whole database when empty",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"columns": {
+				Description:  "Columns of the table the privileges are restricted to",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				RequiredWith: []string{"table"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"with_grant_option": {
+				Description: "Allow the grantee to grant these privileges to others",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"cluster": {
+				Description: "Cluster Name, the privileges are granted ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func validateGrantTarget(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Get("database").(string) == allDatabases && d.Get("table").(string) != "" {
+		return fmt.Errorf("table: privileges on a table require a database, not '*'")
+	}
+	return nil
+}
+
+func resourceGrantRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	chGrantService := CHGrantService{CHConnection: client.ClickhouseConnection}
+
+	grantee := d.Get("grantee").(string)
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	chGrant, err := chGrantService.GetGrant(ctx, grantee, database, table)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse grant: %v", err))
+	}
+	grantResource := chGrant.ToResource(common.StringSetToList(d.Get("privileges").(*schema.Set)), common.StringSetToList(d.Get("columns").(*schema.Set)))
+	if grantResource == nil {
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("privileges", common.StringListToSet(grantResource.Privileges)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting privileges: %v", err))
+	}
+	if err := d.Set("columns", common.StringListToSet(grantResource.Columns)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting columns: %v", err))
+	}
+	if err := d.Set("with_grant_option", grantResource.WithGrantOption); err != nil {
+		return diag.FromErr(fmt.Errorf("setting with_grant_option: %v", err))
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + database + ":" + table + ":" + grantee)
+
+	return diags
+}
+
+// Grants are imported by cluster, database, table and grantee, the table is empty for database grants.
+// An imported grant owns every privilege of the grantee on the target.
+func resourceGrantImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "database", "table", "grantee")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("database", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting database: %v", err)
+	}
+	if err := d.Set("table", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting table: %v", err)
+	}
+	if err := d.Set("grantee", idParts[3]); err != nil {
+		return nil, fmt.Errorf("setting grantee: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chGrantService := CHGrantService{CHConnection: client.ClickhouseConnection}
+
+	grantResource := grantResourceFromData(d, client)
+	if err := chGrantService.CreateGrant(ctx, grantResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + grantResource.Database + ":" + grantResource.Table + ":" + grantResource.Grantee)
+
+	return resourceGrantRead(ctx, d, meta)
+}
+
+func resourceGrantUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chGrantService := CHGrantService{CHConnection: client.ClickhouseConnection}
+
+	grantResource := grantResourceFromData(d, client)
+	statePrivileges, _ := d.GetChange("privileges")
+	stateColumns, _ := d.GetChange("columns")
+	stateWithGrantOption, _ := d.GetChange("with_grant_option")
+	stateGrant := grantResource
+	stateGrant.Privileges = common.StringSetToList(statePrivileges.(*schema.Set))
+	stateGrant.Columns = common.StringSetToList(stateColumns.(*schema.Set))
+	stateGrant.WithGrantOption = stateWithGrantOption.(bool)
+
+	if err := chGrantService.UpdateGrant(ctx, stateGrant, grantResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceGrantRead(ctx, d, meta)
+}
+
+func resourceGrantDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chGrantService := CHGrantService{CHConnection: client.ClickhouseConnection}
+
+	if err := chGrantService.DeleteGrant(ctx, grantResourceFromData(d, client)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func grantResourceFromData(d *schema.ResourceData, client *common.ApiClient) GrantResource {
+	grantResource := GrantResource{
+		Grantee:         d.Get("grantee").(string),
+		Database:        d.Get("database").(string),
+		Table:           d.Get("table").(string),
+		Columns:         common.StringSetToList(d.Get("columns").(*schema.Set)),
+		Privileges:      common.StringSetToList(d.Get("privileges").(*schema.Set)),
+		WithGrantOption: d.Get("with_grant_option").(bool),
+		Cluster:         d.Get("cluster").(string),
+	}
+	if grantResource.Cluster == "" {
+		grantResource.Cluster = client.DefaultCluster
+	}
+	return grantResource
+}
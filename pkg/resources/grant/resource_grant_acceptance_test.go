@@ -0,0 +1,121 @@
+package resourcegrant_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceGrant(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: grantConfig(`["key"]`, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_grant.columns", "columns.#", "1"),
+					resource.TestCheckResourceAttr("clickhouse_grant.columns", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("clickhouse_grant.database", "privileges.#", "2"),
+					resource.TestCheckResourceAttr("clickhouse_role.role", "privileges.#", "1"),
+				),
+			},
+			// GRANT ANOTHER COLUMN WITH GRANT OPTION
+			{
+				Config: grantConfig(`["key", "value"]`, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_grant.columns", "columns.#", "2"),
+					resource.TestCheckResourceAttr("clickhouse_grant.columns", "with_grant_option", "true"),
+				),
+			},
+			{
+				ResourceName:      "clickhouse_grant.database",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceGrant_OverlappingRole(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "clickhouse_db" "db" {
+					name = "test_grant_overlap_db"
+				}
+
+				resource "clickhouse_role" "role" {
+					name = "test_grant_overlap_role"
+					database = clickhouse_db.db.name
+					privileges = ["SELECT"]
+				}
+
+				resource "clickhouse_grant" "overlap" {
+					grantee = clickhouse_role.role.name
+					database = clickhouse_db.db.name
+					privileges = ["SELECT"]
+				}
+				`,
+				ExpectError: regexp.MustCompile("already granted to test_grant_overlap_role by another grant or role"),
+			},
+		},
+	})
+}
+
+func grantConfig(columns string, withGrantOption string) string {
+	s := `
+	resource "clickhouse_db" "db" {
+		name = "test_grant_db"
+	}
+
+	resource "clickhouse_db" "other_db" {
+		name = "test_grant_other_db"
+	}
+
+	resource "clickhouse_table" "table" {
+		database = clickhouse_db.db.name
+		name = "events"
+		engine = "MergeTree"
+		order_by = ["key"]
+		column {
+			name = "key"
+			type = "Int64"
+		}
+		column {
+			name = "value"
+			type = "String"
+		}
+	}
+
+	resource "clickhouse_role" "role" {
+		name = "test_grant_role"
+		database = clickhouse_db.db.name
+		privileges = ["SHOW TABLES"]
+	}
+
+	resource "clickhouse_grant" "database" {
+		grantee = clickhouse_role.role.name
+		database = clickhouse_db.other_db.name
+		privileges = ["SELECT", "INSERT"]
+	}
+
+	resource "clickhouse_grant" "columns" {
+		grantee = clickhouse_role.role.name
+		database = clickhouse_db.db.name
+		table = clickhouse_table.table.name
+		columns = %_columns_%
+		privileges = ["SELECT"]
+		with_grant_option = %_with_grant_option_%
+	}
+	`
+	s = strings.Replace(s, "%_columns_%", columns, -1)
+	s = strings.Replace(s, "%_with_grant_option_%", withGrantOption, -1)
+	return s
+}
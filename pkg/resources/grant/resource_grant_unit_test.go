@@ -0,0 +1,115 @@
+package resourcegrant
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildGrantSentences(t *testing.T) {
+	grantResource := GrantResource{Grantee: "analyst", Database: "events", Table: "clicks", Columns: []string{"id", "url"}, Privileges: []string{"SELECT", "INSERT"}, Cluster: "main"}
+
+	want := "GRANT ON CLUSTER `main` SELECT(`id`, `url`), INSERT(`id`, `url`) ON `events`.`clicks` TO `analyst` WITH GRANT OPTION"
+	if got := buildGrantSentence(grantResource, grantResource.privilegeColumns(), true); got != want {
+		t.Errorf("buildGrantSentence() = %s, want %s", got, want)
+	}
+
+	want = "REVOKE ON CLUSTER `main` GRANT OPTION FOR SELECT(`id`, `url`), INSERT(`id`, `url`) ON `events`.`clicks` FROM `analyst`"
+	if got := buildRevokeSentence(grantResource, grantResource.privilegeColumns(), true); got != want {
+		t.Errorf("buildRevokeSentence() = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		grantResource GrantResource
+		want          string
+	}{
+		{GrantResource{Grantee: "analyst", Database: "events", Privileges: []string{"SELECT"}}, "GRANT SELECT ON `events`.* TO `analyst`"},
+		{GrantResource{Grantee: "analyst", Database: "*", Privileges: []string{"SHOW TABLES"}}, "GRANT SHOW TABLES ON *.* TO `analyst`"},
+	}
+	for _, tt := range tests {
+		if got := buildGrantSentence(tt.grantResource, tt.grantResource.privilegeColumns(), false); got != tt.want {
+			t.Errorf("buildGrantSentence() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestSubtractPrivilegeColumns(t *testing.T) {
+	state := GrantResource{Privileges: []string{"SELECT", "INSERT"}, Columns: []string{"id"}}
+	plan := GrantResource{Privileges: []string{"SELECT"}, Columns: []string{"id", "url"}}
+
+	want := []privilegeColumn{{Privilege: "INSERT", Column: "id"}}
+	if got := subtractPrivilegeColumns(state.privilegeColumns(), plan.privilegeColumns()); !reflect.DeepEqual(got, want) {
+		t.Errorf("revoked = %+v, want %+v", got, want)
+	}
+	want = []privilegeColumn{{Privilege: "SELECT", Column: "url"}}
+	if got := subtractPrivilegeColumns(plan.privilegeColumns(), state.privilegeColumns()); !reflect.DeepEqual(got, want) {
+		t.Errorf("granted = %+v, want %+v", got, want)
+	}
+
+	lowercasePlan := GrantResource{Privileges: []string{"select", "insert"}, Columns: []string{"id"}}
+	if got := subtractPrivilegeColumns(state.privilegeColumns(), lowercasePlan.privilegeColumns()); len(got) > 0 {
+		t.Errorf("revoked after lowercasing = %+v, want none", got)
+	}
+}
+
+func TestCHGrantToResource(t *testing.T) {
+	id, url, ip := "id", "url", "ip"
+	chGrant := CHGrant{
+		Grantee:  "analyst",
+		Database: "events",
+		Table:    "clicks",
+		Rows: []CHGrantRow{
+			{AccessType: "SELECT", Column: &id, GrantOption: 1},
+			{AccessType: "SELECT", Column: &url, GrantOption: 1},
+			{AccessType: "SELECT", Column: &ip},
+			{AccessType: "INSERT", GrantOption: 1},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		statePrivileges []string
+		stateColumns    []string
+		want            *GrantResource
+	}{
+		{"imported table rows", nil, nil, &GrantResource{Grantee: "analyst", Database: "events", Table: "clicks", Columns: []string{}, Privileges: []string{"INSERT"}, WithGrantOption: true}},
+		{"owned table rows", []string{"INSERT"}, nil, &GrantResource{Grantee: "analyst", Database: "events", Table: "clicks", Columns: []string{}, Privileges: []string{"INSERT"}, WithGrantOption: true}},
+		{"owned lowercase table rows", []string{"insert"}, nil, &GrantResource{Grantee: "analyst", Database: "events", Table: "clicks", Columns: []string{}, Privileges: []string{"insert"}, WithGrantOption: true}},
+		{"table rows of another grant", []string{"ALTER UPDATE"}, nil, nil},
+		{"owned column rows", []string{"SELECT"}, []string{"id", "url"}, &GrantResource{Grantee: "analyst", Database: "events", Table: "clicks", Columns: []string{"id", "url"}, Privileges: []string{"SELECT"}, WithGrantOption: true}},
+		{"column rows of another grant", []string{"INSERT"}, []string{"id", "url"}, nil},
+		{"missing column rows", []string{"SELECT"}, []string{"name"}, nil},
+	}
+	for _, tt := range tests {
+		if got := chGrant.ToResource(tt.statePrivileges, tt.stateColumns); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ToResource() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	chGrant.Rows = chGrant.Rows[:3]
+	got := chGrant.ToResource(nil, nil)
+	if got == nil || !reflect.DeepEqual(got.Columns, []string{"id", "url", "ip"}) || got.WithGrantOption {
+		t.Errorf("imported column rows: ToResource() = %+v", got)
+	}
+}
+
+func TestOverlappingPrivilegeColumns(t *testing.T) {
+	existing := []privilegeColumn{{Privilege: "SELECT", Column: "id"}, {Privilege: "INSERT"}}
+
+	tests := []struct {
+		name    string
+		granted []privilegeColumn
+		want    []privilegeColumn
+	}{
+		{"same column", []privilegeColumn{{Privilege: "SELECT", Column: "id"}}, []privilegeColumn{{Privilege: "SELECT", Column: "id"}}},
+		{"other column", []privilegeColumn{{Privilege: "SELECT", Column: "url"}}, nil},
+		{"whole target over a column", []privilegeColumn{{Privilege: "SELECT"}}, []privilegeColumn{{Privilege: "SELECT"}}},
+		{"column under the whole target", []privilegeColumn{{Privilege: "INSERT", Column: "url"}}, []privilegeColumn{{Privilege: "INSERT", Column: "url"}}},
+		{"lowercase privilege", []privilegeColumn{{Privilege: "select", Column: "id"}}, []privilegeColumn{{Privilege: "select", Column: "id"}}},
+		{"other privilege", []privilegeColumn{{Privilege: "ALTER UPDATE"}}, nil},
+	}
+	for _, tt := range tests {
+		if got := overlappingPrivilegeColumns(tt.granted, existing); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: overlappingPrivilegeColumns() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
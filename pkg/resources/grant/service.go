@@ -0,0 +1,112 @@
+package resourcegrant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHGrantService struct {
+	CHConnection *driver.Conn
+}
+
+// GetGrant returns the privileges of the grantee on the database, or on the table when it is not empty
+func (gs *CHGrantService) GetGrant(ctx context.Context, grantee string, database string, table string) (*CHGrant, error) {
+	conditions := []string{"(user_name = {grantee:String} OR role_name = {grantee:String})", "is_partial_revoke = 0"}
+	if database == allDatabases {
+		conditions = append(conditions, "database IS NULL")
+	} else {
+		conditions = append(conditions, "database = {database:String}")
+	}
+	if table == "" {
+		conditions = append(conditions, "table IS NULL")
+	} else {
+		conditions = append(conditions, "table = {table:String}")
+	}
+
+	ctx = common.QueryParameters(ctx, map[string]string{"grantee": grantee, "database": database, "table": table})
+	rows, err := (*gs.CHConnection).Query(ctx, "SELECT toString(access_type) AS access_type, column, grant_option FROM system.grants WHERE "+strings.Join(conditions, " AND "))
+	if err != nil {
+		return nil, fmt.Errorf("reading grants from Clickhouse: %v", err)
+	}
+
+	chGrant := CHGrant{Grantee: grantee, Database: database, Table: table}
+	for rows.Next() {
+		var row CHGrantRow
+		if err := rows.ScanStruct(&row); err != nil {
+			return nil, fmt.Errorf("scanning Clickhouse grant row: %v", err)
+		}
+		chGrant.Rows = append(chGrant.Rows, row)
+	}
+	return &chGrant, nil
+}
+
+// checkOverlappingPrivileges fails when privileges to grant are already granted on the target and not owned by the
+// resource, as they belong to another grant or to a role, which would then read and revoke each other's privileges
+func (gs *CHGrantService) checkOverlappingPrivileges(ctx context.Context, grantResource GrantResource, granted []privilegeColumn, owned []privilegeColumn) error {
+	chGrant, err := gs.GetGrant(ctx, grantResource.Grantee, grantResource.Database, grantResource.Table)
+	if err != nil {
+		return err
+	}
+	others := subtractPrivilegeColumns(chGrant.privilegeColumns(), owned)
+	if overlapping := overlappingPrivilegeColumns(granted, others); len(overlapping) > 0 {
+		return fmt.Errorf("%s on %s already granted to %s by another grant or role, import it or remove it from there", buildPrivileges(overlapping), buildGrantTarget(grantResource), grantResource.Grantee)
+	}
+	return nil
+}
+
+func (gs *CHGrantService) CreateGrant(ctx context.Context, grantResource GrantResource) error {
+	if err := gs.checkOverlappingPrivileges(ctx, grantResource, grantResource.privilegeColumns(), nil); err != nil {
+		return err
+	}
+	err := (*gs.CHConnection).Exec(ctx, buildGrantSentence(grantResource, grantResource.privilegeColumns(), grantResource.WithGrantOption))
+	if err != nil {
+		return fmt.Errorf("granting Clickhouse privileges: %v", err)
+	}
+	return nil
+}
+
+// UpdateGrant revokes the privileges of the state missing in the plan and grants the new ones
+func (gs *CHGrantService) UpdateGrant(ctx context.Context, stateGrant GrantResource, grantResource GrantResource) error {
+	conn := *gs.CHConnection
+	statePrivilegeColumns := stateGrant.privilegeColumns()
+	planPrivilegeColumns := grantResource.privilegeColumns()
+
+	granted := subtractPrivilegeColumns(planPrivilegeColumns, statePrivilegeColumns)
+	if err := gs.checkOverlappingPrivileges(ctx, grantResource, granted, statePrivilegeColumns); err != nil {
+		return err
+	}
+
+	if revoked := subtractPrivilegeColumns(statePrivilegeColumns, planPrivilegeColumns); len(revoked) > 0 {
+		if err := conn.Exec(ctx, buildRevokeSentence(grantResource, revoked, false)); err != nil {
+			return fmt.Errorf("revoking Clickhouse privileges: %v", err)
+		}
+	}
+
+	if grantResource.WithGrantOption && !stateGrant.WithGrantOption {
+		granted = planPrivilegeColumns
+	}
+	if len(granted) > 0 {
+		if err := conn.Exec(ctx, buildGrantSentence(grantResource, granted, grantResource.WithGrantOption)); err != nil {
+			return fmt.Errorf("granting Clickhouse privileges: %v", err)
+		}
+	}
+
+	if stateGrant.WithGrantOption && !grantResource.WithGrantOption && len(planPrivilegeColumns) > 0 {
+		if err := conn.Exec(ctx, buildRevokeSentence(grantResource, planPrivilegeColumns, true)); err != nil {
+			return fmt.Errorf("revoking Clickhouse grant option: %v", err)
+		}
+	}
+	return nil
+}
+
+func (gs *CHGrantService) DeleteGrant(ctx context.Context, grantResource GrantResource) error {
+	err := (*gs.CHConnection).Exec(ctx, buildRevokeSentence(grantResource, grantResource.privilegeColumns(), false))
+	if err != nil {
+		return fmt.Errorf("revoking Clickhouse privileges: %v", err)
+	}
+	return nil
+}
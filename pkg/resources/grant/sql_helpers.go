@@ -0,0 +1,69 @@
+package resourcegrant
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildGrantSentence(grantResource GrantResource, privilegeColumns []privilegeColumn, withGrantOption bool) string {
+	parts := []string{"GRANT"}
+	if clusterStatement := common.GetClusterStatement(grantResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	parts = append(parts, buildPrivileges(privilegeColumns), "ON", buildGrantTarget(grantResource), "TO", common.QuoteIdentifier(grantResource.Grantee))
+	if withGrantOption {
+		parts = append(parts, "WITH GRANT OPTION")
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildRevokeSentence revokes the privileges, or only the permission to grant them with grantOptionOnly
+func buildRevokeSentence(grantResource GrantResource, privilegeColumns []privilegeColumn, grantOptionOnly bool) string {
+	parts := []string{"REVOKE"}
+	if clusterStatement := common.GetClusterStatement(grantResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if grantOptionOnly {
+		parts = append(parts, "GRANT OPTION FOR")
+	}
+	parts = append(parts, buildPrivileges(privilegeColumns), "ON", buildGrantTarget(grantResource), "FROM", common.QuoteIdentifier(grantResource.Grantee))
+	return strings.Join(parts, " ")
+}
+
+// buildGrantTarget renders *.*, db.* or db.table
+func buildGrantTarget(grantResource GrantResource) string {
+	if grantResource.Database == allDatabases {
+		return "*.*"
+	}
+	if grantResource.Table == "" {
+		return common.QuoteIdentifier(grantResource.Database) + ".*"
+	}
+	return common.QuoteTableName(grantResource.Database, grantResource.Table)
+}
+
+// buildPrivileges renders the privileges with their columns, like SELECT(`a`, `b`), INSERT
+func buildPrivileges(privilegeColumns []privilegeColumn) string {
+	var privileges []string
+	columns := map[string][]string{}
+	for _, privilegeColumn := range privilegeColumns {
+		if _, ok := columns[privilegeColumn.Privilege]; !ok {
+			privileges = append(privileges, privilegeColumn.Privilege)
+			columns[privilegeColumn.Privilege] = []string{}
+		}
+		if privilegeColumn.Column != "" {
+			columns[privilegeColumn.Privilege] = append(columns[privilegeColumn.Privilege], privilegeColumn.Column)
+		}
+	}
+
+	rendered := make([]string, 0, len(privileges))
+	for _, privilege := range privileges {
+		if len(columns[privilege]) == 0 {
+			rendered = append(rendered, privilege)
+		} else {
+			rendered = append(rendered, fmt.Sprintf("%s(%s)", privilege, strings.Join(common.QuoteIdentifiers(columns[privilege]), ", ")))
+		}
+	}
+	return strings.Join(rendered, ", ")
+}
@@ -0,0 +1,90 @@
+package resourcequota
+
+import "strings"
+
+// quotaKeys are the values of KEYED BY, an empty key is a NOT KEYED quota
+var quotaKeys = []string{"user_name", "ip_address", "forwarded_ip_address", "client_key", "client_key,user_name", "client_key,ip_address"}
+
+type CHQuota struct {
+	Name        string   `ch:"name"`
+	Keys        []string `ch:"keys"`
+	ApplyToList []string `ch:"apply_to_list"`
+	Limits      []CHQuotaLimit
+}
+
+// CHQuotaLimit is a row of system.quota_limits, limits are NULL when not set
+type CHQuotaLimit struct {
+	Duration         uint64   `ch:"duration"`
+	Randomized       uint8    `ch:"is_randomized_interval"`
+	MaxQueries       *uint64  `ch:"max_queries"`
+	MaxQuerySelects  *uint64  `ch:"max_query_selects"`
+	MaxQueryInserts  *uint64  `ch:"max_query_inserts"`
+	MaxErrors        *uint64  `ch:"max_errors"`
+	MaxResultRows    *uint64  `ch:"max_result_rows"`
+	MaxResultBytes   *uint64  `ch:"max_result_bytes"`
+	MaxReadRows      *uint64  `ch:"max_read_rows"`
+	MaxReadBytes     *uint64  `ch:"max_read_bytes"`
+	MaxExecutionTime *float64 `ch:"max_execution_time"`
+}
+
+type QuotaResource struct {
+	Name      string
+	Cluster   string
+	KeyedBy   string
+	Intervals []IntervalResource
+	To        []string
+}
+
+// IntervalResource is a FOR INTERVAL clause, zero limits are not set
+type IntervalResource struct {
+	Duration         uint64
+	Randomized       bool
+	MaxQueries       uint64
+	MaxQuerySelects  uint64
+	MaxQueryInserts  uint64
+	MaxErrors        uint64
+	MaxResultRows    uint64
+	MaxResultBytes   uint64
+	MaxReadRows      uint64
+	MaxReadBytes     uint64
+	MaxExecutionTime float64
+}
+
+func (q *CHQuota) ToResource() *QuotaResource {
+	quotaResource := QuotaResource{
+		Name:      q.Name,
+		KeyedBy:   strings.Join(q.Keys, ","),
+		Intervals: []IntervalResource{},
+		To:        q.ApplyToList,
+	}
+	for _, limit := range q.Limits {
+		quotaResource.Intervals = append(quotaResource.Intervals, IntervalResource{
+			Duration:         limit.Duration,
+			Randomized:       limit.Randomized != 0,
+			MaxQueries:       uintValue(limit.MaxQueries),
+			MaxQuerySelects:  uintValue(limit.MaxQuerySelects),
+			MaxQueryInserts:  uintValue(limit.MaxQueryInserts),
+			MaxErrors:        uintValue(limit.MaxErrors),
+			MaxResultRows:    uintValue(limit.MaxResultRows),
+			MaxResultBytes:   uintValue(limit.MaxResultBytes),
+			MaxReadRows:      uintValue(limit.MaxReadRows),
+			MaxReadBytes:     uintValue(limit.MaxReadBytes),
+			MaxExecutionTime: floatValue(limit.MaxExecutionTime),
+		})
+	}
+	return &quotaResource
+}
+
+func uintValue(value *uint64) uint64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func floatValue(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,253 @@
+package resourcequota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// intervalLimits are the integer limits of an interval, execution time is the only fractional one
+var intervalLimits = map[string]string{
+	"max_queries":       "Maximum number of queries",
+	"max_query_selects": "Maximum number of SELECT queries",
+	"max_query_inserts": "Maximum number of INSERT queries",
+	"max_errors":        "Maximum number of queries that threw an exception",
+	"max_result_rows":   "Maximum number of rows given as a result",
+	"max_result_bytes":  "Maximum number of bytes given as a result",
+	"max_read_rows":     "Maximum number of source rows read from tables",
+	"max_read_bytes":    "Maximum number of source bytes read from tables",
+}
+
+func ResourceQuota() *schema.Resource {
+	intervalSchema := map[string]*schema.Schema{
+		"duration": {
+			Description:  "Length of the interval in seconds",
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"randomized": {
+			Description: "Randomize the start of the interval, so intervals of different keys don't start at the same time",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+		},
+		"max_execution_time": {
+			Description:  "Maximum query execution time in seconds",
+			Type:         schema.TypeFloat,
+			Optional:     true,
+			ValidateFunc: validation.FloatAtLeast(0),
+		},
+	}
+	for name, description := range intervalLimits {
+		intervalSchema[name] = &schema.Schema{
+			Description:  description + ", 0 means no limit",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		}
+	}
+
+	return &schema.Resource{
+		Description: "Resource to manage quotas, limiting the resources consumed by users and roles over intervals of time",
+
+		CreateContext: resourceQuotaCreate,
+		ReadContext:   resourceQuotaRead,
+		UpdateContext: resourceQuotaUpdate,
+		DeleteContext: resourceQuotaDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceQuotaImport,
+		},
+		CustomizeDiff: validateIntervals,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Quota name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the quota is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"keyed_by": {
+				Description:  "Key the quota is tracked by, one of user_name, ip_address, forwarded_ip_address, client_key, client_key,user_name or client_key,ip_address. When empty the quota is shared by all its assignees",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(quotaKeys, false),
+			},
+			"interval": {
+				Description: "Intervals with their limits. An interval without limits only tracks the consumption",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: intervalSchema,
+				},
+			},
+			"to": {
+				Description: "Users and roles the quota is assigned to",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// validateIntervals rejects intervals with the same duration, the server keeps a single interval per duration
+func validateIntervals(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	durations := map[int]bool{}
+	for _, item := range d.Get("interval").(*schema.Set).List() {
+		duration := item.(map[string]interface{})["duration"].(int)
+		if durations[duration] {
+			return fmt.Errorf("interval: duration %d is declared more than once", duration)
+		}
+		durations[duration] = true
+	}
+	return nil
+}
+
+func resourceQuotaRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	chQuotaService := CHQuotaService{CHConnection: client.ClickhouseConnection}
+
+	chQuota, err := chQuotaService.GetQuota(ctx, d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse quota: %v", err))
+	}
+	if chQuota == nil {
+		d.SetId("")
+		return diags
+	}
+
+	quotaResource := chQuota.ToResource()
+	if err := d.Set("name", quotaResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("keyed_by", quotaResource.KeyedBy); err != nil {
+		return diag.FromErr(fmt.Errorf("setting keyed_by: %v", err))
+	}
+	if err := d.Set("interval", flattenIntervals(quotaResource.Intervals)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting interval: %v", err))
+	}
+	if err := d.Set("to", common.StringListToSet(quotaResource.To)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting to: %v", err))
+	}
+
+	d.SetId(quotaResource.Name)
+
+	return diags
+}
+
+func resourceQuotaImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if err := d.Set("name", d.Id()); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceQuotaCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chQuotaService := CHQuotaService{CHConnection: client.ClickhouseConnection}
+
+	quotaResource := quotaResourceFromData(d, client)
+	if err := chQuotaService.CreateQuota(ctx, quotaResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(quotaResource.Name)
+
+	return resourceQuotaRead(ctx, d, meta)
+}
+
+func resourceQuotaUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chQuotaService := CHQuotaService{CHConnection: client.ClickhouseConnection}
+
+	stateName, _ := d.GetChange("name")
+	stateIntervals, _ := d.GetChange("interval")
+	quotaResource := quotaResourceFromData(d, client)
+	err := chQuotaService.UpdateQuota(ctx, stateName.(string), expandIntervals(stateIntervals.(*schema.Set)), quotaResource)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(quotaResource.Name)
+
+	return resourceQuotaRead(ctx, d, meta)
+}
+
+func resourceQuotaDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chQuotaService := CHQuotaService{CHConnection: client.ClickhouseConnection}
+
+	if err := chQuotaService.DeleteQuota(ctx, quotaResourceFromData(d, client)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func quotaResourceFromData(d *schema.ResourceData, client *common.ApiClient) QuotaResource {
+	quotaResource := QuotaResource{
+		Name:      d.Get("name").(string),
+		Cluster:   d.Get("cluster").(string),
+		KeyedBy:   d.Get("keyed_by").(string),
+		Intervals: expandIntervals(d.Get("interval").(*schema.Set)),
+		To:        common.StringSetToList(d.Get("to").(*schema.Set)),
+	}
+	if quotaResource.Cluster == "" {
+		quotaResource.Cluster = client.DefaultCluster
+	}
+	return quotaResource
+}
+
+func expandIntervals(set *schema.Set) []IntervalResource {
+	intervals := make([]IntervalResource, 0, set.Len())
+	for _, item := range set.List() {
+		interval := item.(map[string]interface{})
+		intervals = append(intervals, IntervalResource{
+			Duration:         uint64(interval["duration"].(int)),
+			Randomized:       interval["randomized"].(bool),
+			MaxQueries:       uint64(interval["max_queries"].(int)),
+			MaxQuerySelects:  uint64(interval["max_query_selects"].(int)),
+			MaxQueryInserts:  uint64(interval["max_query_inserts"].(int)),
+			MaxErrors:        uint64(interval["max_errors"].(int)),
+			MaxResultRows:    uint64(interval["max_result_rows"].(int)),
+			MaxResultBytes:   uint64(interval["max_result_bytes"].(int)),
+			MaxReadRows:      uint64(interval["max_read_rows"].(int)),
+			MaxReadBytes:     uint64(interval["max_read_bytes"].(int)),
+			MaxExecutionTime: interval["max_execution_time"].(float64),
+		})
+	}
+	return intervals
+}
+
+func flattenIntervals(intervals []IntervalResource) []map[string]any {
+	flattened := make([]map[string]any, 0, len(intervals))
+	for _, interval := range intervals {
+		flattened = append(flattened, map[string]any{
+			"duration":           int(interval.Duration),
+			"randomized":         interval.Randomized,
+			"max_queries":        int(interval.MaxQueries),
+			"max_query_selects":  int(interval.MaxQuerySelects),
+			"max_query_inserts":  int(interval.MaxQueryInserts),
+			"max_errors":         int(interval.MaxErrors),
+			"max_result_rows":    int(interval.MaxResultRows),
+			"max_result_bytes":   int(interval.MaxResultBytes),
+			"max_read_rows":      int(interval.MaxReadRows),
+			"max_read_bytes":     int(interval.MaxReadBytes),
+			"max_execution_time": interval.MaxExecutionTime,
+		})
+	}
+	return flattened
+}
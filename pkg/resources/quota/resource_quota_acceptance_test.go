@@ -0,0 +1,73 @@
+package resourcequota_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceQuotaName = "test_quota"
+
+func TestAccResourceQuota(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: quotaConfig(testResourceQuotaName, "100"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_quota.quota", "name", testResourceQuotaName),
+					resource.TestCheckResourceAttr("clickhouse_quota.quota", "keyed_by", "user_name"),
+					resource.TestCheckResourceAttr("clickhouse_quota.quota", "interval.#", "2"),
+					resource.TestCheckResourceAttr("clickhouse_quota.quota", "to.#", "1"),
+				),
+			},
+			// ALTER THE LIMITS
+			{
+				Config: quotaConfig(testResourceQuotaName, "200"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("clickhouse_quota.quota", "interval.*", map[string]string{
+						"duration":    "3600",
+						"max_queries": "200",
+					}),
+				),
+			},
+			{
+				ResourceName:      "clickhouse_quota.quota",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func quotaConfig(name string, maxQueries string) string {
+	s := `
+	resource "clickhouse_role" "role" {
+		name = "test_quota_role"
+		database = "default"
+		privileges = ["SELECT"]
+	}
+
+	resource "clickhouse_quota" "quota" {
+		name = "%_name_%"
+		keyed_by = "user_name"
+		interval {
+			duration = 3600
+			max_queries = %_max_queries_%
+			max_execution_time = 60
+		}
+		interval {
+			duration = 86400
+			randomized = true
+			max_read_bytes = 1000000000
+		}
+		to = [clickhouse_role.role.name]
+	}
+	`
+	s = strings.Replace(s, "%_name_%", name, -1)
+	s = strings.Replace(s, "%_max_queries_%", maxQueries, -1)
+	return s
+}
@@ -0,0 +1,63 @@
+package resourcequota
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildQuotaSentences(t *testing.T) {
+	quotaResource := QuotaResource{
+		Name:    "analysts",
+		Cluster: "main",
+		KeyedBy: "user_name",
+		Intervals: []IntervalResource{
+			{Duration: 3600, Randomized: true, MaxQueries: 100, MaxErrors: 10, MaxExecutionTime: 1.5},
+			{Duration: 86400},
+		},
+		To: []string{"alice"},
+	}
+
+	want := "CREATE QUOTA `analysts` ON CLUSTER `main` KEYED BY user_name FOR RANDOMIZED INTERVAL 3600 second MAX queries = 100, errors = 10, execution_time = 1.5, " +
+		"FOR INTERVAL 86400 second TRACKING ONLY TO `alice`"
+	if got := buildCreateQuotaSentence(quotaResource); got != want {
+		t.Errorf("buildCreateQuotaSentence() = %s, want %s", got, want)
+	}
+
+	stateIntervals := []IntervalResource{{Duration: 3600, MaxQueries: 50}, {Duration: 60, MaxReadBytes: 1000}}
+	want = "ALTER QUOTA `old_analysts` ON CLUSTER `main` RENAME TO `analysts` KEYED BY user_name FOR RANDOMIZED INTERVAL 3600 second MAX queries = 100, errors = 10, execution_time = 1.5, " +
+		"FOR INTERVAL 86400 second TRACKING ONLY, FOR INTERVAL 60 second NO LIMITS TO `alice`"
+	if got := buildAlterQuotaSentence("old_analysts", stateIntervals, quotaResource); got != want {
+		t.Errorf("buildAlterQuotaSentence() = %s, want %s", got, want)
+	}
+
+	want = "ALTER QUOTA `analysts` NOT KEYED TO NONE"
+	if got := buildAlterQuotaSentence("analysts", nil, QuotaResource{Name: "analysts"}); got != want {
+		t.Errorf("buildAlterQuotaSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestCHQuotaToResource(t *testing.T) {
+	queries, readBytes, executionTime := uint64(100), uint64(1000000), 2.5
+	chQuota := CHQuota{
+		Name:        "analysts",
+		Keys:        []string{"client_key", "user_name"},
+		ApplyToList: []string{"alice"},
+		Limits: []CHQuotaLimit{
+			{Duration: 3600, Randomized: 1, MaxQueries: &queries, MaxReadBytes: &readBytes, MaxExecutionTime: &executionTime},
+			{Duration: 86400},
+		},
+	}
+
+	want := &QuotaResource{
+		Name:    "analysts",
+		KeyedBy: "client_key,user_name",
+		Intervals: []IntervalResource{
+			{Duration: 3600, Randomized: true, MaxQueries: 100, MaxReadBytes: 1000000, MaxExecutionTime: 2.5},
+			{Duration: 86400},
+		},
+		To: []string{"alice"},
+	}
+	if got := chQuota.ToResource(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToResource() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package resourcequota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHQuotaService struct {
+	CHConnection *driver.Conn
+}
+
+// GetQuota returns the quota with its limits, or nil when it does not exist
+func (qs *CHQuotaService) GetQuota(ctx context.Context, name string) (*CHQuota, error) {
+	ctx = common.QueryParameters(ctx, map[string]string{"name": name})
+	rows, err := (*qs.CHConnection).Query(ctx, "SELECT name, arrayMap(key -> toString(key), keys) AS keys, apply_to_list FROM system.quotas WHERE name = {name:String}")
+	if err != nil {
+		return nil, fmt.Errorf("reading quota from Clickhouse: %v", err)
+	}
+	if !rows.Next() {
+		return nil, nil
+	}
+	var chQuota CHQuota
+	if err := rows.ScanStruct(&chQuota); err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse quota row: %v", err)
+	}
+
+	rows, err = (*qs.CHConnection).Query(ctx, "SELECT toUInt64(duration) AS duration, is_randomized_interval, max_queries, max_query_selects, max_query_inserts, "+
+		"max_errors, max_result_rows, max_result_bytes, max_read_rows, max_read_bytes, max_execution_time "+
+		"FROM system.quota_limits WHERE quota_name = {name:String} ORDER BY duration")
+	if err != nil {
+		return nil, fmt.Errorf("reading quota limits from Clickhouse: %v", err)
+	}
+	for rows.Next() {
+		var limit CHQuotaLimit
+		if err := rows.ScanStruct(&limit); err != nil {
+			return nil, fmt.Errorf("scanning Clickhouse quota limit row: %v", err)
+		}
+		chQuota.Limits = append(chQuota.Limits, limit)
+	}
+	return &chQuota, nil
+}
+
+func (qs *CHQuotaService) CreateQuota(ctx context.Context, quotaResource QuotaResource) error {
+	err := (*qs.CHConnection).Exec(ctx, buildCreateQuotaSentence(quotaResource))
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse quota: %v", err)
+	}
+	return nil
+}
+
+func (qs *CHQuotaService) UpdateQuota(ctx context.Context, stateName string, stateIntervals []IntervalResource, quotaResource QuotaResource) error {
+	err := (*qs.CHConnection).Exec(ctx, buildAlterQuotaSentence(stateName, stateIntervals, quotaResource))
+	if err != nil {
+		return fmt.Errorf("updating Clickhouse quota: %v", err)
+	}
+	return nil
+}
+
+func (qs *CHQuotaService) DeleteQuota(ctx context.Context, quotaResource QuotaResource) error {
+	query := fmt.Sprintf("DROP QUOTA %s %s", common.QuoteIdentifier(quotaResource.Name), common.GetClusterStatement(quotaResource.Cluster))
+	err := (*qs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("deleting Clickhouse quota: %v", err)
+	}
+	return nil
+}
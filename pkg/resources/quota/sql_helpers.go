@@ -0,0 +1,102 @@
+package resourcequota
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildCreateQuotaSentence(quotaResource QuotaResource) string {
+	parts := []string{"CREATE QUOTA", common.QuoteIdentifier(quotaResource.Name)}
+	if clusterStatement := common.GetClusterStatement(quotaResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if quotaResource.KeyedBy != "" {
+		parts = append(parts, "KEYED BY "+quotaResource.KeyedBy)
+	}
+	if len(quotaResource.Intervals) > 0 {
+		parts = append(parts, buildIntervalsClause(quotaResource.Intervals, nil))
+	}
+	if len(quotaResource.To) > 0 {
+		parts = append(parts, "TO "+strings.Join(common.QuoteIdentifiers(quotaResource.To), ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildAlterQuotaSentence replaces the key, the intervals and the assignees of the quota. ALTER QUOTA only
+// updates the intervals it mentions, so the intervals of the state missing in the plan are dropped with NO LIMITS.
+func buildAlterQuotaSentence(stateName string, stateIntervals []IntervalResource, quotaResource QuotaResource) string {
+	parts := []string{"ALTER QUOTA", common.QuoteIdentifier(stateName)}
+	if clusterStatement := common.GetClusterStatement(quotaResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if stateName != quotaResource.Name {
+		parts = append(parts, "RENAME TO "+common.QuoteIdentifier(quotaResource.Name))
+	}
+	if quotaResource.KeyedBy != "" {
+		parts = append(parts, "KEYED BY "+quotaResource.KeyedBy)
+	} else {
+		parts = append(parts, "NOT KEYED")
+	}
+	if len(stateIntervals) > 0 || len(quotaResource.Intervals) > 0 {
+		parts = append(parts, buildIntervalsClause(quotaResource.Intervals, stateIntervals))
+	}
+	if len(quotaResource.To) > 0 {
+		parts = append(parts, "TO "+strings.Join(common.QuoteIdentifiers(quotaResource.To), ", "))
+	} else {
+		parts = append(parts, "TO NONE")
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildIntervalsClause renders the FOR INTERVAL clauses of the intervals, and NO LIMITS for the dropped ones
+func buildIntervalsClause(intervals []IntervalResource, droppedIntervals []IntervalResource) string {
+	durations := make(map[uint64]bool, len(intervals))
+	clauses := make([]string, 0, len(intervals)+len(droppedIntervals))
+	for _, interval := range intervals {
+		durations[interval.Duration] = true
+		clause := fmt.Sprintf("FOR INTERVAL %d second", interval.Duration)
+		if interval.Randomized {
+			clause = fmt.Sprintf("FOR RANDOMIZED INTERVAL %d second", interval.Duration)
+		}
+		if limits := interval.limits(); len(limits) > 0 {
+			clauses = append(clauses, clause+" MAX "+strings.Join(limits, ", "))
+		} else {
+			clauses = append(clauses, clause+" TRACKING ONLY")
+		}
+	}
+	for _, interval := range droppedIntervals {
+		if !durations[interval.Duration] {
+			clauses = append(clauses, fmt.Sprintf("FOR INTERVAL %d second NO LIMITS", interval.Duration))
+		}
+	}
+	return strings.Join(clauses, ", ")
+}
+
+// limits renders the limits set on the interval, like queries = 100
+func (i IntervalResource) limits() []string {
+	var limits []string
+	for _, limit := range []struct {
+		name  string
+		value uint64
+	}{
+		{"queries", i.MaxQueries},
+		{"query_selects", i.MaxQuerySelects},
+		{"query_inserts", i.MaxQueryInserts},
+		{"errors", i.MaxErrors},
+		{"result_rows", i.MaxResultRows},
+		{"result_bytes", i.MaxResultBytes},
+		{"read_rows", i.MaxReadRows},
+		{"read_bytes", i.MaxReadBytes},
+	} {
+		if limit.value != 0 {
+			limits = append(limits, fmt.Sprintf("%s = %d", limit.name, limit.value))
+		}
+	}
+	if i.MaxExecutionTime != 0 {
+		limits = append(limits, "execution_time = "+strconv.FormatFloat(i.MaxExecutionTime, 'f', -1, 64))
+	}
+	return limits
+}
@@ -13,28 +13,59 @@ type CHGrant struct {
 }
 
 type CHRole struct {
-	Name       string `ch:"name"`
-	Privileges []CHGrant
+	Name             string `ch:"name"`
+	Privileges       []CHGrant
+	SettingsProfiles []string
 }
 
 type RoleResource struct {
-	Name       string
-	Database   string
-	Privileges *schema.Set
+	Name             string
+	Database         string
+	Privileges       *schema.Set
+	SettingsProfiles []string
 }
 
-func (r *CHRole) ToRoleResource() (*RoleResource, error) {
-	var database string
+// ToRoleResource returns the role with the privileges it owns, the privileges of the state on its database. Other
+// privileges, even on the same database, belong to clickhouse_grant resources. On import the database is unknown:
+// every privilege is taken and they must be on a single database.
+func (r *CHRole) ToRoleResource(database string, statePrivileges []string) (*RoleResource, error) {
+	if database == "" {
+		for _, privilege := range r.Privileges {
+			if database != "" && privilege.Database != database {
+				return nil, fmt.Errorf("role %s has privileges on different databases", r.Name)
+			}
+			database = privilege.Database
+		}
+		statePrivileges = r.PrivilegesOn(database)
+	}
+
+	return &RoleResource{Name: r.Name, Database: database, Privileges: common.StringListToSet(r.OwnedPrivileges(database, statePrivileges)), SettingsProfiles: r.SettingsProfiles}, nil
+}
+
+// PrivilegesOn returns the privileges of the role on the database, * for the global ones
+func (r *CHRole) PrivilegesOn(database string) []string {
 	var privileges []string
-	for i := 0; i < len(r.Privileges); i++ {
-		if database != "" && r.Privileges[i].Database != "" && r.Privileges[i].Database != database {
-			return nil, fmt.Errorf("role %s has privileges on different databases", r.Name)
+	for _, privilege := range r.Privileges {
+		if privilege.Database == database {
+			privileges = append(privileges, privilege.AccessType)
 		}
-		database = r.Privileges[i].Database
-		privileges = append(privileges, r.Privileges[i].AccessType)
 	}
+	return privileges
+}
 
-	return &RoleResource{Name: r.Name, Database: database, Privileges: common.StringListToSet(privileges)}, nil
+// OwnedPrivileges returns the privileges of the role on the database that are owned by the resource
+func (r *CHRole) OwnedPrivileges(database string, owned []string) []string {
+	ownedPrivileges := make(map[string]bool, len(owned))
+	for _, privilege := range owned {
+		ownedPrivileges[privilege] = true
+	}
+	var privileges []string
+	for _, privilege := range r.PrivilegesOn(database) {
+		if ownedPrivileges[privilege] {
+			privileges = append(privileges, privilege)
+		}
+	}
+	return privileges
 }
 
 func (r *CHRole) GetPrivilegesList() []string {
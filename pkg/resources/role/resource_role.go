@@ -15,6 +15,9 @@ func ResourceRole() *schema.Resource {
 		ReadContext:   resourceRoleRead,
 		DeleteContext: resourceRoleDelete,
 		UpdateContext: resourceRoleUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRoleImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Description: "Role name",
@@ -27,8 +30,17 @@ func ResourceRole() *schema.Resource {
 				Required:    true,
 			},
 			"privileges": {
-				Description: "Granted privileges to the role. Privileges will be granted at DB level",
-				Type:        schema.TypeSet,
+				Description: "Granted privileges to the role. Privileges will be granted at DB level. The role owns only these privileges, " +
+					"other privileges on the database are left to `clickhouse_grant` resources and can't overlap with these ones",
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"settings_profiles": {
+				Description: "Settings profiles assigned to the role, later profiles take precedence. It replaces any other setting of the role",
+				Type:        schema.TypeList,
 				Optional:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
@@ -55,7 +67,12 @@ func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	}
 
 	chRoleService := CHRoleService{CHConnection: conn}
-	chRole, err := chRoleService.UpdateRole(ctx, RoleResource{Name: planRoleName, Database: planDatabase, Privileges: planPrivileges}, d)
+	chRole, err := chRoleService.UpdateRole(ctx, RoleResource{
+		Name:             planRoleName,
+		Database:         planDatabase,
+		Privileges:       planPrivileges,
+		SettingsProfiles: common.MapArrayInterfaceToArrayOfStrings(d.Get("settings_profiles").([]interface{})),
+	}, d)
 
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource role update: %v", err))
@@ -78,8 +95,13 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource role read: %v", err))
 	}
+	if chRole == nil {
+		// Role removed outside of terraform
+		d.SetId("")
+		return diags
+	}
 
-	roleResource, err := chRole.ToRoleResource()
+	roleResource, err := chRole.ToRoleResource(d.Get("database").(string), common.StringSetToList(d.Get("privileges").(*schema.Set)))
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource role read: %v", err))
 	}
@@ -93,12 +115,22 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	if err := d.Set("privileges", &roleResource.Privileges); err != nil {
 		return diag.FromErr(fmt.Errorf("resource role read: %v", err))
 	}
+	if err := d.Set("settings_profiles", roleResource.SettingsProfiles); err != nil {
+		return diag.FromErr(fmt.Errorf("resource role read: %v", err))
+	}
 
 	d.SetId(roleResource.Name)
 
 	return diags
 }
 
+func resourceRoleImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if err := d.Set("name", d.Id()); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 	client := meta.(*common.ApiClient)
@@ -114,7 +146,7 @@ func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 	}
 
 	chRoleService := CHRoleService{CHConnection: conn}
-	chRole, err := chRoleService.CreateRole(ctx, roleName, database, common.StringSetToList(privileges))
+	chRole, err := chRoleService.CreateRole(ctx, roleName, database, common.StringSetToList(privileges), common.MapArrayInterfaceToArrayOfStrings(d.Get("settings_profiles").([]interface{})))
 
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource role create: %v", err))
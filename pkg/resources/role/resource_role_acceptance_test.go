@@ -170,6 +170,12 @@ func generateTestSteps(testStepsData []TestStepData) []resource.TestStep {
 			),
 		})
 	}
+	// Import the role in its last state
+	testSteps = append(testSteps, resource.TestStep{
+		ResourceName:      roleResource,
+		ImportState:       true,
+		ImportStateVerify: true,
+	})
 	return testSteps
 }
 
@@ -0,0 +1,59 @@
+package resourcerole
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func TestCHRoleToRoleResource(t *testing.T) {
+	chRole := CHRole{
+		Name: "analyst",
+		Privileges: []CHGrant{
+			{RoleName: "analyst", AccessType: "SELECT", Database: "events"},
+			{RoleName: "analyst", AccessType: "INSERT", Database: "events"},
+			{RoleName: "analyst", AccessType: "SELECT", Database: "logs"},
+		},
+	}
+
+	roleResource, err := chRole.ToRoleResource("events", []string{"SELECT", "INSERT", "SHOW TABLES"})
+	if err != nil {
+		t.Fatalf("ToRoleResource() unexpected error: %v", err)
+	}
+	privileges := common.StringSetToList(roleResource.Privileges)
+	sort.Strings(privileges)
+	if roleResource.Database != "events" || !reflect.DeepEqual(privileges, []string{"INSERT", "SELECT"}) {
+		t.Errorf("ToRoleResource() = %s, %v, want events, [INSERT SELECT]", roleResource.Database, privileges)
+	}
+
+	// INSERT on events belongs to a clickhouse_grant
+	roleResource, err = chRole.ToRoleResource("events", []string{"SELECT"})
+	if privileges := common.StringSetToList(roleResource.Privileges); err != nil || !reflect.DeepEqual(privileges, []string{"SELECT"}) {
+		t.Errorf("ToRoleResource() = %v, %v, want [SELECT]", privileges, err)
+	}
+
+	if _, err := chRole.ToRoleResource("", nil); err == nil {
+		t.Errorf("ToRoleResource() expected an error for privileges on different databases without a database")
+	}
+
+	chRole.Privileges = chRole.Privileges[:2]
+	roleResource, err = chRole.ToRoleResource("", nil)
+	if err != nil || roleResource.Database != "events" || roleResource.Privileges.Len() != 2 {
+		t.Errorf("ToRoleResource() = %+v, %v, want database events with 2 privileges", roleResource, err)
+	}
+}
+
+func TestOverlappingPrivileges(t *testing.T) {
+	existing := []string{"SELECT", "INSERT", "SHOW TABLES"}
+	owned := []string{"SELECT"}
+
+	want := []string{"INSERT"}
+	if got := overlappingPrivileges([]string{"SELECT", "INSERT", "ALTER UPDATE"}, existing, owned); !reflect.DeepEqual(got, want) {
+		t.Errorf("overlappingPrivileges() = %v, want %v", got, want)
+	}
+	if got := overlappingPrivileges([]string{"ALTER UPDATE"}, existing, owned); got != nil {
+		t.Errorf("overlappingPrivileges() = %v, want none", got)
+	}
+}
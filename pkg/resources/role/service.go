@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	resourcesettingsprofile "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/settingsprofile"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"strings"
 )
@@ -12,16 +14,25 @@ type CHRoleService struct {
 	CHConnection *driver.Conn
 }
 
+// quoteGrantDatabase quotes the database of a grant, * stands for all of them
+func quoteGrantDatabase(database string) string {
+	if database == "*" {
+		return database
+	}
+	return common.QuoteIdentifier(database)
+}
+
 func getGrantQuery(roleName string, privileges []string, database string) string {
 	if database == "system" || database == "*" {
-		return fmt.Sprintf("GRANT CURRENT GRANTS (%s ON %s.*) TO %s", strings.Join(privileges, ","), database, roleName)
+		return fmt.Sprintf("GRANT CURRENT GRANTS (%s ON %s.*) TO %s", strings.Join(privileges, ","), quoteGrantDatabase(database), common.QuoteIdentifier(roleName))
 	}
-	return fmt.Sprintf("GRANT %s ON %s.* TO %s", strings.Join(privileges, ","), database, roleName)
+	return fmt.Sprintf("GRANT %s ON %s.* TO %s", strings.Join(privileges, ","), quoteGrantDatabase(database), common.QuoteIdentifier(roleName))
 }
 
 func (rs *CHRoleService) getRoleGrants(ctx context.Context, roleName string) ([]CHGrant, error) {
-	query := fmt.Sprintf("SELECT role_name, access_type, database FROM system.grants WHERE role_name = '%s'", roleName)
-	rows, err := (*rs.CHConnection).Query(ctx, query)
+	ctx = common.QueryParameters(ctx, map[string]string{"role_name": roleName})
+	rows, err := (*rs.CHConnection).Query(ctx, "SELECT role_name, access_type, database FROM system.grants "+
+		"WHERE role_name = {role_name:String} AND table IS NULL AND column IS NULL AND is_partial_revoke = 0")
 
 	if err != nil {
 		return nil, fmt.Errorf("error fetching role grants: %s", err)
@@ -44,9 +55,7 @@ func (rs *CHRoleService) getRoleGrants(ctx context.Context, roleName string) ([]
 }
 
 func (rs *CHRoleService) GetRole(ctx context.Context, roleName string) (*CHRole, error) {
-	roleQuery := fmt.Sprintf("SELECT name FROM system.roles WHERE name = '%s'", roleName)
-
-	rows, err := (*rs.CHConnection).Query(ctx, roleQuery)
+	rows, err := (*rs.CHConnection).Query(common.QueryParameters(ctx, map[string]string{"name": roleName}), "SELECT name FROM system.roles WHERE name = {name:String}")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching role: %s", err)
 	}
@@ -59,9 +68,16 @@ func (rs *CHRoleService) GetRole(ctx context.Context, roleName string) (*CHRole,
 		return nil, fmt.Errorf("error fetching role grants: %s", err)
 	}
 
+	chSettingsProfileService := resourcesettingsprofile.CHSettingsProfileService{CHConnection: rs.CHConnection}
+	settingsProfiles, err := chSettingsProfileService.GetAssignedProfiles(ctx, "role_name", roleName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching role settings profiles: %s", err)
+	}
+
 	return &CHRole{
-		Name:       roleName,
-		Privileges: privileges,
+		Name:             roleName,
+		Privileges:       privileges,
+		SettingsProfiles: settingsProfiles,
 	}, nil
 }
 
@@ -80,13 +96,18 @@ func (rs *CHRoleService) UpdateRole(ctx context.Context, rolePlan RoleResource,
 	roleDatabaseHasChange := resourceData.HasChange("database")
 	rolePrivilegesHasChange := resourceData.HasChange("privileges")
 
+	// Only the privileges of the state on its database belong to the role, others are managed by clickhouse_grant
+	stateDatabase, _ := resourceData.GetChange("database")
+	stateOwnedPrivileges, _ := resourceData.GetChange("privileges")
+	statePrivileges := chRole.OwnedPrivileges(stateDatabase.(string), common.StringSetToList(stateOwnedPrivileges.(*schema.Set)))
+
 	var grantPrivileges []string
 	var revokePrivileges []string
 	if rolePrivilegesHasChange {
 		for _, planPrivilege := range rolePlan.Privileges.List() {
 			found := false
-			for _, privilege := range chRole.Privileges {
-				if privilege.AccessType == planPrivilege {
+			for _, privilege := range statePrivileges {
+				if privilege == planPrivilege {
 					found = true
 				}
 			}
@@ -95,31 +116,48 @@ func (rs *CHRoleService) UpdateRole(ctx context.Context, rolePlan RoleResource,
 			}
 		}
 
-		for _, privilege := range chRole.Privileges {
-			if rolePlan.Privileges.Contains(privilege.AccessType) == false {
-				revokePrivileges = append(revokePrivileges, privilege.AccessType)
+		for _, privilege := range statePrivileges {
+			if rolePlan.Privileges.Contains(privilege) == false {
+				revokePrivileges = append(revokePrivileges, privilege)
 			}
 		}
 	}
 
+	// Privileges granted on the database of the plan by clickhouse_grant resources can't be taken over by the role
+	granted := grantPrivileges
+	ownedOnPlanDatabase := statePrivileges
+	if roleDatabaseHasChange {
+		granted = append(granted, statePrivileges...)
+		ownedOnPlanDatabase = nil
+	}
+	if overlapping := overlappingPrivileges(granted, chRole.PrivilegesOn(rolePlan.Database), ownedOnPlanDatabase); len(overlapping) > 0 {
+		return nil, fmt.Errorf("privileges %s on %s already granted to role %s by a clickhouse_grant, remove them from there first", strings.Join(overlapping, ", "), rolePlan.Database, chRole.Name)
+	}
+
 	conn := *rs.CHConnection
 
 	if roleNameHasChange {
-		err := conn.Exec(ctx, fmt.Sprintf("ALTER ROLE %s RENAME TO %s", chRole.Name, rolePlan.Name))
+		err := conn.Exec(ctx, fmt.Sprintf("ALTER ROLE %s RENAME TO %s", common.QuoteIdentifier(chRole.Name), common.QuoteIdentifier(rolePlan.Name)))
 		if err != nil {
 			return nil, fmt.Errorf("error renaming role %s to %s: %v", chRole.Name, rolePlan.Name, err)
 		}
 	}
 
-	if roleDatabaseHasChange {
-		err := conn.Exec(ctx, fmt.Sprintf("REVOKE ALL ON *.* FROM %s", rolePlan.Name))
+	if resourceData.HasChange("settings_profiles") {
+		err := conn.Exec(ctx, fmt.Sprintf("ALTER ROLE %s %s", common.QuoteIdentifier(rolePlan.Name), resourcesettingsprofile.BuildProfilesClause(rolePlan.SettingsProfiles)))
 		if err != nil {
-			return nil, fmt.Errorf("error revoking all privileges from role %s: %v", chRole.Name, err)
+			return nil, fmt.Errorf("error updating settings profiles of role %s: %v", chRole.Name, err)
+		}
+	}
+
+	if roleDatabaseHasChange && len(statePrivileges) > 0 {
+		err := conn.Exec(ctx, fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(statePrivileges, ","), quoteGrantDatabase(stateDatabase.(string)), common.QuoteIdentifier(rolePlan.Name)))
+		if err != nil {
+			return nil, fmt.Errorf("error revoking privileges from role %s: %v", chRole.Name, err)
 		}
-		dbPrivileges := chRole.GetPrivilegesList()
 		err = conn.Exec(ctx, getGrantQuery(
 			rolePlan.Name,
-			dbPrivileges,
+			statePrivileges,
 			rolePlan.Database,
 		))
 		if err != nil {
@@ -135,7 +173,7 @@ func (rs *CHRoleService) UpdateRole(ctx context.Context, rolePlan RoleResource,
 	}
 
 	if len(revokePrivileges) > 0 {
-		err := conn.Exec(ctx, fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(revokePrivileges, ","), rolePlan.Database, rolePlan.Name))
+		err := conn.Exec(ctx, fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(revokePrivileges, ","), quoteGrantDatabase(rolePlan.Database), common.QuoteIdentifier(rolePlan.Name)))
 		if err != nil {
 			return nil, fmt.Errorf("error revoking privileges from role %s: %v", chRole.Name, err)
 		}
@@ -144,9 +182,31 @@ func (rs *CHRoleService) UpdateRole(ctx context.Context, rolePlan RoleResource,
 	return rs.GetRole(ctx, rolePlan.Name)
 }
 
-func (rs *CHRoleService) CreateRole(ctx context.Context, name string, database string, privileges []string) (*CHRole, error) {
+// overlappingPrivileges returns the privileges to grant that are already granted and not owned
+func overlappingPrivileges(granted []string, existing []string, owned []string) []string {
+	others := make(map[string]bool, len(existing))
+	for _, privilege := range existing {
+		others[privilege] = true
+	}
+	for _, privilege := range owned {
+		delete(others, privilege)
+	}
+	var overlapping []string
+	for _, privilege := range granted {
+		if others[privilege] {
+			overlapping = append(overlapping, privilege)
+		}
+	}
+	return overlapping
+}
+
+func (rs *CHRoleService) CreateRole(ctx context.Context, name string, database string, privileges []string, settingsProfiles []string) (*CHRole, error) {
 	conn := *rs.CHConnection
-	err := conn.Exec(ctx, fmt.Sprintf("CREATE ROLE %s", name))
+	query := fmt.Sprintf("CREATE ROLE %s", common.QuoteIdentifier(name))
+	if len(settingsProfiles) > 0 {
+		query = fmt.Sprintf("%s %s", query, resourcesettingsprofile.BuildProfilesClause(settingsProfiles))
+	}
+	err := conn.Exec(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error creating role: %s", err)
 	}
@@ -157,7 +217,7 @@ func (rs *CHRoleService) CreateRole(ctx context.Context, name string, database s
 		err = conn.Exec(ctx, getGrantQuery(name, []string{privilege}, database))
 		if err != nil {
 			// Rollback
-			err2 := conn.Exec(ctx, fmt.Sprintf("DROP ROLE %s", name))
+			err2 := conn.Exec(ctx, fmt.Sprintf("DROP ROLE %s", common.QuoteIdentifier(name)))
 			if err2 != nil {
 				return nil, fmt.Errorf("error creating role: %s:%s", err, err2)
 			}
@@ -165,9 +225,9 @@ func (rs *CHRoleService) CreateRole(ctx context.Context, name string, database s
 		}
 		chPrivileges = append(chPrivileges, CHGrant{RoleName: name, AccessType: privilege, Database: database})
 	}
-	return &CHRole{Name: name, Privileges: chPrivileges}, nil
+	return &CHRole{Name: name, Privileges: chPrivileges, SettingsProfiles: settingsProfiles}, nil
 }
 
 func (rs *CHRoleService) DeleteRole(ctx context.Context, name string) error {
-	return (*rs.CHConnection).Exec(ctx, fmt.Sprintf("DROP ROLE %s", name))
+	return (*rs.CHConnection).Exec(ctx, fmt.Sprintf("DROP ROLE %s", common.QuoteIdentifier(name)))
 }
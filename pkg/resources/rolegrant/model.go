@@ -0,0 +1,67 @@
+package resourcerolegrant
+
+// CHRoleGrant is a row of system.role_grants, either user_name or role_name is the grantee
+type CHRoleGrant struct {
+	UserName        *string `ch:"user_name"`
+	RoleName        *string `ch:"role_name"`
+	GrantedRoleName string  `ch:"granted_role_name"`
+	IsDefault       uint8   `ch:"granted_role_is_default"`
+	WithAdminOption uint8   `ch:"with_admin_option"`
+}
+
+// CHDefaultRoles are the default roles of a user in system.users: all the granted roles but the excepted ones when
+// All is set, otherwise the listed ones
+type CHDefaultRoles struct {
+	All    uint8    `ch:"default_roles_all"`
+	List   []string `ch:"default_roles_list"`
+	Except []string `ch:"default_roles_except"`
+}
+
+type RoleGrantResource struct {
+	Role            string
+	Grantee         string
+	WithAdminOption bool
+	Default         bool
+	Cluster         string
+}
+
+// IsUserGrant tells if the role is granted to a user, default roles only apply to users
+func (g *CHRoleGrant) IsUserGrant() bool {
+	return g.UserName != nil
+}
+
+func (g *CHRoleGrant) ToResource() *RoleGrantResource {
+	roleGrantResource := RoleGrantResource{
+		Role:            g.GrantedRoleName,
+		WithAdminOption: g.WithAdminOption != 0,
+		Default:         g.IsDefault != 0,
+	}
+	if g.UserName != nil {
+		roleGrantResource.Grantee = *g.UserName
+	} else if g.RoleName != nil {
+		roleGrantResource.Grantee = *g.RoleName
+	}
+	return &roleGrantResource
+}
+
+// WithRole returns the default roles with the role added or removed, keeping the other roles and ALL
+func (r CHDefaultRoles) WithRole(role string, isDefault bool) CHDefaultRoles {
+	withRole := CHDefaultRoles{All: r.All, List: withoutRole(r.List, role), Except: withoutRole(r.Except, role)}
+	switch {
+	case r.All != 0 && !isDefault:
+		withRole.Except = append(withRole.Except, role)
+	case r.All == 0 && isDefault:
+		withRole.List = append(withRole.List, role)
+	}
+	return withRole
+}
+
+func withoutRole(roles []string, role string) []string {
+	result := []string{}
+	for _, r := range roles {
+		if r != role {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package resourcerolegrant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceRoleGrant() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to grant a role to a user or to another role, to build role hierarchies. " +
+			"Don't combine it with the `roles` of `clickhouse_user` for the same user",
+
+		CreateContext: resourceRoleGrantCreate,
+		ReadContext:   resourceRoleGrantRead,
+		UpdateContext: resourceRoleGrantUpdate,
+		DeleteContext: resourceRoleGrantDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRoleGrantImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Description: "Granted role",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"grantee": {
+				Description: "User or role receiving the role",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"with_admin_option": {
+				Description: "Allow the grantee to grant the role to others",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"default": {
+				Description: "Make the role a default role of the user, so it is active when the user logs in. Other default roles of the user, or DEFAULT ROLE ALL, are kept. Ignored when the grantee is a role",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the role is granted ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceRoleGrantRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	chRoleGrantService := CHRoleGrantService{CHConnection: client.ClickhouseConnection}
+
+	chRoleGrant, err := chRoleGrantService.GetRoleGrant(ctx, d.Get("role").(string), d.Get("grantee").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse role grant: %v", err))
+	}
+	if chRoleGrant == nil {
+		d.SetId("")
+		return diags
+	}
+
+	roleGrantResource := chRoleGrant.ToResource()
+	if err := d.Set("role", roleGrantResource.Role); err != nil {
+		return diag.FromErr(fmt.Errorf("setting role: %v", err))
+	}
+	if err := d.Set("grantee", roleGrantResource.Grantee); err != nil {
+		return diag.FromErr(fmt.Errorf("setting grantee: %v", err))
+	}
+	if err := d.Set("with_admin_option", roleGrantResource.WithAdminOption); err != nil {
+		return diag.FromErr(fmt.Errorf("setting with_admin_option: %v", err))
+	}
+	if chRoleGrant.IsUserGrant() {
+		if err := d.Set("default", roleGrantResource.Default); err != nil {
+			return diag.FromErr(fmt.Errorf("setting default: %v", err))
+		}
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + roleGrantResource.Role + ":" + roleGrantResource.Grantee)
+
+	return diags
+}
+
+func resourceRoleGrantImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "role", "grantee")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("role", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting role: %v", err)
+	}
+	if err := d.Set("grantee", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting grantee: %v", err)
+	}
+	if err := d.Set("default", true); err != nil {
+		return nil, fmt.Errorf("setting default: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceRoleGrantCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chRoleGrantService := CHRoleGrantService{CHConnection: client.ClickhouseConnection}
+
+	roleGrantResource := roleGrantResourceFromData(d, client)
+	if err := chRoleGrantService.CreateRoleGrant(ctx, roleGrantResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + roleGrantResource.Role + ":" + roleGrantResource.Grantee)
+
+	return resourceRoleGrantRead(ctx, d, meta)
+}
+
+func resourceRoleGrantUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chRoleGrantService := CHRoleGrantService{CHConnection: client.ClickhouseConnection}
+
+	roleGrantResource := roleGrantResourceFromData(d, client)
+	if d.HasChange("with_admin_option") {
+		if err := chRoleGrantService.UpdateAdminOption(ctx, roleGrantResource); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if d.HasChange("default") {
+		if err := chRoleGrantService.UpdateDefaultRole(ctx, roleGrantResource); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceRoleGrantRead(ctx, d, meta)
+}
+
+func resourceRoleGrantDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chRoleGrantService := CHRoleGrantService{CHConnection: client.ClickhouseConnection}
+
+	if err := chRoleGrantService.DeleteRoleGrant(ctx, roleGrantResourceFromData(d, client)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func roleGrantResourceFromData(d *schema.ResourceData, client *common.ApiClient) RoleGrantResource {
+	roleGrantResource := RoleGrantResource{
+		Role:            d.Get("role").(string),
+		Grantee:         d.Get("grantee").(string),
+		WithAdminOption: d.Get("with_admin_option").(bool),
+		Default:         d.Get("default").(bool),
+		Cluster:         d.Get("cluster").(string),
+	}
+	if roleGrantResource.Cluster == "" {
+		roleGrantResource.Cluster = client.DefaultCluster
+	}
+	return roleGrantResource
+}
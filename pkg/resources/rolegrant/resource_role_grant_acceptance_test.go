@@ -0,0 +1,74 @@
+package resourcerolegrant_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceRoleGrant(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: roleGrantConfig("false", "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_role_grant.analyst_engineer", "grantee", "test_role_grant_engineer"),
+					resource.TestCheckResourceAttr("clickhouse_role_grant.engineer_user", "default", "true"),
+				),
+			},
+			// ADMIN OPTION AND NON DEFAULT ROLE
+			{
+				Config: roleGrantConfig("true", "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_role_grant.analyst_engineer", "with_admin_option", "true"),
+					resource.TestCheckResourceAttr("clickhouse_role_grant.engineer_user", "default", "false"),
+				),
+			},
+			{
+				ResourceName:      "clickhouse_role_grant.analyst_engineer",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func roleGrantConfig(withAdminOption string, isDefault string) string {
+	s := `
+	resource "clickhouse_role" "analyst" {
+		name = "test_role_grant_analyst"
+		database = "default"
+		privileges = ["SELECT"]
+	}
+
+	resource "clickhouse_role" "engineer" {
+		name = "test_role_grant_engineer"
+		database = "default"
+		privileges = ["INSERT"]
+	}
+
+	resource "clickhouse_user" "user" {
+		name = "test_role_grant_user"
+		password = "test_role_grant_password"
+	}
+
+	resource "clickhouse_role_grant" "analyst_engineer" {
+		role = clickhouse_role.analyst.name
+		grantee = clickhouse_role.engineer.name
+		with_admin_option = %_with_admin_option_%
+	}
+
+	resource "clickhouse_role_grant" "engineer_user" {
+		role = clickhouse_role.engineer.name
+		grantee = clickhouse_user.user.name
+		default = %_default_%
+	}
+	`
+	s = strings.Replace(s, "%_with_admin_option_%", withAdminOption, -1)
+	s = strings.Replace(s, "%_default_%", isDefault, -1)
+	return s
+}
@@ -0,0 +1,67 @@
+package resourcerolegrant
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildRoleGrantSentences(t *testing.T) {
+	roleGrantResource := RoleGrantResource{Role: "analyst", Grantee: "engineer", WithAdminOption: true, Cluster: "main"}
+
+	if got, want := buildGrantRoleSentence(roleGrantResource), "GRANT ON CLUSTER `main` `analyst` TO `engineer` WITH ADMIN OPTION"; got != want {
+		t.Errorf("buildGrantRoleSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildRevokeRoleSentence(roleGrantResource, true), "REVOKE ON CLUSTER `main` ADMIN OPTION FOR `analyst` FROM `engineer`"; got != want {
+		t.Errorf("buildRevokeRoleSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildRevokeRoleSentence(RoleGrantResource{Role: "analyst", Grantee: "engineer"}, false), "REVOKE `analyst` FROM `engineer`"; got != want {
+		t.Errorf("buildRevokeRoleSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildDefaultRolesSentence("alice", "", CHDefaultRoles{List: []string{"analyst", "engineer"}}), "ALTER USER `alice` DEFAULT ROLE `analyst`, `engineer`"; got != want {
+		t.Errorf("buildDefaultRolesSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildDefaultRolesSentence("alice", "main", CHDefaultRoles{}), "ALTER USER `alice` ON CLUSTER `main` DEFAULT ROLE NONE"; got != want {
+		t.Errorf("buildDefaultRolesSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildDefaultRolesSentence("alice", "", CHDefaultRoles{All: 1, Except: []string{"admin"}}), "ALTER USER `alice` DEFAULT ROLE ALL EXCEPT `admin`"; got != want {
+		t.Errorf("buildDefaultRolesSentence() = %s, want %s", got, want)
+	}
+	if got, want := buildDefaultRolesSentence("alice", "", CHDefaultRoles{All: 1}), "ALTER USER `alice` DEFAULT ROLE ALL"; got != want {
+		t.Errorf("buildDefaultRolesSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestCHDefaultRolesWithRole(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultRoles CHDefaultRoles
+		isDefault    bool
+		want         CHDefaultRoles
+	}{
+		{"add to list", CHDefaultRoles{List: []string{"engineer"}, Except: []string{}}, true, CHDefaultRoles{List: []string{"engineer", "analyst"}, Except: []string{}}},
+		{"remove from list", CHDefaultRoles{List: []string{"analyst", "engineer"}, Except: []string{}}, false, CHDefaultRoles{List: []string{"engineer"}, Except: []string{}}},
+		{"except from all", CHDefaultRoles{All: 1, List: []string{}, Except: []string{}}, false, CHDefaultRoles{All: 1, List: []string{}, Except: []string{"analyst"}}},
+		{"include in all", CHDefaultRoles{All: 1, List: []string{}, Except: []string{"analyst", "admin"}}, true, CHDefaultRoles{All: 1, List: []string{}, Except: []string{"admin"}}},
+	}
+	for _, tt := range tests {
+		if got := tt.defaultRoles.WithRole("analyst", tt.isDefault); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: WithRole() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCHRoleGrantToResource(t *testing.T) {
+	user, role := "alice", "engineer"
+
+	chRoleGrant := CHRoleGrant{UserName: &user, GrantedRoleName: "analyst", IsDefault: 1}
+	want := &RoleGrantResource{Role: "analyst", Grantee: "alice", Default: true}
+	if got := chRoleGrant.ToResource(); !reflect.DeepEqual(got, want) || !chRoleGrant.IsUserGrant() {
+		t.Errorf("ToResource() = %+v, want %+v", got, want)
+	}
+
+	chRoleGrant = CHRoleGrant{RoleName: &role, GrantedRoleName: "analyst", WithAdminOption: 1}
+	want = &RoleGrantResource{Role: "analyst", Grantee: "engineer", WithAdminOption: true}
+	if got := chRoleGrant.ToResource(); !reflect.DeepEqual(got, want) || chRoleGrant.IsUserGrant() {
+		t.Errorf("ToResource() = %+v, want %+v", got, want)
+	}
+}
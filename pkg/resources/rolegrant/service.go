@@ -0,0 +1,101 @@
+package resourcerolegrant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+// defaultRolesMutex serializes the updates of default roles, which rewrite the whole DEFAULT ROLE clause of the user,
+// so role grants of the same user applied in parallel don't drop each other's default role
+var defaultRolesMutex sync.Mutex
+
+type CHRoleGrantService struct {
+	CHConnection *driver.Conn
+}
+
+// GetRoleGrant returns the grant of the role to the user or role, or nil when it is not granted
+func (gs *CHRoleGrantService) GetRoleGrant(ctx context.Context, role string, grantee string) (*CHRoleGrant, error) {
+	query := "SELECT user_name, role_name, granted_role_name, granted_role_is_default, with_admin_option FROM system.role_grants " +
+		"WHERE granted_role_name = {role:String} AND (user_name = {grantee:String} OR role_name = {grantee:String})"
+	row := (*gs.CHConnection).QueryRow(common.QueryParameters(ctx, map[string]string{"role": role, "grantee": grantee}), query)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("reading role grant from Clickhouse: %v", row.Err())
+	}
+
+	var chRoleGrant CHRoleGrant
+	err := row.ScanStruct(&chRoleGrant)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse role grant row: %v", err)
+	}
+	return &chRoleGrant, nil
+}
+
+func (gs *CHRoleGrantService) CreateRoleGrant(ctx context.Context, roleGrantResource RoleGrantResource) error {
+	if err := (*gs.CHConnection).Exec(ctx, buildGrantRoleSentence(roleGrantResource)); err != nil {
+		return fmt.Errorf("granting Clickhouse role: %v", err)
+	}
+	return gs.UpdateDefaultRole(ctx, roleGrantResource)
+}
+
+// UpdateAdminOption grants the role with the admin option, or revokes the admin option
+func (gs *CHRoleGrantService) UpdateAdminOption(ctx context.Context, roleGrantResource RoleGrantResource) error {
+	query := buildGrantRoleSentence(roleGrantResource)
+	if !roleGrantResource.WithAdminOption {
+		query = buildRevokeRoleSentence(roleGrantResource, true)
+	}
+	if err := (*gs.CHConnection).Exec(ctx, query); err != nil {
+		return fmt.Errorf("updating admin option of Clickhouse role grant: %v", err)
+	}
+	return nil
+}
+
+// UpdateDefaultRole adds or removes the role from the default roles of a user grantee, other default roles are kept,
+// as well as DEFAULT ROLE ALL
+func (gs *CHRoleGrantService) UpdateDefaultRole(ctx context.Context, roleGrantResource RoleGrantResource) error {
+	defaultRolesMutex.Lock()
+	defer defaultRolesMutex.Unlock()
+
+	chRoleGrant, err := gs.GetRoleGrant(ctx, roleGrantResource.Role, roleGrantResource.Grantee)
+	if err != nil {
+		return err
+	}
+	if chRoleGrant == nil {
+		return fmt.Errorf("role %s is not granted to %s", roleGrantResource.Role, roleGrantResource.Grantee)
+	}
+	if !chRoleGrant.IsUserGrant() || (chRoleGrant.IsDefault != 0) == roleGrantResource.Default {
+		return nil
+	}
+
+	query := "SELECT default_roles_all, default_roles_list, default_roles_except FROM system.users WHERE name = {grantee:String}"
+	row := (*gs.CHConnection).QueryRow(common.QueryParameters(ctx, map[string]string{"grantee": roleGrantResource.Grantee}), query)
+	if row.Err() != nil {
+		return fmt.Errorf("reading default roles from Clickhouse: %v", row.Err())
+	}
+	var defaultRoles CHDefaultRoles
+	if err := row.ScanStruct(&defaultRoles); err != nil {
+		return fmt.Errorf("scanning Clickhouse default roles row: %v", err)
+	}
+
+	defaultRoles = defaultRoles.WithRole(roleGrantResource.Role, roleGrantResource.Default)
+	err = (*gs.CHConnection).Exec(ctx, buildDefaultRolesSentence(roleGrantResource.Grantee, roleGrantResource.Cluster, defaultRoles))
+	if err != nil {
+		return fmt.Errorf("updating default roles of Clickhouse user: %v", err)
+	}
+	return nil
+}
+
+func (gs *CHRoleGrantService) DeleteRoleGrant(ctx context.Context, roleGrantResource RoleGrantResource) error {
+	if err := (*gs.CHConnection).Exec(ctx, buildRevokeRoleSentence(roleGrantResource, false)); err != nil {
+		return fmt.Errorf("revoking Clickhouse role: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package resourcerolegrant
+
+import (
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildGrantRoleSentence(roleGrantResource RoleGrantResource) string {
+	parts := []string{"GRANT"}
+	if clusterStatement := common.GetClusterStatement(roleGrantResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	parts = append(parts, common.QuoteIdentifier(roleGrantResource.Role), "TO", common.QuoteIdentifier(roleGrantResource.Grantee))
+	if roleGrantResource.WithAdminOption {
+		parts = append(parts, "WITH ADMIN OPTION")
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildRevokeRoleSentence revokes the role, or only the permission to grant it with adminOptionOnly
+func buildRevokeRoleSentence(roleGrantResource RoleGrantResource, adminOptionOnly bool) string {
+	parts := []string{"REVOKE"}
+	if clusterStatement := common.GetClusterStatement(roleGrantResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if adminOptionOnly {
+		parts = append(parts, "ADMIN OPTION FOR")
+	}
+	parts = append(parts, common.QuoteIdentifier(roleGrantResource.Role), "FROM", common.QuoteIdentifier(roleGrantResource.Grantee))
+	return strings.Join(parts, " ")
+}
+
+// buildDefaultRolesSentence replaces the default roles of the user
+func buildDefaultRolesSentence(user string, cluster string, defaultRoles CHDefaultRoles) string {
+	parts := []string{"ALTER USER", common.QuoteIdentifier(user)}
+	if clusterStatement := common.GetClusterStatement(cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	switch {
+	case defaultRoles.All != 0 && len(defaultRoles.Except) > 0:
+		parts = append(parts, "DEFAULT ROLE ALL EXCEPT "+strings.Join(common.QuoteIdentifiers(defaultRoles.Except), ", "))
+	case defaultRoles.All != 0:
+		parts = append(parts, "DEFAULT ROLE ALL")
+	case len(defaultRoles.List) > 0:
+		parts = append(parts, "DEFAULT ROLE "+strings.Join(common.QuoteIdentifiers(defaultRoles.List), ", "))
+	default:
+		parts = append(parts, "DEFAULT ROLE NONE")
+	}
+	return strings.Join(parts, " ")
+}
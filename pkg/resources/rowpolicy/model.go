@@ -0,0 +1,50 @@
+package resourcerowpolicy
+
+// applyToAll is the element of `to` assigning the policy to every user and role
+const applyToAll = "ALL"
+
+var rowPolicyKinds = []string{"permissive", "restrictive"}
+
+type CHRowPolicy struct {
+	Name          string   `ch:"short_name"`
+	Database      string   `ch:"database"`
+	Table         string   `ch:"table"`
+	SelectFilter  *string  `ch:"select_filter"`
+	IsRestrictive uint8    `ch:"is_restrictive"`
+	ApplyToAll    uint8    `ch:"apply_to_all"`
+	ApplyToList   []string `ch:"apply_to_list"`
+	ApplyToExcept []string `ch:"apply_to_except"`
+}
+
+type RowPolicyResource struct {
+	Name     string
+	Database string
+	Table    string
+	Cluster  string
+	Filter   string
+	Kind     string
+	To       []string
+	ToExcept []string
+}
+
+func (p *CHRowPolicy) ToResource() *RowPolicyResource {
+	rowPolicyResource := RowPolicyResource{
+		Name:     p.Name,
+		Database: p.Database,
+		Table:    p.Table,
+		Kind:     "permissive",
+		To:       []string{},
+		ToExcept: p.ApplyToExcept,
+	}
+	if p.SelectFilter != nil {
+		rowPolicyResource.Filter = *p.SelectFilter
+	}
+	if p.IsRestrictive != 0 {
+		rowPolicyResource.Kind = "restrictive"
+	}
+	if p.ApplyToAll != 0 {
+		rowPolicyResource.To = append(rowPolicyResource.To, applyToAll)
+	}
+	rowPolicyResource.To = append(rowPolicyResource.To, p.ApplyToList...)
+	return &rowPolicyResource
+}
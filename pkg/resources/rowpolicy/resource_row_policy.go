@@ -0,0 +1,214 @@
+package resourcerowpolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceRowPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage row policies, filtering the rows of a table users and roles can read",
+
+		CreateContext: resourceRowPolicyCreate,
+		ReadContext:   resourceRowPolicyRead,
+		UpdateContext: resourceRowPolicyUpdate,
+		DeleteContext: resourceRowPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRowPolicyImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Row policy name, unique per table",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"database": {
+				Description: "DB Name of the table",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"table": {
+				Description: "Table the policy filters",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the policy is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"filter": {
+				Description:      "Condition of the USING clause, rows are visible when it is true, e.g. `tenant_id = 42`. Parentheses the server adds around AND and OR operands are ignored",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentFilter,
+			},
+			"kind": {
+				Description:      "permissive policies of a table are combined with OR, restrictive ones with AND",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "permissive",
+				ValidateFunc:     validation.StringInSlice(rowPolicyKinds, true),
+				DiffSuppressFunc: suppressEquivalentKind,
+			},
+			"to": {
+				Description: "Users and roles the policy applies to, like the names of `clickhouse_role` resources. ALL applies it to everyone",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"to_except": {
+				Description:  "Users and roles excluded from the policy, used with `to = [\"ALL\"]`",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				RequiredWith: []string{"to"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceRowPolicyRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	chRowPolicyService := CHRowPolicyService{CHConnection: client.ClickhouseConnection}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	chRowPolicy, err := chRowPolicyService.GetRowPolicy(ctx, database, table, d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse row policy: %v", err))
+	}
+	if chRowPolicy == nil {
+		d.SetId("")
+		return diags
+	}
+
+	rowPolicyResource := chRowPolicy.ToResource()
+	if err := d.Set("name", rowPolicyResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("database", rowPolicyResource.Database); err != nil {
+		return diag.FromErr(fmt.Errorf("setting database: %v", err))
+	}
+	if err := d.Set("table", rowPolicyResource.Table); err != nil {
+		return diag.FromErr(fmt.Errorf("setting table: %v", err))
+	}
+	if err := d.Set("filter", rowPolicyResource.Filter); err != nil {
+		return diag.FromErr(fmt.Errorf("setting filter: %v", err))
+	}
+	if err := d.Set("kind", rowPolicyResource.Kind); err != nil {
+		return diag.FromErr(fmt.Errorf("setting kind: %v", err))
+	}
+	if err := d.Set("to", common.StringListToSet(rowPolicyResource.To)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting to: %v", err))
+	}
+	if err := d.Set("to_except", common.StringListToSet(rowPolicyResource.ToExcept)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting to_except: %v", err))
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + database + ":" + table + ":" + rowPolicyResource.Name)
+
+	return diags
+}
+
+func resourceRowPolicyImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "database", "table", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("database", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting database: %v", err)
+	}
+	if err := d.Set("table", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting table: %v", err)
+	}
+	if err := d.Set("name", idParts[3]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceRowPolicyCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chRowPolicyService := CHRowPolicyService{CHConnection: client.ClickhouseConnection}
+
+	rowPolicyResource := rowPolicyResourceFromData(d, client)
+	if err := chRowPolicyService.CreateRowPolicy(ctx, rowPolicyResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + rowPolicyResource.Database + ":" + rowPolicyResource.Table + ":" + rowPolicyResource.Name)
+
+	return resourceRowPolicyRead(ctx, d, meta)
+}
+
+func resourceRowPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chRowPolicyService := CHRowPolicyService{CHConnection: client.ClickhouseConnection}
+
+	stateName, _ := d.GetChange("name")
+	rowPolicyResource := rowPolicyResourceFromData(d, client)
+	if err := chRowPolicyService.UpdateRowPolicy(ctx, stateName.(string), rowPolicyResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + rowPolicyResource.Database + ":" + rowPolicyResource.Table + ":" + rowPolicyResource.Name)
+
+	return resourceRowPolicyRead(ctx, d, meta)
+}
+
+func resourceRowPolicyDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chRowPolicyService := CHRowPolicyService{CHConnection: client.ClickhouseConnection}
+
+	if err := chRowPolicyService.DeleteRowPolicy(ctx, rowPolicyResourceFromData(d, client)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func rowPolicyResourceFromData(d *schema.ResourceData, client *common.ApiClient) RowPolicyResource {
+	rowPolicyResource := RowPolicyResource{
+		Name:     d.Get("name").(string),
+		Database: d.Get("database").(string),
+		Table:    d.Get("table").(string),
+		Cluster:  d.Get("cluster").(string),
+		Filter:   d.Get("filter").(string),
+		Kind:     strings.ToLower(d.Get("kind").(string)),
+		To:       common.StringSetToList(d.Get("to").(*schema.Set)),
+		ToExcept: common.StringSetToList(d.Get("to_except").(*schema.Set)),
+	}
+	if rowPolicyResource.Cluster == "" {
+		rowPolicyResource.Cluster = client.DefaultCluster
+	}
+	return rowPolicyResource
+}
+
+func suppressEquivalentFilter(k, old, new string, d *schema.ResourceData) bool {
+	return common.NormalizeQuery(old) == common.NormalizeQuery(new)
+}
+
+func suppressEquivalentKind(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
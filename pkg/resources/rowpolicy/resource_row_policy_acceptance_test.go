@@ -0,0 +1,80 @@
+package resourcerowpolicy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceRowPolicyName = "test_row_policy"
+
+func TestAccResourceRowPolicy(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: rowPolicyConfig(testResourceRowPolicyName, "tenant_id = 1", "permissive"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_row_policy.policy", "name", testResourceRowPolicyName),
+					resource.TestCheckResourceAttr("clickhouse_row_policy.policy", "filter", "tenant_id = 1"),
+					resource.TestCheckResourceAttr("clickhouse_row_policy.policy", "kind", "permissive"),
+					resource.TestCheckResourceAttr("clickhouse_row_policy.policy", "to.#", "1"),
+				),
+			},
+			// ALTER THE FILTER AND THE KIND
+			{
+				Config: rowPolicyConfig(testResourceRowPolicyName, "tenant_id = 2", "restrictive"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_row_policy.policy", "filter", "tenant_id = 2"),
+					resource.TestCheckResourceAttr("clickhouse_row_policy.policy", "kind", "restrictive"),
+				),
+			},
+			{
+				ResourceName:      "clickhouse_row_policy.policy",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func rowPolicyConfig(name string, filter string, kind string) string {
+	s := `
+	resource "clickhouse_db" "db" {
+		name = "test_row_policy_db"
+	}
+
+	resource "clickhouse_table" "table" {
+		database = clickhouse_db.db.name
+		name = "events"
+		engine = "MergeTree"
+		order_by = ["tenant_id"]
+		column {
+			name = "tenant_id"
+			type = "UInt64"
+		}
+	}
+
+	resource "clickhouse_role" "role" {
+		name = "test_row_policy_role"
+		database = clickhouse_db.db.name
+		privileges = ["SELECT"]
+	}
+
+	resource "clickhouse_row_policy" "policy" {
+		name = "%_name_%"
+		database = clickhouse_db.db.name
+		table = clickhouse_table.table.name
+		filter = "%_filter_%"
+		kind = "%_kind_%"
+		to = [clickhouse_role.role.name]
+	}
+	`
+	s = strings.Replace(s, "%_name_%", name, -1)
+	s = strings.Replace(s, "%_filter_%", filter, -1)
+	s = strings.Replace(s, "%_kind_%", kind, -1)
+	return s
+}
@@ -0,0 +1,85 @@
+package resourcerowpolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildRowPolicySentences(t *testing.T) {
+	rowPolicyResource := RowPolicyResource{
+		Name:     "tenant",
+		Database: "events",
+		Table:    "clicks",
+		Cluster:  "main",
+		Filter:   "tenant_id = 42",
+		Kind:     "restrictive",
+		To:       []string{"ALL"},
+		ToExcept: []string{"admin"},
+	}
+
+	want := "CREATE ROW POLICY `tenant` ON CLUSTER `main` ON `events`.`clicks` FOR SELECT USING tenant_id = 42 AS RESTRICTIVE TO ALL EXCEPT `admin`"
+	if got := buildCreateRowPolicySentence(rowPolicyResource); got != want {
+		t.Errorf("buildCreateRowPolicySentence() = %s, want %s", got, want)
+	}
+
+	want = "ALTER ROW POLICY `old_tenant` ON CLUSTER `main` ON `events`.`clicks` RENAME TO `tenant` FOR SELECT USING tenant_id = 42 AS RESTRICTIVE TO ALL EXCEPT `admin`"
+	if got := buildAlterRowPolicySentence("old_tenant", rowPolicyResource); got != want {
+		t.Errorf("buildAlterRowPolicySentence() = %s, want %s", got, want)
+	}
+
+	rowPolicyResource = RowPolicyResource{Name: "tenant", Database: "events", Table: "clicks", Filter: "1", Kind: "permissive"}
+	want = "ALTER ROW POLICY `tenant` ON `events`.`clicks` FOR SELECT USING 1 AS PERMISSIVE TO NONE"
+	if got := buildAlterRowPolicySentence("tenant", rowPolicyResource); got != want {
+		t.Errorf("buildAlterRowPolicySentence() = %s, want %s", got, want)
+	}
+
+	if got, want := buildDropRowPolicySentence(rowPolicyResource), "DROP ROW POLICY `tenant` ON `events`.`clicks`"; got != want {
+		t.Errorf("buildDropRowPolicySentence() = %s, want %s", got, want)
+	}
+}
+
+func TestCHRowPolicyToResource(t *testing.T) {
+	filter := "tenant_id = 42"
+	chRowPolicy := CHRowPolicy{
+		Name:          "tenant",
+		Database:      "events",
+		Table:         "clicks",
+		SelectFilter:  &filter,
+		IsRestrictive: 1,
+		ApplyToAll:    1,
+		ApplyToList:   []string{},
+		ApplyToExcept: []string{"admin"},
+	}
+
+	want := &RowPolicyResource{
+		Name:     "tenant",
+		Database: "events",
+		Table:    "clicks",
+		Filter:   "tenant_id = 42",
+		Kind:     "restrictive",
+		To:       []string{"ALL"},
+		ToExcept: []string{"admin"},
+	}
+	if got := chRowPolicy.ToResource(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToResource() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSuppressEquivalentFilter(t *testing.T) {
+	tests := []struct {
+		old  string
+		new  string
+		want bool
+	}{
+		{"tenant_id = 42", "tenant_id=42", true},
+		{"(tenant_id = 42) AND (region = 'eu')", "tenant_id = 42 and region = 'eu'", true},
+		{"((tenant_id = 42) AND (region = 'eu')) OR (is_public = 1)", "tenant_id = 42 AND region = 'eu' OR is_public = 1", true},
+		{"(tenant_id = 42) AND ((region = 'eu') OR (is_public = 1))", "tenant_id = 42 AND region = 'eu' OR is_public = 1", false},
+		{"tenant_id = 42", "tenant_id = 43", false},
+	}
+	for _, tt := range tests {
+		if got := suppressEquivalentFilter("filter", tt.old, tt.new, nil); got != tt.want {
+			t.Errorf("suppressEquivalentFilter(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package resourcerowpolicy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHRowPolicyService struct {
+	CHConnection *driver.Conn
+}
+
+// GetRowPolicy returns the policy of the table, or nil when it does not exist
+func (ps *CHRowPolicyService) GetRowPolicy(ctx context.Context, database string, table string, name string) (*CHRowPolicy, error) {
+	ctx = common.QueryParameters(ctx, map[string]string{"database": database, "table": table, "name": name})
+	row := (*ps.CHConnection).QueryRow(ctx, "SELECT short_name, database, table, select_filter, is_restrictive, apply_to_all, apply_to_list, apply_to_except "+
+		"FROM system.row_policies WHERE database = {database:String} AND table = {table:String} AND short_name = {name:String}")
+	if row.Err() != nil {
+		return nil, fmt.Errorf("reading row policy from Clickhouse: %v", row.Err())
+	}
+
+	var chRowPolicy CHRowPolicy
+	err := row.ScanStruct(&chRowPolicy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse row policy row: %v", err)
+	}
+	return &chRowPolicy, nil
+}
+
+func (ps *CHRowPolicyService) CreateRowPolicy(ctx context.Context, rowPolicyResource RowPolicyResource) error {
+	err := (*ps.CHConnection).Exec(ctx, buildCreateRowPolicySentence(rowPolicyResource))
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse row policy: %v", err)
+	}
+	return nil
+}
+
+func (ps *CHRowPolicyService) UpdateRowPolicy(ctx context.Context, stateName string, rowPolicyResource RowPolicyResource) error {
+	err := (*ps.CHConnection).Exec(ctx, buildAlterRowPolicySentence(stateName, rowPolicyResource))
+	if err != nil {
+		return fmt.Errorf("updating Clickhouse row policy: %v", err)
+	}
+	return nil
+}
+
+func (ps *CHRowPolicyService) DeleteRowPolicy(ctx context.Context, rowPolicyResource RowPolicyResource) error {
+	err := (*ps.CHConnection).Exec(ctx, buildDropRowPolicySentence(rowPolicyResource))
+	if err != nil {
+		return fmt.Errorf("deleting Clickhouse row policy: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package resourcerowpolicy
+
+import (
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildCreateRowPolicySentence(rowPolicyResource RowPolicyResource) string {
+	parts := []string{"CREATE ROW POLICY", common.QuoteIdentifier(rowPolicyResource.Name)}
+	if clusterStatement := common.GetClusterStatement(rowPolicyResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	parts = append(parts, "ON "+common.QuoteTableName(rowPolicyResource.Database, rowPolicyResource.Table))
+	parts = append(parts, buildPolicyClauses(rowPolicyResource)...)
+	if len(rowPolicyResource.To) > 0 {
+		parts = append(parts, "TO "+buildRolesList(rowPolicyResource))
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildAlterRowPolicySentence(stateName string, rowPolicyResource RowPolicyResource) string {
+	parts := []string{"ALTER ROW POLICY", common.QuoteIdentifier(stateName)}
+	if clusterStatement := common.GetClusterStatement(rowPolicyResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	parts = append(parts, "ON "+common.QuoteTableName(rowPolicyResource.Database, rowPolicyResource.Table))
+	if stateName != rowPolicyResource.Name {
+		parts = append(parts, "RENAME TO "+common.QuoteIdentifier(rowPolicyResource.Name))
+	}
+	parts = append(parts, buildPolicyClauses(rowPolicyResource)...)
+	if len(rowPolicyResource.To) > 0 {
+		parts = append(parts, "TO "+buildRolesList(rowPolicyResource))
+	} else {
+		parts = append(parts, "TO NONE")
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildDropRowPolicySentence(rowPolicyResource RowPolicyResource) string {
+	parts := []string{"DROP ROW POLICY", common.QuoteIdentifier(rowPolicyResource.Name)}
+	if clusterStatement := common.GetClusterStatement(rowPolicyResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	parts = append(parts, "ON "+common.QuoteTableName(rowPolicyResource.Database, rowPolicyResource.Table))
+	return strings.Join(parts, " ")
+}
+
+func buildPolicyClauses(rowPolicyResource RowPolicyResource) []string {
+	return []string{"FOR SELECT USING " + rowPolicyResource.Filter, "AS " + strings.ToUpper(rowPolicyResource.Kind)}
+}
+
+// buildRolesList renders the assignees of the policy, ALL is a keyword and the others are users or roles
+func buildRolesList(rowPolicyResource RowPolicyResource) string {
+	roles := make([]string, 0, len(rowPolicyResource.To))
+	for _, role := range rowPolicyResource.To {
+		if role == applyToAll {
+			roles = append(roles, applyToAll)
+		} else {
+			roles = append(roles, common.QuoteIdentifier(role))
+		}
+	}
+	rolesList := strings.Join(roles, ", ")
+	if len(rowPolicyResource.ToExcept) > 0 {
+		rolesList += " EXCEPT " + strings.Join(common.QuoteIdentifiers(rowPolicyResource.ToExcept), ", ")
+	}
+	return rolesList
+}
@@ -0,0 +1,87 @@
+package resourcesettingsprofile
+
+import (
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+// settingWritabilities are the constraints of a setting, READONLY is an alias of CONST
+var settingWritabilities = []string{"READONLY", "CONST", "WRITABLE", "CHANGEABLE_IN_READONLY"}
+
+type CHSettingsProfile struct {
+	Name        string   `ch:"name"`
+	ApplyToList []string `ch:"apply_to_list"`
+	Elements    []CHSettingsProfileElement
+}
+
+// CHSettingsProfileElement is a row of system.settings_profile_elements, either a setting or an inherited profile
+type CHSettingsProfileElement struct {
+	Index          uint64  `ch:"index"`
+	SettingName    *string `ch:"setting_name"`
+	Value          *string `ch:"value"`
+	Min            *string `ch:"min"`
+	Max            *string `ch:"max"`
+	Writability    *string `ch:"writability"`
+	InheritProfile *string `ch:"inherit_profile"`
+}
+
+type SettingsProfileResource struct {
+	Name     string
+	Cluster  string
+	Settings []SettingResource
+	Inherit  []string
+	To       []string
+}
+
+type SettingResource struct {
+	Name        string
+	Value       string
+	Min         string
+	Max         string
+	Writability string
+}
+
+func (p *CHSettingsProfile) ToResource() *SettingsProfileResource {
+	profileResource := SettingsProfileResource{
+		Name:     p.Name,
+		Settings: []SettingResource{},
+		Inherit:  []string{},
+		To:       p.ApplyToList,
+	}
+	for _, element := range p.Elements {
+		if element.InheritProfile != nil {
+			profileResource.Inherit = append(profileResource.Inherit, *element.InheritProfile)
+			continue
+		}
+		if element.SettingName == nil {
+			continue
+		}
+		profileResource.Settings = append(profileResource.Settings, SettingResource{
+			Name:        *element.SettingName,
+			Value:       settingValue(element.Value),
+			Min:         settingValue(element.Min),
+			Max:         settingValue(element.Max),
+			Writability: stringValue(element.Writability),
+		})
+	}
+	return &profileResource
+}
+
+// settingValue returns a value of system.settings_profile_elements, which reports string settings quoted
+func settingValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	if strings.HasPrefix(*value, "'") && strings.HasSuffix(*value, "'") && len(*value) > 1 {
+		return common.UnquoteString(*value)
+	}
+	return *value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,219 @@
+package resourcesettingsprofile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceSettingsProfile() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage settings profiles. Users and roles reference profiles by name with their `settings_profiles` attribute",
+
+		CreateContext: resourceSettingsProfileCreate,
+		ReadContext:   resourceSettingsProfileRead,
+		UpdateContext: resourceSettingsProfileUpdate,
+		DeleteContext: resourceSettingsProfileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSettingsProfileImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Settings profile name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the profile is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"setting": {
+				Description: "Settings of the profile with their constraints",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "Setting name",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"value": {
+							Description: "Setting value",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"min": {
+							Description: "Minimum value users can set",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"max": {
+							Description: "Maximum value users can set",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"writability": {
+							Description:      "Constraint on changes of the setting, one of " + strings.Join(settingWritabilities, ", ") + ". READONLY is an alias of CONST",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validation.StringInSlice(settingWritabilities, true),
+							DiffSuppressFunc: suppressEquivalentWritability,
+						},
+					},
+				},
+			},
+			"inherit": {
+				Description: "Profiles whose settings this profile inherits, settings of the profile take precedence",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"to": {
+				Description: "Users and roles the profile is assigned to",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceSettingsProfileRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	chSettingsProfileService := CHSettingsProfileService{CHConnection: client.ClickhouseConnection}
+
+	chProfile, err := chSettingsProfileService.GetSettingsProfile(ctx, d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse settings profile: %v", err))
+	}
+	if chProfile == nil {
+		d.SetId("")
+		return diags
+	}
+
+	profileResource := chProfile.ToResource()
+	settings := make([]map[string]any, 0, len(profileResource.Settings))
+	for _, setting := range profileResource.Settings {
+		settings = append(settings, map[string]any{
+			"name":        setting.Name,
+			"value":       setting.Value,
+			"min":         setting.Min,
+			"max":         setting.Max,
+			"writability": setting.Writability,
+		})
+	}
+
+	if err := d.Set("name", profileResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("setting", settings); err != nil {
+		return diag.FromErr(fmt.Errorf("setting setting: %v", err))
+	}
+	if err := d.Set("inherit", profileResource.Inherit); err != nil {
+		return diag.FromErr(fmt.Errorf("setting inherit: %v", err))
+	}
+	if err := d.Set("to", common.StringListToSet(profileResource.To)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting to: %v", err))
+	}
+
+	d.SetId(profileResource.Name)
+
+	return diags
+}
+
+func resourceSettingsProfileImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if err := d.Set("name", d.Id()); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceSettingsProfileCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chSettingsProfileService := CHSettingsProfileService{CHConnection: client.ClickhouseConnection}
+
+	profileResource := settingsProfileResourceFromData(d, client)
+	if err := chSettingsProfileService.CreateSettingsProfile(ctx, profileResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(profileResource.Name)
+
+	return resourceSettingsProfileRead(ctx, d, meta)
+}
+
+func resourceSettingsProfileUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chSettingsProfileService := CHSettingsProfileService{CHConnection: client.ClickhouseConnection}
+
+	// The profile is altered instead of recreated, so users and roles keep referencing it
+	stateName, _ := d.GetChange("name")
+	profileResource := settingsProfileResourceFromData(d, client)
+	if err := chSettingsProfileService.UpdateSettingsProfile(ctx, stateName.(string), profileResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(profileResource.Name)
+
+	return resourceSettingsProfileRead(ctx, d, meta)
+}
+
+func resourceSettingsProfileDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chSettingsProfileService := CHSettingsProfileService{CHConnection: client.ClickhouseConnection}
+
+	if err := chSettingsProfileService.DeleteSettingsProfile(ctx, settingsProfileResourceFromData(d, client)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func settingsProfileResourceFromData(d *schema.ResourceData, client *common.ApiClient) SettingsProfileResource {
+	profileResource := SettingsProfileResource{
+		Name:    d.Get("name").(string),
+		Cluster: d.Get("cluster").(string),
+		Inherit: common.MapArrayInterfaceToArrayOfStrings(d.Get("inherit").([]interface{})),
+		To:      common.StringSetToList(d.Get("to").(*schema.Set)),
+	}
+	for _, setting := range d.Get("setting").([]interface{}) {
+		settingMap := setting.(map[string]interface{})
+		profileResource.Settings = append(profileResource.Settings, SettingResource{
+			Name:        settingMap["name"].(string),
+			Value:       settingMap["value"].(string),
+			Min:         settingMap["min"].(string),
+			Max:         settingMap["max"].(string),
+			Writability: strings.ToUpper(settingMap["writability"].(string)),
+		})
+	}
+	if profileResource.Cluster == "" {
+		profileResource.Cluster = client.DefaultCluster
+	}
+	return profileResource
+}
+
+// suppressEquivalentWritability ignores the READONLY alias, which the server reports as CONST
+func suppressEquivalentWritability(k, old, new string, d *schema.ResourceData) bool {
+	canonical := func(writability string) string {
+		writability = strings.ToUpper(writability)
+		if writability == "READONLY" {
+			return "CONST"
+		}
+		return writability
+	}
+	return canonical(old) == canonical(new)
+}
@@ -0,0 +1,72 @@
+package resourcesettingsprofile_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceSettingsProfileName = "test_settings_profile"
+
+func TestAccResourceSettingsProfile(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: settingsProfileConfig(testResourceSettingsProfileName, "10000000000"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_settings_profile.profile", "name", testResourceSettingsProfileName),
+					resource.TestCheckResourceAttr("clickhouse_settings_profile.profile", "setting.#", "2"),
+					resource.TestCheckResourceAttr("clickhouse_settings_profile.profile", "setting.0.value", "10000000000"),
+					resource.TestCheckResourceAttr("clickhouse_settings_profile.profile", "inherit.0", "default"),
+					resource.TestCheckResourceAttr("clickhouse_user.user", "settings_profiles.0", testResourceSettingsProfileName),
+				),
+			},
+			// ALTER THE PROFILE, THE USER KEEPS REFERENCING IT
+			{
+				Config: settingsProfileConfig(testResourceSettingsProfileName, "20000000000"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_settings_profile.profile", "setting.0.value", "20000000000"),
+					resource.TestCheckResourceAttr("clickhouse_user.user", "settings_profiles.0", testResourceSettingsProfileName),
+				),
+			},
+			{
+				ResourceName:      "clickhouse_settings_profile.profile",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func settingsProfileConfig(name string, maxMemoryUsage string) string {
+	s := `
+	resource "clickhouse_settings_profile" "profile" {
+		name = "%_name_%"
+		inherit = ["default"]
+		setting {
+			name = "max_memory_usage"
+			value = "%_max_memory_usage_%"
+			max = "30000000000"
+			writability = "CHANGEABLE_IN_READONLY"
+		}
+		setting {
+			name = "readonly"
+			value = "1"
+			writability = "CONST"
+		}
+	}
+
+	resource "clickhouse_user" "user" {
+		name = "test_settings_profile_user"
+		password = "test_settings_profile_password"
+		settings_profiles = [clickhouse_settings_profile.profile.name]
+	}
+	`
+	s = strings.Replace(s, "%_name_%", name, -1)
+	s = strings.Replace(s, "%_max_memory_usage_%", maxMemoryUsage, -1)
+	return s
+}
@@ -0,0 +1,80 @@
+package resourcesettingsprofile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildSettingsProfileSentences(t *testing.T) {
+	profileResource := SettingsProfileResource{
+		Name:    "analysts",
+		Cluster: "main",
+		Inherit: []string{"default"},
+		Settings: []SettingResource{
+			{Name: "max_memory_usage", Value: "10000000000", Min: "1000000", Max: "20000000000", Writability: "CHANGEABLE_IN_READONLY"},
+			{Name: "readonly", Value: "1", Writability: "CONST"},
+		},
+		To: []string{"alice"},
+	}
+
+	want := "CREATE SETTINGS PROFILE `analysts` ON CLUSTER `main` SETTINGS INHERIT `default`, " +
+		"`max_memory_usage` = '10000000000' MIN '1000000' MAX '20000000000' CHANGEABLE_IN_READONLY, `readonly` = '1' CONST TO `alice`"
+	if got := buildCreateSettingsProfileSentence(profileResource); got != want {
+		t.Errorf("buildCreateSettingsProfileSentence() = %s, want %s", got, want)
+	}
+
+	want = "ALTER SETTINGS PROFILE `old_analysts` ON CLUSTER `main` RENAME TO `analysts` SETTINGS INHERIT `default`, " +
+		"`max_memory_usage` = '10000000000' MIN '1000000' MAX '20000000000' CHANGEABLE_IN_READONLY, `readonly` = '1' CONST TO `alice`"
+	if got := buildAlterSettingsProfileSentence("old_analysts", profileResource); got != want {
+		t.Errorf("buildAlterSettingsProfileSentence() = %s, want %s", got, want)
+	}
+
+	want = "ALTER SETTINGS PROFILE `analysts` SETTINGS NONE TO NONE"
+	if got := buildAlterSettingsProfileSentence("analysts", SettingsProfileResource{Name: "analysts"}); got != want {
+		t.Errorf("buildAlterSettingsProfileSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestBuildProfilesClause(t *testing.T) {
+	if got, want := BuildProfilesClause([]string{"default", "analysts"}), "SETTINGS PROFILE `default`, PROFILE `analysts`"; got != want {
+		t.Errorf("BuildProfilesClause() = %s, want %s", got, want)
+	}
+	if got, want := BuildProfilesClause(nil), "SETTINGS NONE"; got != want {
+		t.Errorf("BuildProfilesClause() = %s, want %s", got, want)
+	}
+}
+
+func TestCHSettingsProfileToResource(t *testing.T) {
+	str := func(s string) *string { return &s }
+	chProfile := CHSettingsProfile{
+		Name:        "analysts",
+		ApplyToList: []string{"alice"},
+		Elements: []CHSettingsProfileElement{
+			{Index: 0, InheritProfile: str("default")},
+			{Index: 1, SettingName: str("max_memory_usage"), Value: str("10000000000"), Max: str("20000000000")},
+			{Index: 2, SettingName: str("load_balancing"), Value: str("'random'"), Writability: str("CONST")},
+		},
+	}
+
+	want := &SettingsProfileResource{
+		Name:    "analysts",
+		Inherit: []string{"default"},
+		Settings: []SettingResource{
+			{Name: "max_memory_usage", Value: "10000000000", Max: "20000000000"},
+			{Name: "load_balancing", Value: "random", Writability: "CONST"},
+		},
+		To: []string{"alice"},
+	}
+	if got := chProfile.ToResource(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToResource() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSuppressEquivalentWritability(t *testing.T) {
+	if !suppressEquivalentWritability("", "CONST", "readonly", nil) {
+		t.Errorf("READONLY should be equivalent to CONST")
+	}
+	if suppressEquivalentWritability("", "CONST", "WRITABLE", nil) {
+		t.Errorf("WRITABLE should not be equivalent to CONST")
+	}
+}
@@ -0,0 +1,94 @@
+package resourcesettingsprofile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHSettingsProfileService struct {
+	CHConnection *driver.Conn
+}
+
+// GetSettingsProfile returns the profile, or nil when it does not exist
+func (ps *CHSettingsProfileService) GetSettingsProfile(ctx context.Context, name string) (*CHSettingsProfile, error) {
+	ctx = common.QueryParameters(ctx, map[string]string{"name": name})
+	rows, err := (*ps.CHConnection).Query(ctx, "SELECT name, apply_to_list FROM system.settings_profiles WHERE name = {name:String}")
+	if err != nil {
+		return nil, fmt.Errorf("reading settings profile from Clickhouse: %v", err)
+	}
+	if !rows.Next() {
+		return nil, nil
+	}
+	var chProfile CHSettingsProfile
+	if err := rows.ScanStruct(&chProfile); err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse settings profile row: %v", err)
+	}
+
+	chProfile.Elements, err = ps.getElements(ctx, "profile_name", name)
+	if err != nil {
+		return nil, err
+	}
+	return &chProfile, nil
+}
+
+// GetAssignedProfiles returns the profiles a user or a role inherits, ownerColumn is user_name or role_name
+func (ps *CHSettingsProfileService) GetAssignedProfiles(ctx context.Context, ownerColumn string, name string) ([]string, error) {
+	elements, err := ps.getElements(common.QueryParameters(ctx, map[string]string{"name": name}), ownerColumn, name)
+	if err != nil {
+		return nil, err
+	}
+	profiles := []string{}
+	for _, element := range elements {
+		if element.InheritProfile != nil {
+			profiles = append(profiles, *element.InheritProfile)
+		}
+	}
+	return profiles, nil
+}
+
+func (ps *CHSettingsProfileService) getElements(ctx context.Context, ownerColumn string, name string) ([]CHSettingsProfileElement, error) {
+	query := fmt.Sprintf("SELECT index, setting_name, value, min, max, toString(writability) AS writability, inherit_profile "+
+		"FROM system.settings_profile_elements WHERE %s = {name:String} ORDER BY index", common.QuoteIdentifier(ownerColumn))
+	rows, err := (*ps.CHConnection).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading settings profile elements from Clickhouse: %v", err)
+	}
+
+	var elements []CHSettingsProfileElement
+	for rows.Next() {
+		var element CHSettingsProfileElement
+		if err := rows.ScanStruct(&element); err != nil {
+			return nil, fmt.Errorf("scanning Clickhouse settings profile element row: %v", err)
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+func (ps *CHSettingsProfileService) CreateSettingsProfile(ctx context.Context, profileResource SettingsProfileResource) error {
+	err := (*ps.CHConnection).Exec(ctx, buildCreateSettingsProfileSentence(profileResource))
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse settings profile: %v", err)
+	}
+	return nil
+}
+
+func (ps *CHSettingsProfileService) UpdateSettingsProfile(ctx context.Context, stateName string, profileResource SettingsProfileResource) error {
+	err := (*ps.CHConnection).Exec(ctx, buildAlterSettingsProfileSentence(stateName, profileResource))
+	if err != nil {
+		return fmt.Errorf("updating Clickhouse settings profile: %v", err)
+	}
+	return nil
+}
+
+func (ps *CHSettingsProfileService) DeleteSettingsProfile(ctx context.Context, profileResource SettingsProfileResource) error {
+	query := fmt.Sprintf("DROP SETTINGS PROFILE %s %s", common.QuoteIdentifier(profileResource.Name), common.GetClusterStatement(profileResource.Cluster))
+	err := (*ps.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("deleting Clickhouse settings profile: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package resourcesettingsprofile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildCreateSettingsProfileSentence(profileResource SettingsProfileResource) string {
+	parts := []string{"CREATE SETTINGS PROFILE", common.QuoteIdentifier(profileResource.Name)}
+	if clusterStatement := common.GetClusterStatement(profileResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if elements := buildSettingsElements(profileResource); len(elements) > 0 {
+		parts = append(parts, "SETTINGS "+strings.Join(elements, ", "))
+	}
+	if len(profileResource.To) > 0 {
+		parts = append(parts, "TO "+strings.Join(common.QuoteIdentifiers(profileResource.To), ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildAlterSettingsProfileSentence replaces the settings, inherited profiles and assignees of the profile
+func buildAlterSettingsProfileSentence(stateName string, profileResource SettingsProfileResource) string {
+	parts := []string{"ALTER SETTINGS PROFILE", common.QuoteIdentifier(stateName)}
+	if clusterStatement := common.GetClusterStatement(profileResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if stateName != profileResource.Name {
+		parts = append(parts, "RENAME TO "+common.QuoteIdentifier(profileResource.Name))
+	}
+	if elements := buildSettingsElements(profileResource); len(elements) > 0 {
+		parts = append(parts, "SETTINGS "+strings.Join(elements, ", "))
+	} else {
+		parts = append(parts, "SETTINGS NONE")
+	}
+	if len(profileResource.To) > 0 {
+		parts = append(parts, "TO "+strings.Join(common.QuoteIdentifiers(profileResource.To), ", "))
+	} else {
+		parts = append(parts, "TO NONE")
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildSettingsElements renders the inherited profiles first, so the settings of the profile override them
+func buildSettingsElements(profileResource SettingsProfileResource) []string {
+	elements := make([]string, 0, len(profileResource.Inherit)+len(profileResource.Settings))
+	for _, profile := range profileResource.Inherit {
+		elements = append(elements, "INHERIT "+common.QuoteIdentifier(profile))
+	}
+	for _, setting := range profileResource.Settings {
+		element := []string{common.QuoteIdentifier(setting.Name)}
+		if setting.Value != "" {
+			element = append(element, "= "+common.QuoteString(setting.Value))
+		}
+		if setting.Min != "" {
+			element = append(element, "MIN "+common.QuoteString(setting.Min))
+		}
+		if setting.Max != "" {
+			element = append(element, "MAX "+common.QuoteString(setting.Max))
+		}
+		if setting.Writability != "" {
+			element = append(element, setting.Writability)
+		}
+		elements = append(elements, strings.Join(element, " "))
+	}
+	return elements
+}
+
+// BuildProfilesClause renders the SETTINGS clause assigning profiles to a user or a role,
+// it replaces all the settings of the user or role
+func BuildProfilesClause(profiles []string) string {
+	if len(profiles) == 0 {
+		return "SETTINGS NONE"
+	}
+	elements := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		elements = append(elements, fmt.Sprintf("PROFILE %s", common.QuoteIdentifier(profile)))
+	}
+	return "SETTINGS " + strings.Join(elements, ", ")
+}
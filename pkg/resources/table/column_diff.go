@@ -0,0 +1,207 @@
+package resourcetable
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type ColumnRename struct {
+	From string
+	To   string
+}
+
+type ColumnModification struct {
+	From ColumnResource
+	To   ColumnResource
+}
+
+type ColumnPosition struct {
+	Column ColumnResource
+	// After is the column the given one is placed after, empty means FIRST
+	After string
+}
+
+// ColumnsDiff holds the ALTER TABLE operations needed to turn a list of columns into another one.
+// Operations are meant to be applied in order: renames, drops, modifications, additions and moves.
+type ColumnsDiff struct {
+	Renamed  []ColumnRename
+	Dropped  []ColumnResource
+	Modified []ColumnModification
+	Added    []ColumnPosition
+	Moved    []ColumnPosition
+}
+
+func (cd *ColumnsDiff) IsEmpty() bool {
+	return len(cd.Renamed) == 0 && len(cd.Dropped) == 0 && len(cd.Modified) == 0 && len(cd.Added) == 0 && len(cd.Moved) == 0
+}
+
+// GetDefaultKind returns the kind of the column default expression, DEFAULT is assumed when only the expression is given
+func (c ColumnResource) GetDefaultKind() string {
+	if c.DefaultKind == "" && c.DefaultExpression != "" {
+		return "DEFAULT"
+	}
+	return strings.ToUpper(c.DefaultKind)
+}
+
+func (c ColumnResource) definitionEquals(other ColumnResource) bool {
+	return DataTypesEquivalent(c.Type, other.Type) &&
+		c.GetDefaultKind() == other.GetDefaultKind() &&
+		ExpressionsEquivalent(c.DefaultExpression, other.DefaultExpression) &&
+		CodecsEquivalent(c.Codec, other.Codec) &&
+		NormalizeExpression(c.TTL) == NormalizeExpression(other.TTL) &&
+		c.Comment == other.Comment
+}
+
+// DiffColumns matches columns by name. With detectRenames, a column replaced at the same position by another one
+// with a new name and the very same definition is considered a rename, otherwise it is dropped and added.
+func DiffColumns(oldColumns []ColumnResource, newColumns []ColumnResource, detectRenames bool) ColumnsDiff {
+	var diff ColumnsDiff
+
+	oldByName := make(map[string]ColumnResource)
+	for _, column := range oldColumns {
+		oldByName[column.Name] = column
+	}
+	newByName := make(map[string]ColumnResource)
+	for _, column := range newColumns {
+		newByName[column.Name] = column
+	}
+
+	// new name -> old name and old name -> new name
+	renamedFrom := make(map[string]string)
+	renamedTo := make(map[string]string)
+	for i := 0; detectRenames && i < len(oldColumns) && i < len(newColumns); i++ {
+		oldColumn, newColumn := oldColumns[i], newColumns[i]
+		_, oldStillExists := newByName[oldColumn.Name]
+		_, newAlreadyExists := oldByName[newColumn.Name]
+		if oldColumn.Name != newColumn.Name && !oldStillExists && !newAlreadyExists && oldColumn.definitionEquals(newColumn) {
+			diff.Renamed = append(diff.Renamed, ColumnRename{From: oldColumn.Name, To: newColumn.Name})
+			renamedFrom[newColumn.Name] = oldColumn.Name
+			renamedTo[oldColumn.Name] = newColumn.Name
+		}
+	}
+
+	// Column names as they are after renames and drops are applied
+	var current []string
+	for _, column := range oldColumns {
+		if _, ok := newByName[column.Name]; ok {
+			current = append(current, column.Name)
+		} else if newName, ok := renamedTo[column.Name]; ok {
+			current = append(current, newName)
+		} else {
+			diff.Dropped = append(diff.Dropped, column)
+		}
+	}
+
+	for _, column := range newColumns {
+		if oldColumn, ok := oldByName[column.Name]; ok && !oldColumn.definitionEquals(column) {
+			diff.Modified = append(diff.Modified, ColumnModification{From: oldColumn, To: column})
+		}
+	}
+
+	for i, column := range newColumns {
+		_, existed := oldByName[column.Name]
+		if _, renamed := renamedFrom[column.Name]; existed || renamed {
+			continue
+		}
+		after := ""
+		if i > 0 {
+			after = newColumns[i-1].Name
+		}
+		diff.Added = append(diff.Added, ColumnPosition{Column: column, After: after})
+		current = insertAfter(removeName(current, column.Name), column.Name, after)
+	}
+
+	// Existing columns whose position changed are moved after their new predecessor
+	for i, column := range newColumns {
+		if i < len(current) && current[i] == column.Name {
+			continue
+		}
+		after := ""
+		if i > 0 {
+			after = newColumns[i-1].Name
+		}
+		diff.Moved = append(diff.Moved, ColumnPosition{Column: column, After: after})
+		current = insertAfter(removeName(current, column.Name), column.Name, after)
+	}
+
+	return diff
+}
+
+func removeName(names []string, name string) []string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func insertAfter(names []string, name string, after string) []string {
+	out := make([]string, 0, len(names)+1)
+	if after == "" {
+		out = append(out, name)
+	}
+	for _, n := range names {
+		out = append(out, n)
+		if n == after {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+var identifierRegex = regexp.MustCompile("[A-Za-z_][A-Za-z0-9_]*")
+
+// keyColumns returns the identifiers used by sorting and partition keys and by the engine parameters.
+// ClickHouse forbids changing the type of any of them, renaming or dropping them, other properties can be modified.
+func (t *TableResource) keyColumns() map[string]bool {
+	keys := make(map[string]bool)
+	for _, orderBy := range t.OrderBy {
+		for _, identifier := range identifierRegex.FindAllString(orderBy, -1) {
+			keys[identifier] = true
+		}
+	}
+	for _, partitionBy := range t.PartitionBy {
+		keys[partitionBy.By] = true
+	}
+	partitionColumns, _ := common.ExpressionColumns(t.PartitionByExpression)
+	for _, column := range partitionColumns {
+		keys[column] = true
+	}
+	for _, column := range engineColumns(t.Engine, t.EngineParams) {
+		keys[column] = true
+	}
+	return keys
+}
+
+// RequiresReplacement tells if the column changes can't be applied with ALTER TABLE statements
+func (t *TableResource) RequiresReplacement(diff ColumnsDiff) bool {
+	keys := t.keyColumns()
+	for _, rename := range diff.Renamed {
+		if keys[rename.From] {
+			return true
+		}
+	}
+	for _, column := range diff.Dropped {
+		if keys[column.Name] {
+			return true
+		}
+	}
+	for _, modification := range diff.Modified {
+		if keys[modification.From.Name] && !DataTypesEquivalent(modification.From.Type, modification.To.Type) {
+			return true
+		}
+	}
+	// Ephemeral columns are not stored, so they can't be turned into regular ones or the other way around
+	for _, modification := range diff.Modified {
+		fromEphemeral := modification.From.GetDefaultKind() == "EPHEMERAL"
+		toEphemeral := modification.To.GetDefaultKind() == "EPHEMERAL"
+		if fromEphemeral != toEphemeral {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package resourcetable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testColumns(nameTypes ...string) []ColumnResource {
+	var columns []ColumnResource
+	for i := 0; i < len(nameTypes); i += 2 {
+		columns = append(columns, ColumnResource{Name: nameTypes[i], Type: nameTypes[i+1]})
+	}
+	return columns
+}
+
+func TestDiffColumns(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldColumns    []ColumnResource
+		newColumns    []ColumnResource
+		detectRenames bool
+		want          ColumnsDiff
+	}{
+		{
+			name:       "no changes",
+			oldColumns: testColumns("key", "Int64", "value", "String"),
+			newColumns: testColumns("key", "Int64", "value", "String"),
+			want:       ColumnsDiff{},
+		},
+		{
+			name:       "add column at the end",
+			oldColumns: testColumns("key", "Int64"),
+			newColumns: testColumns("key", "Int64", "value", "Nullable(String)"),
+			want: ColumnsDiff{
+				Added: []ColumnPosition{{Column: ColumnResource{Name: "value", Type: "Nullable(String)"}, After: "key"}},
+			},
+		},
+		{
+			name:       "add columns first and in the middle",
+			oldColumns: testColumns("key", "Int64", "value", "String"),
+			newColumns: testColumns("id", "UUID", "key", "Int64", "ts", "DateTime", "value", "String"),
+			want: ColumnsDiff{
+				Added: []ColumnPosition{
+					{Column: ColumnResource{Name: "id", Type: "UUID"}, After: ""},
+					{Column: ColumnResource{Name: "ts", Type: "DateTime"}, After: "key"},
+				},
+			},
+		},
+		{
+			name:       "drop column",
+			oldColumns: testColumns("key", "Int64", "value", "String", "ts", "DateTime"),
+			newColumns: testColumns("key", "Int64", "ts", "DateTime"),
+			want: ColumnsDiff{
+				Dropped: testColumns("value", "String"),
+			},
+		},
+		{
+			name:       "modify column type",
+			oldColumns: testColumns("key", "Int64", "value", "String"),
+			newColumns: testColumns("key", "Int64", "value", "Nullable(String)"),
+			want: ColumnsDiff{
+				Modified: []ColumnModification{{
+					From: ColumnResource{Name: "value", Type: "String"},
+					To:   ColumnResource{Name: "value", Type: "Nullable(String)"},
+				}},
+			},
+		},
+		{
+			name:       "implicit default kind",
+			oldColumns: []ColumnResource{{Name: "ts", Type: "DateTime", DefaultKind: "DEFAULT", DefaultExpression: "now()"}},
+			newColumns: []ColumnResource{{Name: "ts", Type: "DateTime", DefaultExpression: "now()"}},
+			want:       ColumnsDiff{},
+		},
+		{
+			name:          "rename column",
+			oldColumns:    testColumns("key", "Int64", "value", "String"),
+			newColumns:    testColumns("key", "Int64", "payload", "String"),
+			detectRenames: true,
+			want: ColumnsDiff{
+				Renamed: []ColumnRename{{From: "value", To: "payload"}},
+			},
+		},
+		{
+			name:       "replace column with the same definition without detecting renames",
+			oldColumns: testColumns("key", "Int64", "value", "String"),
+			newColumns: testColumns("key", "Int64", "payload", "String"),
+			want: ColumnsDiff{
+				Dropped: testColumns("value", "String"),
+				Added:   []ColumnPosition{{Column: ColumnResource{Name: "payload", Type: "String"}, After: "key"}},
+			},
+		},
+		{
+			name:       "replace column with a different type",
+			oldColumns: testColumns("key", "Int64", "value", "String"),
+			newColumns: testColumns("key", "Int64", "amount", "Decimal(10, 2)"),
+			want: ColumnsDiff{
+				Dropped: testColumns("value", "String"),
+				Added:   []ColumnPosition{{Column: ColumnResource{Name: "amount", Type: "Decimal(10, 2)"}, After: "key"}},
+			},
+		},
+		{
+			name:       "move column",
+			oldColumns: testColumns("key", "Int64", "value", "String", "ts", "DateTime"),
+			newColumns: testColumns("key", "Int64", "ts", "DateTime", "value", "String"),
+			want: ColumnsDiff{
+				Moved: []ColumnPosition{{Column: ColumnResource{Name: "ts", Type: "DateTime"}, After: "key"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffColumns(tt.oldColumns, tt.newColumns, tt.detectRenames)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffColumns() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableResourceRequiresReplacement(t *testing.T) {
+	tableResource := TableResource{
+		OrderBy:     []string{"key", "cityHash64(value)"},
+		PartitionBy: []PartitionByResource{{By: "ts", PartitionFunction: "toYYYYMM"}},
+	}
+	oldColumns := testColumns("key", "Int64", "value", "String", "ts", "DateTime", "extra", "String")
+
+	tests := []struct {
+		name       string
+		newColumns []ColumnResource
+		want       bool
+	}{
+		{"add column", testColumns("key", "Int64", "value", "String", "ts", "DateTime", "extra", "String", "other", "UInt8"), false},
+		{"modify non key column", testColumns("key", "Int64", "value", "String", "ts", "DateTime", "extra", "Nullable(String)"), false},
+		{"drop non key column", testColumns("key", "Int64", "value", "String", "ts", "DateTime"), false},
+		{"modify order by column", testColumns("key", "UInt64", "value", "String", "ts", "DateTime", "extra", "String"), true},
+		{"modify column used in order by expression", testColumns("key", "Int64", "value", "Nullable(String)", "ts", "DateTime", "extra", "String"), true},
+		{"comment order by column", []ColumnResource{{Name: "key", Type: "Int64", Comment: "event key"}, {Name: "value", Type: "String"}, {Name: "ts", Type: "DateTime"}, {Name: "extra", Type: "String"}}, false},
+		{"codec and default of partition column", []ColumnResource{{Name: "key", Type: "Int64"}, {Name: "value", Type: "String"}, {Name: "ts", Type: "DateTime", DefaultExpression: "now()", Codec: "Delta, ZSTD"}, {Name: "extra", Type: "String"}}, false},
+		{"rename partition column", testColumns("key", "Int64", "value", "String", "event_time", "DateTime", "extra", "String"), true},
+		{"drop order by column", testColumns("value", "String", "ts", "DateTime", "extra", "String"), true},
+		{"make column ephemeral", append(testColumns("key", "Int64", "value", "String", "ts", "DateTime"), ColumnResource{Name: "extra", Type: "String", DefaultKind: "EPHEMERAL"}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffColumns(oldColumns, tt.newColumns, true)
+			if got := tableResource.RequiresReplacement(diff); got != tt.want {
+				t.Errorf("RequiresReplacement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableResourceRequiresReplacement_PartitionByExpression(t *testing.T) {
+	tableResource := TableResource{OrderBy: []string{"key"}, PartitionByExpression: "(toMonday(ts), intDiv(id, 1000000))"}
+	oldColumns := testColumns("key", "Int64", "id", "UInt64", "ts", "DateTime", "extra", "String")
+
+	if diff := DiffColumns(oldColumns, testColumns("key", "Int64", "id", "Int64", "ts", "DateTime", "extra", "String"), false); !tableResource.RequiresReplacement(diff) {
+		t.Errorf("RequiresReplacement() = false, want true when a partition expression column is modified")
+	}
+	if diff := DiffColumns(oldColumns, testColumns("key", "Int64", "id", "UInt64", "ts", "DateTime", "extra", "LowCardinality(String)"), false); tableResource.RequiresReplacement(diff) {
+		t.Errorf("RequiresReplacement() = true, want false when a non key column is modified")
+	}
+}
+
+func TestBuildAlterColumnsSentences(t *testing.T) {
+	tableResource := TableResource{Database: "dm", Name: "events", Cluster: "bi_cluster"}
+	diff := DiffColumns(
+		testColumns("key", "Int64", "value", "String", "old_name", "UInt8", "ts", "DateTime"),
+		testColumns("id", "UUID", "key", "Int64", "new_name", "UInt8", "ts", "DateTime64(3)"),
+		true,
+	)
+
+	want := []string{
+		"ALTER TABLE `dm`.`events` ON CLUSTER `bi_cluster` RENAME COLUMN `old_name` TO `new_name`",
+		"ALTER TABLE `dm`.`events` ON CLUSTER `bi_cluster` DROP COLUMN `value`",
+		"ALTER TABLE `dm`.`events` ON CLUSTER `bi_cluster` MODIFY COLUMN `ts` DateTime64(3)",
+		"ALTER TABLE `dm`.`events` ON CLUSTER `bi_cluster` ADD COLUMN `id` UUID FIRST",
+	}
+	if got := buildAlterColumnsSentences(tableResource, diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAlterColumnsSentences() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildAlterColumnsSentences_KeyColumnComment(t *testing.T) {
+	tableResource := TableResource{Database: "dm", Name: "events", OrderBy: []string{"key"}}
+	diff := DiffColumns(
+		testColumns("key", "Int64", "value", "String"),
+		[]ColumnResource{{Name: "key", Type: "Int64", Comment: "event key"}, {Name: "value", Type: "String"}},
+		false,
+	)
+	if tableResource.RequiresReplacement(diff) {
+		t.Fatalf("RequiresReplacement() = true, want false when a sorting key column only changes its comment")
+	}
+
+	want := []string{"ALTER TABLE `dm`.`events` MODIFY COLUMN `key` COMMENT 'event key'"}
+	if got := buildAlterColumnsSentences(tableResource, diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAlterColumnsSentences() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildColumnSentence(t *testing.T) {
+	tests := []struct {
+		column ColumnResource
+		want   string
+	}{
+		{ColumnResource{Name: "key", Type: "Int64"}, "`key` Int64"},
+		{ColumnResource{Name: "ts", Type: "DateTime", DefaultExpression: "now()"}, "`ts` DateTime DEFAULT now()"},
+		{ColumnResource{Name: "day", Type: "Date", DefaultKind: "materialized", DefaultExpression: "toDate(ts)"}, "`day` Date MATERIALIZED toDate(ts)"},
+		{ColumnResource{Name: "raw", Type: "String", DefaultKind: "EPHEMERAL"}, "`raw` String EPHEMERAL"},
+		{
+			ColumnResource{Name: "value", Type: "String", Comment: "user's value", Codec: "ZSTD(1)", TTL: "ts + INTERVAL 1 MONTH"},
+			"`value` String COMMENT 'user\\'s value' CODEC(ZSTD(1)) TTL ts + INTERVAL 1 MONTH",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := buildColumnSentence(tt.column); got != tt.want {
+			t.Errorf("buildColumnSentence() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestBuildAlterColumnsSentences_RemoveProperties(t *testing.T) {
+	tableResource := TableResource{Database: "dm", Name: "events"}
+	diff := DiffColumns(
+		[]ColumnResource{{Name: "value", Type: "String", DefaultExpression: "''", Codec: "ZSTD(1)", Comment: "value"}},
+		[]ColumnResource{{Name: "value", Type: "String", Comment: "value"}},
+		false,
+	)
+
+	want := []string{
+		"ALTER TABLE `dm`.`events` MODIFY COLUMN `value` REMOVE DEFAULT",
+		"ALTER TABLE `dm`.`events` MODIFY COLUMN `value` REMOVE CODEC",
+		"ALTER TABLE `dm`.`events` MODIFY COLUMN `value` COMMENT 'value'",
+	}
+	if got := buildAlterColumnsSentences(tableResource, diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAlterColumnsSentences() = %q, want %q", got, want)
+	}
+}
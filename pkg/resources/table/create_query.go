@@ -0,0 +1,170 @@
+package resourcetable
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+// scanTopLevel calls f for every position of s that is outside of quotes and parentheses,
+// top level parentheses themselves included. Scanning stops when f returns false.
+func scanTopLevel(s string, f func(i int) bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		char := s[i]
+		topLevel := depth == 0
+		switch {
+		case quote != 0:
+			if char == '\\' {
+				i++
+			} else if char == quote {
+				quote = 0
+			}
+			continue
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+			continue
+		case char == '(' || char == '[':
+			depth++
+		case char == ')' || char == ']':
+			depth--
+			topLevel = depth == 0
+		}
+		if topLevel && !f(i) {
+			return
+		}
+	}
+}
+
+// indexTopLevelKeyword returns the position of the first top level occurrence of keyword in s, or -1
+func indexTopLevelKeyword(s string, keyword string) int {
+	index := -1
+	scanTopLevel(s, func(i int) bool {
+		end := i + len(keyword)
+		if end <= len(s) && strings.EqualFold(s[i:end], keyword) && isWordBoundary(s, i-1) && isWordBoundary(s, end) {
+			index = i
+			return false
+		}
+		return true
+	})
+	return index
+}
+
+func isWordBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	char := s[i]
+	return !(char == '_' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9')
+}
+
+var ttlActions = []string{"DELETE", "TO DISK", "TO VOLUME"}
+
+// parseTTL parses the table TTL clause into its rules. GROUP BY rules take every following item,
+// as their keys and SET assignments are comma separated too.
+func parseTTL(ttl string) []TTLResource {
+	var rules []TTLResource
+	var groupByRule *TTLResource
+	inSet := false
+	for _, item := range common.SplitTopLevel(ttl) {
+		if groupByRule != nil {
+			if setIndex := indexTopLevelKeyword(item, "SET"); !inSet && setIndex != -1 {
+				inSet = true
+				if groupBy := strings.TrimSpace(item[:setIndex]); groupBy != "" {
+					groupByRule.GroupBy = append(groupByRule.GroupBy, groupBy)
+				}
+				item = strings.TrimSpace(item[setIndex+len("SET"):])
+			}
+			if inSet {
+				keyValue := strings.SplitN(item, "=", 2)
+				if len(keyValue) == 2 {
+					groupByRule.Set[strings.TrimSpace(keyValue[0])] = strings.TrimSpace(keyValue[1])
+				}
+			} else {
+				groupByRule.GroupBy = append(groupByRule.GroupBy, item)
+			}
+			continue
+		}
+
+		rule := TTLResource{Action: "DELETE", Set: map[string]string{}}
+		if index := indexTopLevelKeyword(item, "GROUP BY"); index != -1 {
+			rule.Action = "GROUP BY"
+			groupBy := strings.TrimSpace(item[index+len("GROUP BY"):])
+			item = strings.TrimSpace(item[:index])
+			if setIndex := indexTopLevelKeyword(groupBy, "SET"); setIndex != -1 {
+				inSet = true
+				keyValue := strings.SplitN(strings.TrimSpace(groupBy[setIndex+len("SET"):]), "=", 2)
+				if len(keyValue) == 2 {
+					rule.Set[strings.TrimSpace(keyValue[0])] = strings.TrimSpace(keyValue[1])
+				}
+				groupBy = strings.TrimSpace(groupBy[:setIndex])
+			}
+			rule.GroupBy = []string{groupBy}
+		}
+		if index := indexTopLevelKeyword(item, "WHERE"); index != -1 {
+			rule.Where = strings.TrimSpace(item[index+len("WHERE"):])
+			item = strings.TrimSpace(item[:index])
+		}
+		for _, action := range ttlActions {
+			if index := indexTopLevelKeyword(item, action); index != -1 {
+				rule.Action = action
+				rule.Target = common.UnquoteString(strings.TrimSpace(item[index+len(action):]))
+				item = strings.TrimSpace(item[:index])
+				break
+			}
+		}
+		rule.Expression = item
+		rules = append(rules, rule)
+		if rule.Action == "GROUP BY" {
+			groupByRule = &rules[len(rules)-1]
+		}
+	}
+	return rules
+}
+
+var intervalRegex = regexp.MustCompile(`(?i)\bINTERVAL\s+(\d+)\s+(SECOND|MINUTE|HOUR|DAY|WEEK|MONTH|QUARTER|YEAR)S?\b`)
+var spacesRegex = regexp.MustCompile(`\s+`)
+
+// NormalizeExpression rewrites an expression the way ClickHouse stores it in create queries,
+// so that both can be compared: INTERVAL literals are turned into toInterval functions and spaces are collapsed.
+func NormalizeExpression(expression string) string {
+	expression = intervalRegex.ReplaceAllStringFunc(expression, func(interval string) string {
+		parts := intervalRegex.FindStringSubmatch(interval)
+		unit := strings.ToUpper(parts[2][:1]) + strings.ToLower(parts[2][1:])
+		return "toInterval" + unit + "(" + parts[1] + ")"
+	})
+	return strings.TrimSpace(spacesRegex.ReplaceAllString(expression, " "))
+}
+
+// ExpressionsEquivalent compares expressions after normalizing them, so spacing and keyword case are ignored
+func ExpressionsEquivalent(a, b string) bool {
+	return common.NormalizeQuery(NormalizeExpression(a)) == common.NormalizeQuery(NormalizeExpression(b))
+}
+
+// CodecsEquivalent compares codec lists like "Delta, ZSTD" and the form ClickHouse reports, "Delta(8), ZSTD(1)".
+// ClickHouse fills in the default parameters of a codec, so a codec given without parameters matches any.
+func CodecsEquivalent(a, b string) bool {
+	aCodecs, bCodecs := common.SplitTopLevel(a), common.SplitTopLevel(b)
+	if len(aCodecs) != len(bCodecs) {
+		return false
+	}
+	for i := range aCodecs {
+		aName, aParams := splitCodec(aCodecs[i])
+		bName, bParams := splitCodec(bCodecs[i])
+		if aName != bName || aParams != "" && bParams != "" && aParams != bParams {
+			return false
+		}
+	}
+	return true
+}
+
+func splitCodec(codec string) (string, string) {
+	codec = strings.TrimSpace(codec)
+	index := strings.Index(codec, "(")
+	if index == -1 {
+		return codec, ""
+	}
+	return strings.TrimSpace(codec[:index]), strings.Join(strings.Fields(codec[index:]), "")
+}
@@ -0,0 +1,194 @@
+package resourcetable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCHTableToResource_CreateQuery(t *testing.T) {
+	chTable := CHTable{
+		Database: "dm",
+		Name:     "events",
+		Engine:   "MergeTree",
+		CreateTableQuery: "CREATE TABLE dm.events (`key` Int64, `ts` DateTime, `region` String, " +
+			"`value` String TTL ts + toIntervalDay(7) COMMENT 'order by value') " +
+			"ENGINE = MergeTree PARTITION BY (toYYYYMM(ts), region) PRIMARY KEY key ORDER BY (key, cityHash64(value, ts)) SAMPLE BY key " +
+			"TTL ts + toIntervalMonth(1) TO VOLUME 'cold', ts + toIntervalYear(1) WHERE value = 'ttl' " +
+			"SETTINGS index_granularity = 8192, storage_policy = 'tiered' COMMENT 'events table'",
+		PrimaryKey:  "key",
+		SamplingKey: "key",
+		Columns: []CHColumn{
+			{Name: "key", Type: "Int64"},
+			{Name: "ts", Type: "DateTime"},
+			{Name: "region", Type: "String"},
+			{Name: "value", Type: "String", Comment: "order by value"},
+		},
+	}
+	tableResource, diags := chTable.ToResource()
+	if len(diags) > 0 {
+		t.Fatalf("ToResource() unexpected diagnostics: %v", diags)
+	}
+
+	if want := []string{"key", "cityHash64(value, ts)"}; !reflect.DeepEqual(tableResource.OrderBy, want) {
+		t.Errorf("ToResource() order by = %q, want %q", tableResource.OrderBy, want)
+	}
+	wantPartitionBy := []PartitionByResource{{By: "ts", PartitionFunction: "toYYYYMM"}, {By: "region"}}
+	if !reflect.DeepEqual(tableResource.PartitionBy, wantPartitionBy) {
+		t.Errorf("ToResource() partition by = %+v, want %+v", tableResource.PartitionBy, wantPartitionBy)
+	}
+	if want := map[string]string{"index_granularity": "8192", "storage_policy": "tiered"}; !reflect.DeepEqual(tableResource.Settings, want) {
+		t.Errorf("ToResource() settings = %v, want %v", tableResource.Settings, want)
+	}
+	if len(tableResource.TTL) != 2 || tableResource.TTL[0].Target != "cold" || tableResource.TTL[1].Where != "value = 'ttl'" {
+		t.Errorf("ToResource() ttl = %+v", tableResource.TTL)
+	}
+	if ttl := tableResource.Columns[3].(map[string]interface{})["ttl"]; ttl != "ts + toIntervalDay(7)" {
+		t.Errorf("ToResource() column ttl = %q, want %q", ttl, "ts + toIntervalDay(7)")
+	}
+	if tableResource.PrimaryKey[0] != "key" || tableResource.SampleBy != "key" {
+		t.Errorf("ToResource() primary key = %q, sample by = %q", tableResource.PrimaryKey, tableResource.SampleBy)
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  string
+		want []TTLResource
+	}{
+		{
+			name: "delete and move",
+			ttl:  "ts + toIntervalMonth(1) TO VOLUME 'cold', ts + toIntervalYear(1) WHERE value = 'ttl'",
+			want: []TTLResource{
+				{Expression: "ts + toIntervalMonth(1)", Action: "TO VOLUME", Target: "cold", Set: map[string]string{}},
+				{Expression: "ts + toIntervalYear(1)", Action: "DELETE", Where: "value = 'ttl'", Set: map[string]string{}},
+			},
+		},
+		{
+			name: "group by",
+			ttl:  "ts + toIntervalDay(7) GROUP BY key, toStartOfDay(ts) SET value = any(value), total = sum(total)",
+			want: []TTLResource{
+				{
+					Expression: "ts + toIntervalDay(7)",
+					Action:     "GROUP BY",
+					GroupBy:    []string{"key", "toStartOfDay(ts)"},
+					Set:        map[string]string{"value": "any(value)", "total": "sum(total)"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTTL(tt.ttl); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTTL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"ts + INTERVAL 1 MONTH", "ts + toIntervalMonth(1)"},
+		{"ts  +   interval 3 days", "ts + toIntervalDay(3)"},
+		{"ts + toIntervalMonth(1)", "ts + toIntervalMonth(1)"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeExpression(tt.expression); got != tt.want {
+			t.Errorf("NormalizeExpression(%q) = %q, want %q", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestCodecsEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Delta, ZSTD", "Delta(8), ZSTD(1)", true},
+		{"Delta(4), ZSTD(1)", "Delta(8), ZSTD(1)", false},
+		{"ZSTD( 3 )", "ZSTD(3)", true},
+		{"LZ4", "ZSTD(1)", false},
+		{"Delta", "Delta(8), ZSTD(1)", false},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := CodecsEquivalent(tt.a, tt.b); got != tt.want {
+			t.Errorf("CodecsEquivalent(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionsEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"now()+1", "now() + 1", true},
+		{"ts + INTERVAL 1 DAY", "ts + toIntervalDay(1)", true},
+		{"'a'", "'b'", false},
+	}
+
+	for _, tt := range tests {
+		if got := ExpressionsEquivalent(tt.a, tt.b); got != tt.want {
+			t.Errorf("ExpressionsEquivalent(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBuildTTLSentence(t *testing.T) {
+	ttl := []TTLResource{
+		{Expression: "ts + INTERVAL 1 MONTH", Action: "TO DISK", Target: "cold"},
+		{Expression: "ts + INTERVAL 1 YEAR", Action: "DELETE", Where: "value = ''"},
+		{Expression: "ts + INTERVAL 7 DAY", Action: "GROUP BY", GroupBy: []string{"key"}, Set: map[string]string{"value": "max(value)"}},
+	}
+	want := "ts + INTERVAL 1 MONTH TO DISK 'cold', ts + INTERVAL 1 YEAR WHERE value = '', ts + INTERVAL 7 DAY GROUP BY key SET value = max(value)"
+	if got := buildTTLSentence(ttl); got != want {
+		t.Errorf("buildTTLSentence() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildModifySettingsSentences(t *testing.T) {
+	tableResource := TableResource{
+		Database: "dm",
+		Name:     "events",
+		Settings: map[string]string{"ttl_only_drop_parts": "1", "storage_policy": "tiered", "merge_with_ttl_timeout": "3600"},
+	}
+	oldSettings := map[string]string{"ttl_only_drop_parts": "0", "storage_policy": "tiered", "min_bytes_for_wide_part": "0"}
+
+	want := []string{
+		"ALTER TABLE `dm`.`events` MODIFY SETTING merge_with_ttl_timeout = 3600, ttl_only_drop_parts = 1",
+		"ALTER TABLE `dm`.`events` RESET SETTING min_bytes_for_wide_part",
+	}
+	if got := buildModifySettingsSentences(tableResource, oldSettings); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildModifySettingsSentences() = %q, want %q", got, want)
+	}
+}
+
+func TestReadonlySettingsChanged(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldSettings map[string]string
+		newSettings map[string]string
+		want        bool
+	}{
+		{"modifiable setting", map[string]string{"ttl_only_drop_parts": "0"}, map[string]string{"ttl_only_drop_parts": "1"}, false},
+		{"changed", map[string]string{"index_granularity": "8192"}, map[string]string{"index_granularity": "4096"}, true},
+		{"added", map[string]string{}, map[string]string{"index_granularity_bytes": "0"}, true},
+		{"removed", map[string]string{"index_granularity": "4096"}, map[string]string{}, true},
+		{"unchanged", map[string]string{"index_granularity": "4096"}, map[string]string{"index_granularity": "4096", "storage_policy": "tiered"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readonlySettingsChanged(tt.oldSettings, tt.newSettings); got != tt.want {
+				t.Errorf("readonlySettingsChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,701 @@
+package resourcetable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DataType is a parsed ClickHouse data type, e.g. Map(String, Array(Nullable(UInt64)))
+type DataType struct {
+	Name   string
+	Params []DataTypeParam
+}
+
+// DataTypeParam is a data type parameter, only one of Type or Literal is set
+type DataTypeParam struct {
+	// Name is the element name of Tuple and Nested types or the setting name of Dynamic
+	Name string
+	// Type is a nested data type
+	Type *DataType
+	// Literal is a number, a quoted string or an aggregate function
+	Literal string
+	// Value is the number assigned to an Enum element
+	Value string
+}
+
+var simpleDataTypes = map[string]bool{
+	"UInt8": true, "UInt16": true, "UInt32": true, "UInt64": true, "UInt128": true, "UInt256": true,
+	"Int8": true, "Int16": true, "Int32": true, "Int64": true, "Int128": true, "Int256": true,
+	"Float32": true, "Float64": true, "BFloat16": true,
+	"Bool": true, "String": true, "UUID": true, "Date": true, "Date32": true,
+	"IPv4": true, "IPv6": true, "Nothing": true,
+	"Point": true, "Ring": true, "LineString": true, "MultiLineString": true, "Polygon": true, "MultiPolygon": true,
+	"IntervalNanosecond": true, "IntervalMicrosecond": true, "IntervalMillisecond": true, "IntervalSecond": true,
+	"IntervalMinute": true, "IntervalHour": true, "IntervalDay": true, "IntervalWeek": true,
+	"IntervalMonth": true, "IntervalQuarter": true, "IntervalYear": true,
+}
+
+// dataTypeAliases maps the case insensitive aliases to ClickHouse data types
+var dataTypeAliases = map[string]string{
+	"TINYINT": "Int8", "INT1": "Int8", "BYTE": "Int8",
+	"SMALLINT": "Int16", "INT2": "Int16",
+	"INT": "Int32", "INTEGER": "Int32", "INT4": "Int32", "MEDIUMINT": "Int32",
+	"BIGINT": "Int64", "INT8": "Int64",
+	"FLOAT": "Float32", "REAL": "Float32", "SINGLE": "Float32", "DOUBLE": "Float64",
+	"BOOL": "Bool", "BOOLEAN": "Bool", "TIMESTAMP": "DateTime",
+	"DEC": "Decimal", "NUMERIC": "Decimal", "FIXED": "Decimal",
+	"TEXT": "String", "TINYTEXT": "String", "MEDIUMTEXT": "String", "LONGTEXT": "String",
+	"VARCHAR": "String", "VARCHAR2": "String", "NVARCHAR": "String", "CHAR": "String", "NCHAR": "String",
+	"CHARACTER": "String", "BLOB": "String", "TINYBLOB": "String", "MEDIUMBLOB": "String", "LONGBLOB": "String",
+	"CLOB": "String", "VARBINARY": "String", "BYTEA": "String",
+	"BINARY": "FixedString", "INET4": "IPv4", "INET6": "IPv6", "ENUM": "Enum",
+}
+
+var parametricDataTypes = map[string]bool{
+	"Nullable": true, "LowCardinality": true, "Array": true, "Map": true, "Tuple": true, "Nested": true,
+	"Variant": true, "Dynamic": true, "FixedString": true, "Decimal": true, "Decimal32": true, "Decimal64": true,
+	"Decimal128": true, "Decimal256": true, "DateTime": true, "DateTime64": true, "Enum": true, "Enum8": true,
+	"Enum16": true, "AggregateFunction": true, "SimpleAggregateFunction": true, "JSON": true, "Object": true,
+}
+
+// lowCardinalityDataTypes are the types LowCardinality can wrap, besides Nullable ones
+var lowCardinalityDataTypes = map[string]bool{
+	"String": true, "FixedString": true, "Date": true, "Date32": true, "DateTime": true,
+	"UInt8": true, "UInt16": true, "UInt32": true, "UInt64": true, "UInt128": true, "UInt256": true,
+	"Int8": true, "Int16": true, "Int32": true, "Int64": true, "Int128": true, "Int256": true,
+	"Float32": true, "Float64": true, "IPv4": true, "IPv6": true, "UUID": true,
+}
+
+// nonNullableDataTypes can't be wrapped by Nullable
+var nonNullableDataTypes = map[string]bool{
+	"Array": true, "Map": true, "Tuple": true, "Nested": true, "Nullable": true, "LowCardinality": true,
+	"AggregateFunction": true, "SimpleAggregateFunction": true, "Variant": true, "Dynamic": true,
+	"JSON": true, "Object": true, "Point": true, "Ring": true, "LineString": true, "MultiLineString": true,
+	"Polygon": true, "MultiPolygon": true,
+}
+
+// String renders the data type the way ClickHouse does
+func (t *DataType) String() string {
+	if len(t.Params) == 0 {
+		return t.Name
+	}
+	params := make([]string, 0, len(t.Params))
+	for _, param := range t.Params {
+		params = append(params, param.String())
+	}
+	return t.Name + "(" + strings.Join(params, ", ") + ")"
+}
+
+func (p DataTypeParam) String() string {
+	switch {
+	case p.Type != nil && p.Name != "":
+		return p.Name + " " + p.Type.String()
+	case p.Type != nil:
+		return p.Type.String()
+	case p.Value != "":
+		return p.Literal + " = " + p.Value
+	case p.Name != "":
+		return p.Name + " = " + p.Literal
+	default:
+		return p.Literal
+	}
+}
+
+type dataTypeTokenKind int
+
+const (
+	tokenEOF dataTypeTokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenString
+	tokenPunctuation
+)
+
+type dataTypeToken struct {
+	Kind  dataTypeTokenKind
+	Text  string
+	Start int
+}
+
+func tokenizeDataType(s string) ([]dataTypeToken, error) {
+	var tokens []dataTypeToken
+	for i := 0; i < len(s); {
+		char := s[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case char == '(' || char == ')' || char == ',' || char == '=':
+			tokens = append(tokens, dataTypeToken{Kind: tokenPunctuation, Text: string(char), Start: i})
+			i++
+		case char == '\'' || char == '`' || char == '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != char; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated quoted string at position %d", i)
+			}
+			kind := tokenString
+			text := s[i : end+1]
+			if char != '\'' {
+				// Quoted identifiers, e.g. Tuple element names
+				kind = tokenIdentifier
+				text = s[i+1 : end]
+			}
+			tokens = append(tokens, dataTypeToken{Kind: kind, Text: text, Start: i})
+			i = end + 1
+		case char == '-' || char >= '0' && char <= '9':
+			end := i + 1
+			for ; end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || s[end] == 'e' || s[end] == 'E'); end++ {
+			}
+			tokens = append(tokens, dataTypeToken{Kind: tokenNumber, Text: s[i:end], Start: i})
+			i = end
+		case isIdentifierChar(char):
+			end := i + 1
+			for ; end < len(s) && (isIdentifierChar(s[end]) || s[end] >= '0' && s[end] <= '9' || s[end] == '.'); end++ {
+			}
+			tokens = append(tokens, dataTypeToken{Kind: tokenIdentifier, Text: s[i:end], Start: i})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", char, i)
+		}
+	}
+	return append(tokens, dataTypeToken{Kind: tokenEOF, Start: len(s)}), nil
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z'
+}
+
+type dataTypeParser struct {
+	input  string
+	tokens []dataTypeToken
+	pos    int
+}
+
+func (p *dataTypeParser) peek() dataTypeToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dataTypeParser) next() dataTypeToken {
+	token := p.tokens[p.pos]
+	if token.Kind != tokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *dataTypeParser) isPunctuation(text string) bool {
+	token := p.peek()
+	return token.Kind == tokenPunctuation && token.Text == text
+}
+
+func (p *dataTypeParser) expectPunctuation(text string) error {
+	token := p.next()
+	if token.Kind != tokenPunctuation || token.Text != text {
+		return p.unexpected(token, fmt.Sprintf("%q", text))
+	}
+	return nil
+}
+
+func (p *dataTypeParser) unexpected(token dataTypeToken, expected string) error {
+	if token.Kind == tokenEOF {
+		return fmt.Errorf("unexpected end of type, expected %s", expected)
+	}
+	return fmt.Errorf("unexpected %q at position %d, expected %s", token.Text, token.Start, expected)
+}
+
+// ParseDataType parses and validates a ClickHouse data type
+func ParseDataType(s string) (*DataType, error) {
+	tokens, err := tokenizeDataType(s)
+	if err != nil {
+		return nil, err
+	}
+	parser := dataTypeParser{input: s, tokens: tokens}
+	dataType, err := parser.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.Kind != tokenEOF {
+		return nil, parser.unexpected(token, "end of type")
+	}
+	if err := validateDataType(dataType, true); err != nil {
+		return nil, err
+	}
+	return dataType, nil
+}
+
+func (p *dataTypeParser) parseType() (*DataType, error) {
+	token := p.next()
+	if token.Kind != tokenIdentifier {
+		return nil, p.unexpected(token, "a type name")
+	}
+	dataType := &DataType{Name: token.Text}
+	// Aliases are case insensitive, so they must not shadow type names like Int8
+	alias, isAlias := dataTypeAliases[strings.ToUpper(token.Text)]
+	isAlias = isAlias && !simpleDataTypes[token.Text] && !parametricDataTypes[token.Text]
+	if isAlias {
+		dataType.Name = alias
+	}
+	if !p.isPunctuation("(") {
+		return dataType, nil
+	}
+	p.next()
+	if p.isPunctuation(")") {
+		p.next()
+		return dataType, nil
+	}
+
+	// JSON and Object parameters have their own grammar (paths, SKIP clauses...), they are kept as is
+	if dataType.Name == "JSON" || dataType.Name == "Object" {
+		start := p.peek().Start
+		depth := 0
+		for {
+			token := p.next()
+			switch {
+			case token.Kind == tokenEOF:
+				return nil, p.unexpected(token, `")"`)
+			case token.Kind == tokenPunctuation && token.Text == "(":
+				depth++
+			case token.Kind == tokenPunctuation && token.Text == ")" && depth > 0:
+				depth--
+			case token.Kind == tokenPunctuation && token.Text == ")":
+				dataType.Params = []DataTypeParam{{Literal: strings.TrimSpace(p.input[start:token.Start])}}
+				return dataType, nil
+			}
+		}
+	}
+
+	for {
+		param, err := p.parseParam(dataType.Name, len(dataType.Params))
+		if err != nil {
+			return nil, err
+		}
+		dataType.Params = append(dataType.Params, param)
+		if p.isPunctuation(")") {
+			p.next()
+			// Lengths of string aliases, like VARCHAR(255), are ignored by ClickHouse
+			if isAlias && dataType.Name == "String" {
+				dataType.Params = nil
+			}
+			return dataType, nil
+		}
+		if err := p.expectPunctuation(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *dataTypeParser) parseParam(typeName string, index int) (DataTypeParam, error) {
+	switch {
+	case typeName == "Tuple" || typeName == "Nested":
+		// Named elements are an identifier followed by a type
+		if token := p.peek(); token.Kind == tokenIdentifier && p.tokens[p.pos+1].Kind == tokenIdentifier {
+			p.next()
+			dataType, err := p.parseType()
+			return DataTypeParam{Name: token.Text, Type: dataType}, err
+		}
+	case strings.HasPrefix(typeName, "Enum"):
+		token := p.next()
+		if token.Kind != tokenString {
+			return DataTypeParam{}, p.unexpected(token, "an enum element name")
+		}
+		param := DataTypeParam{Literal: token.Text}
+		if p.isPunctuation("=") {
+			p.next()
+			value := p.next()
+			if value.Kind != tokenNumber {
+				return DataTypeParam{}, p.unexpected(value, "an enum element value")
+			}
+			param.Value = value.Text
+		}
+		return param, nil
+	case (typeName == "AggregateFunction" || typeName == "SimpleAggregateFunction") && index == 0:
+		return p.parseAggregateFunction()
+	case typeName == "Dynamic":
+		name := p.next()
+		if name.Kind != tokenIdentifier {
+			return DataTypeParam{}, p.unexpected(name, "a setting name")
+		}
+		if err := p.expectPunctuation("="); err != nil {
+			return DataTypeParam{}, err
+		}
+		value := p.next()
+		if value.Kind != tokenNumber {
+			return DataTypeParam{}, p.unexpected(value, "a number")
+		}
+		return DataTypeParam{Name: name.Text, Literal: value.Text}, nil
+	}
+
+	if token := p.peek(); token.Kind == tokenNumber || token.Kind == tokenString {
+		p.next()
+		return DataTypeParam{Literal: token.Text}, nil
+	}
+	dataType, err := p.parseType()
+	return DataTypeParam{Type: dataType}, err
+}
+
+// parseAggregateFunction parses the function of aggregate function types, along with its parameters and combinators,
+// e.g. quantilesIf(0.5, 0.9)
+func (p *dataTypeParser) parseAggregateFunction() (DataTypeParam, error) {
+	name := p.next()
+	if name.Kind != tokenIdentifier {
+		return DataTypeParam{}, p.unexpected(name, "an aggregate function")
+	}
+	function := name.Text
+	if !p.isPunctuation("(") {
+		return DataTypeParam{Literal: function}, nil
+	}
+	p.next()
+	var params []string
+	for !p.isPunctuation(")") {
+		token := p.next()
+		if token.Kind != tokenNumber && token.Kind != tokenString {
+			return DataTypeParam{}, p.unexpected(token, "an aggregate function parameter")
+		}
+		params = append(params, token.Text)
+		if !p.isPunctuation(")") {
+			if err := p.expectPunctuation(","); err != nil {
+				return DataTypeParam{}, err
+			}
+		}
+	}
+	p.next()
+	return DataTypeParam{Literal: function + "(" + strings.Join(params, ", ") + ")"}, nil
+}
+
+func (t *DataType) typeParams() []*DataType {
+	var types []*DataType
+	for _, param := range t.Params {
+		if param.Type != nil {
+			types = append(types, param.Type)
+		}
+	}
+	return types
+}
+
+func (t *DataType) expectParams(min int, max int) error {
+	if len(t.Params) < min || max >= 0 && len(t.Params) > max {
+		switch {
+		case min == max:
+			return fmt.Errorf("%s expects %d parameters, got %d", t.Name, min, len(t.Params))
+		case max < 0:
+			return fmt.Errorf("%s expects at least %d parameters, got %d", t.Name, min, len(t.Params))
+		default:
+			return fmt.Errorf("%s expects between %d and %d parameters, got %d", t.Name, min, max, len(t.Params))
+		}
+	}
+	return nil
+}
+
+func (t *DataType) expectTypeParams() error {
+	for _, param := range t.Params {
+		if param.Type == nil {
+			return fmt.Errorf("%s expects data types as parameters, got %s", t.Name, param.Literal)
+		}
+	}
+	return nil
+}
+
+func (t *DataType) integerParam(index int, name string, min int64, max int64) error {
+	param := t.Params[index]
+	value, err := strconv.ParseInt(param.Literal, 10, 64)
+	if param.Type != nil || err != nil {
+		return fmt.Errorf("%s %s must be an integer, got %s", t.Name, name, param.String())
+	}
+	if value < min || value > max {
+		return fmt.Errorf("%s %s must be between %d and %d, got %d", t.Name, name, min, max, value)
+	}
+	return nil
+}
+
+func (t *DataType) stringParam(index int, name string) error {
+	if param := t.Params[index]; param.Type != nil || !isStringLiteral(param.Literal) {
+		return fmt.Errorf("%s %s must be a string literal, got %s", t.Name, name, param.String())
+	}
+	return nil
+}
+
+// validateDataType checks the parameters of the type and the nesting rules, Nested is only allowed at top level
+func validateDataType(t *DataType, topLevel bool) error {
+	if err := validateDataTypeParams(t, topLevel); err != nil {
+		return err
+	}
+	for _, param := range t.typeParams() {
+		if err := validateDataType(param, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDataTypeParams(t *DataType, topLevel bool) error {
+	if simpleDataTypes[t.Name] {
+		return t.expectParams(0, 0)
+	}
+
+	switch t.Name {
+	case "Nullable":
+		if err := t.expectParams(1, 1); err != nil {
+			return err
+		}
+		if err := t.expectTypeParams(); err != nil {
+			return err
+		}
+		if inner := t.Params[0].Type; nonNullableDataTypes[inner.Name] {
+			return fmt.Errorf("nested type %s cannot be inside Nullable type", inner.Name)
+		}
+	case "LowCardinality":
+		if err := t.expectParams(1, 1); err != nil {
+			return err
+		}
+		if err := t.expectTypeParams(); err != nil {
+			return err
+		}
+		inner := t.Params[0].Type
+		if inner.Name == "Nullable" {
+			inner = inner.Params[0].Type
+		}
+		if !lowCardinalityDataTypes[inner.Name] {
+			return fmt.Errorf("LowCardinality is not supported for %s", t.Params[0].Type.String())
+		}
+	case "Array":
+		if err := t.expectParams(1, 1); err != nil {
+			return err
+		}
+		return t.expectTypeParams()
+	case "Map":
+		if err := t.expectParams(2, 2); err != nil {
+			return err
+		}
+		if err := t.expectTypeParams(); err != nil {
+			return err
+		}
+		switch key := t.Params[0].Type; key.Name {
+		case "Nullable", "Array", "Map", "Tuple", "Nested", "Float32", "Float64", "BFloat16", "Variant", "Dynamic", "JSON":
+			return fmt.Errorf("type %s is not allowed as a Map key", key.String())
+		}
+	case "Tuple":
+		if err := t.expectParams(1, -1); err != nil {
+			return err
+		}
+		if err := t.expectTypeParams(); err != nil {
+			return err
+		}
+		return validateElementNames(t, false)
+	case "Nested":
+		if !topLevel {
+			return fmt.Errorf("Nested type is only allowed at top level")
+		}
+		if err := t.expectParams(1, -1); err != nil {
+			return err
+		}
+		if err := t.expectTypeParams(); err != nil {
+			return err
+		}
+		return validateElementNames(t, true)
+	case "Variant":
+		if err := t.expectParams(1, -1); err != nil {
+			return err
+		}
+		if err := t.expectTypeParams(); err != nil {
+			return err
+		}
+		for _, variant := range t.typeParams() {
+			switch variant.Name {
+			case "Nullable", "LowCardinality", "Variant", "Dynamic":
+				return fmt.Errorf("type %s is not allowed inside Variant", variant.String())
+			}
+		}
+	case "Dynamic":
+		if err := t.expectParams(0, 1); err != nil {
+			return err
+		}
+		if len(t.Params) == 1 && t.Params[0].Name != "max_types" {
+			return fmt.Errorf("Dynamic only accepts max_types parameter, got %s", t.Params[0].String())
+		}
+	case "FixedString":
+		if err := t.expectParams(1, 1); err != nil {
+			return err
+		}
+		return t.integerParam(0, "length", 1, 1<<31-1)
+	case "Decimal":
+		if err := t.expectParams(0, 2); err != nil {
+			return err
+		}
+		if len(t.Params) == 0 {
+			return nil
+		}
+		if err := t.integerParam(0, "precision", 1, 76); err != nil {
+			return err
+		}
+		if len(t.Params) == 2 {
+			precision, _ := strconv.ParseInt(t.Params[0].Literal, 10, 64)
+			return t.integerParam(1, "scale", 0, precision)
+		}
+	case "Decimal32", "Decimal64", "Decimal128", "Decimal256":
+		if err := t.expectParams(1, 1); err != nil {
+			return err
+		}
+		maxPrecision := map[string]int64{"Decimal32": 9, "Decimal64": 18, "Decimal128": 38, "Decimal256": 76}[t.Name]
+		return t.integerParam(0, "scale", 0, maxPrecision)
+	case "DateTime":
+		if err := t.expectParams(0, 1); err != nil {
+			return err
+		}
+		if len(t.Params) == 1 {
+			return t.stringParam(0, "timezone")
+		}
+	case "DateTime64":
+		if err := t.expectParams(1, 2); err != nil {
+			return err
+		}
+		if err := t.integerParam(0, "precision", 0, 9); err != nil {
+			return err
+		}
+		if len(t.Params) == 2 {
+			return t.stringParam(1, "timezone")
+		}
+	case "Enum", "Enum8", "Enum16":
+		return validateEnum(t)
+	case "AggregateFunction":
+		if err := t.expectParams(1, -1); err != nil {
+			return err
+		}
+		t := DataType{Name: t.Name, Params: t.Params[1:]}
+		return t.expectTypeParams()
+	case "SimpleAggregateFunction":
+		if err := t.expectParams(2, 2); err != nil {
+			return err
+		}
+		t := DataType{Name: t.Name, Params: t.Params[1:]}
+		return t.expectTypeParams()
+	case "JSON", "Object":
+		return nil
+	default:
+		return fmt.Errorf("unknown data type %s", t.Name)
+	}
+	return nil
+}
+
+// validateElementNames checks elements are either all named or all unnamed and names are unique
+func validateElementNames(t *DataType, namesRequired bool) error {
+	names := make(map[string]bool)
+	for _, param := range t.Params {
+		if param.Name == "" {
+			continue
+		}
+		if names[param.Name] {
+			return fmt.Errorf("%s element %s is duplicated", t.Name, param.Name)
+		}
+		names[param.Name] = true
+	}
+	if namesRequired && len(names) != len(t.Params) {
+		return fmt.Errorf("%s elements must be named", t.Name)
+	}
+	if len(names) > 0 && len(names) != len(t.Params) {
+		return fmt.Errorf("%s elements must be all named or all unnamed", t.Name)
+	}
+	return nil
+}
+
+func validateEnum(t *DataType) error {
+	if err := t.expectParams(1, -1); err != nil {
+		return err
+	}
+	min, max := int64(-32768), int64(32767)
+	if t.Name == "Enum8" {
+		min, max = -128, 127
+	}
+	names := make(map[string]bool)
+	values := make(map[int64]bool)
+	for _, param := range t.Params {
+		if names[param.Literal] {
+			return fmt.Errorf("%s element %s is duplicated", t.Name, param.Literal)
+		}
+		names[param.Literal] = true
+		if param.Value == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(param.Value, 10, 64)
+		if err != nil || value < min || value > max {
+			return fmt.Errorf("%s element %s value must be an integer between %d and %d, got %s", t.Name, param.Literal, min, max, param.Value)
+		}
+		if values[value] {
+			return fmt.Errorf("%s value %d is duplicated", t.Name, value)
+		}
+		values[value] = true
+	}
+	return nil
+}
+
+// CanonicalDataType returns the data type as ClickHouse reports it in system.columns,
+// types that can't be parsed are returned as they are.
+func CanonicalDataType(s string) string {
+	dataType, err := ParseDataType(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	return dataType.String()
+}
+
+// DataTypesEquivalent tells if both data types are the same once canonicalized. DateTime and DateTime64 types
+// without timezone use the server one, so they are considered equivalent to the same types with any timezone.
+// Decimal types without precision or scale are equivalent to the Decimal(10, 0) reported by the server.
+func DataTypesEquivalent(a string, b string) bool {
+	if a == b {
+		return true
+	}
+	typeA, errA := ParseDataType(a)
+	typeB, errB := ParseDataType(b)
+	if errA != nil || errB != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return typeA.equivalent(typeB)
+}
+
+func (t *DataType) equivalent(other *DataType) bool {
+	if t.Name != other.Name {
+		return false
+	}
+	params, otherParams := t.Params, other.Params
+	if t.Name == "Decimal" {
+		params, otherParams = decimalParams(params), decimalParams(otherParams)
+	}
+	if t.Name == "DateTime" || t.Name == "DateTime64" {
+		precision := 0
+		if t.Name == "DateTime64" {
+			precision = 1
+		}
+		if len(params) == precision || len(otherParams) == precision {
+			params, otherParams = params[:precision], otherParams[:precision]
+		}
+	}
+	if len(params) != len(otherParams) {
+		return false
+	}
+	for i := range params {
+		if params[i].Name != otherParams[i].Name || params[i].Literal != otherParams[i].Literal || params[i].Value != otherParams[i].Value {
+			return false
+		}
+		if (params[i].Type == nil) != (otherParams[i].Type == nil) {
+			return false
+		}
+		if params[i].Type != nil && !params[i].Type.equivalent(otherParams[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// decimalParams completes the parameters of a Decimal type with the default precision 10 and scale 0
+func decimalParams(params []DataTypeParam) []DataTypeParam {
+	defaults := []DataTypeParam{{Literal: "10"}, {Literal: "0"}}
+	if len(params) >= len(defaults) {
+		return params
+	}
+	return append(append([]DataTypeParam{}, params...), defaults[len(params):]...)
+}
@@ -0,0 +1,184 @@
+package resourcetable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDataType_Valid(t *testing.T) {
+	tests := []string{
+		"UInt64",
+		"Nullable(String)",
+		"Decimal",
+		"Decimal(10, 2)",
+		"Nullable(Decimal(10, 2))",
+		"Decimal64(4)",
+		"DateTime('Europe/Madrid')",
+		"DateTime64(6)",
+		"Nullable(DateTime64(6, 'UTC'))",
+		"FixedString(16)",
+		"IPv4",
+		"IPv6",
+		"Array(String)",
+		"Array(Array(Nullable(UInt8)))",
+		"Map(String, UInt64)",
+		"Map(LowCardinality(String), Array(Float64))",
+		"Tuple(UInt8, String)",
+		"Tuple(a Int8, b String)",
+		"Tuple(`first name` String, `last name` String)",
+		"Enum8('a' = 1, 'b' = 2)",
+		"Enum16('a' = -1000, 'b' = 1000)",
+		"Enum('a', 'b')",
+		"Nested(id UInt32, name String)",
+		"LowCardinality(String)",
+		"LowCardinality(Nullable(String))",
+		"AggregateFunction(uniq, UInt64)",
+		"AggregateFunction(quantiles(0.5, 0.9), Float64)",
+		"AggregateFunction(anyIf, String, UInt8)",
+		"SimpleAggregateFunction(sum, UInt64)",
+		"Variant(UInt64, String, Array(UInt64))",
+		"Dynamic",
+		"Dynamic(max_types = 10)",
+		"JSON",
+		"JSON(max_dynamic_paths = 16, a.b UInt32, SKIP a.c)",
+		"Bool",
+		"UUID",
+		"Date32",
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseDataType(tt); err != nil {
+			t.Errorf("ParseDataType(%q) unexpected error: %v", tt, err)
+		}
+	}
+}
+
+func TestParseDataType_Invalid(t *testing.T) {
+	tests := []struct {
+		dataType string
+		wantErr  string
+	}{
+		{"Strng", "unknown data type Strng"},
+		{"UInt64(1)", "UInt64 expects 0 parameters"},
+		{"Nullable(Array(String))", "nested type Array cannot be inside Nullable type"},
+		{"Nullable(LowCardinality(String))", "nested type LowCardinality cannot be inside Nullable type"},
+		{"Nullable(Nullable(String))", "nested type Nullable cannot be inside Nullable type"},
+		{"Nullable(String", "unexpected end of type"},
+		{"Nullable(String))", `unexpected ")" at position 16, expected end of type`},
+		{"Array(String, String)", "Array expects 1 parameters, got 2"},
+		{"Array(1)", "Array expects data types as parameters"},
+		{"Map(String)", "Map expects 2 parameters, got 1"},
+		{"Map(Nullable(String), UInt64)", "not allowed as a Map key"},
+		{"Tuple(a Int8, String)", "must be all named or all unnamed"},
+		{"Tuple(a Int8, a String)", "element a is duplicated"},
+		{"Nested(UInt32, String)", "Nested elements must be named"},
+		{"Array(Nested(id UInt32))", "Nested type is only allowed at top level"},
+		{"Enum8('a' = 1, 'b' = 200)", "between -128 and 127"},
+		{"Enum8('a' = 1, 'a' = 2)", "element 'a' is duplicated"},
+		{"Enum8('a' = 1, 'b' = 1)", "value 1 is duplicated"},
+		{"Enum8(a = 1)", "expected an enum element name"},
+		{"FixedString(0)", "FixedString length must be between 1"},
+		{"FixedString", "FixedString expects 1 parameters, got 0"},
+		{"BINARY", "FixedString expects 1 parameters, got 0"},
+		{"Decimal(77, 2)", "Decimal precision must be between 1 and 76"},
+		{"Decimal(10, 11)", "Decimal scale must be between 0 and 10"},
+		{"DateTime64(10)", "DateTime64 precision must be between 0 and 9"},
+		{"DateTime(UTC)", "DateTime timezone must be a string literal"},
+		{"LowCardinality(Array(String))", "LowCardinality is not supported for Array(String)"},
+		{"SimpleAggregateFunction(sum)", "SimpleAggregateFunction expects 2 parameters"},
+		{"AggregateFunction(uniq, 1)", "AggregateFunction expects data types as parameters"},
+		{"Variant(Nullable(String), UInt64)", "not allowed inside Variant"},
+		{"Dynamic(types = 10)", "Dynamic only accepts max_types parameter"},
+		{"String;", "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseDataType(tt.dataType)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseDataType(%q) error = %v, want %q", tt.dataType, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDataTypeString(t *testing.T) {
+	tests := []struct {
+		dataType string
+		want     string
+	}{
+		{"Decimal(10,2)", "Decimal(10, 2)"},
+		{"Map( String ,Array( UInt8 ) )", "Map(String, Array(UInt8))"},
+		{"Tuple(a Int8,b String)", "Tuple(a Int8, b String)"},
+		{"Enum8('a'=1,'b'=2)", "Enum8('a' = 1, 'b' = 2)"},
+		{"AggregateFunction(quantiles(0.5,0.9), Float64)", "AggregateFunction(quantiles(0.5, 0.9), Float64)"},
+	}
+
+	for _, tt := range tests {
+		dataType, err := ParseDataType(tt.dataType)
+		if err != nil {
+			t.Fatalf("ParseDataType(%q) unexpected error: %v", tt.dataType, err)
+		}
+		if got := dataType.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalDataType(t *testing.T) {
+	tests := []struct {
+		dataType string
+		want     string
+	}{
+		{"INT", "Int32"},
+		{"Int8", "Int8"},
+		{"INT8", "Int64"},
+		{"bigint", "Int64"},
+		{"TEXT", "String"},
+		{"VARCHAR(255)", "String"},
+		{"BINARY(16)", "FixedString(16)"},
+		{"VARBINARY(16)", "String"},
+		{"BYTEA", "String"},
+		{"Nullable(TEXT)", "Nullable(String)"},
+		{"BOOLEAN", "Bool"},
+		{"DOUBLE", "Float64"},
+		{"NUMERIC(10,2)", "Decimal(10, 2)"},
+		{"Array(INTEGER)", "Array(Int32)"},
+		{"not a type(", "not a type("},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalDataType(tt.dataType); got != tt.want {
+			t.Errorf("CanonicalDataType(%q) = %q, want %q", tt.dataType, got, tt.want)
+		}
+	}
+}
+
+func TestDataTypesEquivalent(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{"Decimal(10,2)", "Decimal(10, 2)", true},
+		{"Decimal", "Decimal(10, 0)", true},
+		{"NUMERIC(12)", "Decimal(12, 0)", true},
+		{"Decimal", "Decimal(12, 0)", false},
+		{"Decimal(10, 2)", "Decimal(10, 0)", false},
+		{"DateTime64(6)", "DateTime64(6, 'UTC')", true},
+		{"Nullable(DateTime64(6))", "Nullable(DateTime64(6, 'UTC'))", true},
+		{"DateTime", "DateTime('Europe/Madrid')", true},
+		{"DateTime64(6, 'Europe/Madrid')", "DateTime64(6, 'UTC')", false},
+		{"DateTime64(3)", "DateTime64(6)", false},
+		{"INT", "Int32", true},
+		{"TEXT", "String", true},
+		{"Map(TEXT, BIGINT)", "Map(String, Int64)", true},
+		{"Bool", "Bool", true},
+		{"String", "Nullable(String)", false},
+		{"Int32", "Int64", false},
+	}
+
+	for _, tt := range tests {
+		if got := DataTypesEquivalent(tt.a, tt.b); got != tt.want {
+			t.Errorf("DataTypesEquivalent(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package resourcetable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type engineParamKind int
+
+const (
+	// paramColumn is a single column name
+	paramColumn engineParamKind = iota
+	// paramColumns is a column name or a tuple of column names
+	paramColumns
+	// paramString is a quoted string literal
+	paramString
+	// paramExpression is any expression, it is not validated
+	paramExpression
+)
+
+type engineParam struct {
+	Name string
+	Kind engineParamKind
+}
+
+// engineSpec describes the parameters of a table engine, optional ones always follow the required ones
+type engineSpec struct {
+	Required []engineParam
+	Optional []engineParam
+}
+
+var mergeTreeEngines = map[string]engineSpec{
+	"MergeTree": {},
+	"ReplacingMergeTree": {
+		Optional: []engineParam{{"ver", paramColumn}, {"is_deleted", paramColumn}},
+	},
+	"SummingMergeTree": {
+		Optional: []engineParam{{"columns", paramColumns}},
+	},
+	"AggregatingMergeTree": {},
+	"CollapsingMergeTree": {
+		Required: []engineParam{{"sign", paramColumn}},
+	},
+	"VersionedCollapsingMergeTree": {
+		Required: []engineParam{{"sign", paramColumn}, {"version", paramColumn}},
+	},
+	"GraphiteMergeTree": {
+		Required: []engineParam{{"config_section", paramString}},
+	},
+}
+
+var distributedEngine = engineSpec{
+	Required: []engineParam{{"cluster", paramExpression}, {"database", paramExpression}, {"table", paramExpression}},
+	Optional: []engineParam{{"sharding_key", paramExpression}, {"policy_name", paramString}},
+}
+
+// Replicated engines take the ZooKeeper path and the replica name before the engine parameters.
+// Both can be omitted at once when the server has default_replica_path and default_replica_name.
+var replicationParams = []engineParam{{"zoo_path", paramString}, {"replica_name", paramString}}
+
+const replicatedPrefix = "Replicated"
+
+// SupportedEngines returns the names of the table engines managed by this provider
+func SupportedEngines() []string {
+	engines := []string{"Distributed"}
+	for engine := range mergeTreeEngines {
+		engines = append(engines, engine, replicatedPrefix+engine)
+	}
+	sort.Strings(engines)
+	return engines
+}
+
+func isReplicatedEngine(engine string) bool {
+	_, ok := mergeTreeEngines[strings.TrimPrefix(engine, replicatedPrefix)]
+	return ok && strings.HasPrefix(engine, replicatedPrefix)
+}
+
+func getEngineSpec(engine string) (engineSpec, bool) {
+	if engine == "Distributed" {
+		return distributedEngine, true
+	}
+	spec, ok := mergeTreeEngines[strings.TrimPrefix(engine, replicatedPrefix)]
+	return spec, ok
+}
+
+func isStringLiteral(param string) bool {
+	return len(param) >= 2 && param[0] == '\'' && param[len(param)-1] == '\''
+}
+
+func (s engineSpec) accepts(count int) bool {
+	return count >= len(s.Required) && count <= len(s.Required)+len(s.Optional)
+}
+
+// splitReplicationParams separates the replication parameters of a Replicated engine from the engine ones
+func splitReplicationParams(engine string, params []string) ([]string, []string) {
+	if !isReplicatedEngine(engine) || len(params) < len(replicationParams) {
+		return nil, params
+	}
+	spec, _ := getEngineSpec(engine)
+	if isStringLiteral(params[0]) && isStringLiteral(params[1]) && spec.accepts(len(params)-len(replicationParams)) {
+		return params[:len(replicationParams)], params[len(replicationParams):]
+	}
+	return nil, params
+}
+
+// namedEngineParams maps every engine parameter to its definition, replication parameters excluded
+func namedEngineParams(engine string, params []string) map[string]engineParam {
+	spec, ok := getEngineSpec(engine)
+	if !ok {
+		return nil
+	}
+	_, params = splitReplicationParams(engine, params)
+	definitions := append(append([]engineParam{}, spec.Required...), spec.Optional...)
+	named := make(map[string]engineParam)
+	for i, param := range params {
+		if i < len(definitions) {
+			named[param] = definitions[i]
+		}
+	}
+	return named
+}
+
+// engineColumns returns the columns referenced by the engine parameters, like the sign or version columns
+func engineColumns(engine string, params []string) []string {
+	var columns []string
+	for param, definition := range namedEngineParams(engine, params) {
+		switch definition.Kind {
+		case paramColumn:
+			columns = append(columns, param)
+		case paramColumns:
+			columns = append(columns, common.SplitTuple(param)...)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// ValidateEngineParams checks the number and kind of the parameters of the table engine.
+// Column parameters are checked along with the table keys by Validate.
+func (t *TableResource) ValidateEngineParams() error {
+	spec, ok := getEngineSpec(t.Engine)
+	if !ok {
+		return fmt.Errorf("engine %q is not supported", t.Engine)
+	}
+	_, params := splitReplicationParams(t.Engine, t.EngineParams)
+	if !spec.accepts(len(params)) {
+		// Only the engine parameters are counted, the replication ones may have been added from the replication block
+		if isReplicatedEngine(t.Engine) && len(params) >= len(replicationParams) && isStringLiteral(params[0]) && isStringLiteral(params[1]) {
+			params = params[len(replicationParams):]
+		}
+		return fmt.Errorf("engine %s expects %s, got %d parameters", t.Engine, describeEngineParams(t.Engine, spec), len(params))
+	}
+
+	definitions := append(append([]engineParam{}, spec.Required...), spec.Optional...)
+	for i, param := range params {
+		if definitions[i].Kind == paramString && !isStringLiteral(param) {
+			return fmt.Errorf("engine %s parameter %s must be a string literal, got %s", t.Engine, definitions[i].Name, param)
+		}
+	}
+	return nil
+}
+
+func describeEngineParams(engine string, spec engineSpec) string {
+	var names []string
+	for _, param := range spec.Required {
+		names = append(names, param.Name)
+	}
+	for _, param := range spec.Optional {
+		names = append(names, "["+param.Name+"]")
+	}
+	description := "no parameters"
+	if len(names) > 0 {
+		description = "(" + strings.Join(names, ", ") + ")"
+	}
+	if isReplicatedEngine(engine) {
+		description += " besides the replication parameters"
+	}
+	return description
+}
@@ -0,0 +1,135 @@
+package resourcetable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateEngineParams(t *testing.T) {
+	columns := []interface{}{
+		map[string]interface{}{"name": "key", "type": "Int64"},
+		map[string]interface{}{"name": "value", "type": "Int64"},
+		map[string]interface{}{"name": "sign", "type": "Int8"},
+		map[string]interface{}{"name": "version", "type": "UInt64"},
+	}
+
+	tests := []struct {
+		engine  string
+		params  []string
+		wantErr string
+	}{
+		{"MergeTree", nil, ""},
+		{"MergeTree", []string{"key"}, "expects no parameters"},
+		{"ReplicatedMergeTree", nil, ""},
+		{"ReplicatedMergeTree", []string{"'/clickhouse/tables/{shard}/dm/events'", "'{replica}'"}, ""},
+		{"ReplicatedMergeTree", []string{"'/clickhouse/tables/{shard}/dm/events'"}, "expects no parameters besides the replication parameters, got 1 parameters"},
+		{"ReplacingMergeTree", []string{"version"}, ""},
+		{"ReplicatedReplacingMergeTree", []string{"'/clickhouse/tables/{shard}/dm/events'", "'{replica}'", "version", "sign"}, ""},
+		{"SummingMergeTree", []string{"(value, version)"}, ""},
+		{"AggregatingMergeTree", nil, ""},
+		{"CollapsingMergeTree", nil, "expects (sign)"},
+		{"ReplicatedCollapsingMergeTree", []string{"sign"}, ""},
+		{"VersionedCollapsingMergeTree", []string{"sign", "version"}, ""},
+		{"ReplicatedVersionedCollapsingMergeTree", []string{"'/clickhouse/tables/{shard}/dm/events'", "'{replica}'", "sign"}, "got 1 parameters"},
+		{"GraphiteMergeTree", []string{"'graphite_rollup'"}, ""},
+		{"GraphiteMergeTree", []string{"graphite_rollup"}, "must be a string literal"},
+		{"ReplicatedGraphiteMergeTree", []string{"'/clickhouse/tables/{shard}/dm/events'", "'{replica}'", "'graphite_rollup'"}, ""},
+		{"Distributed", []string{"'{cluster}'", "dm", "events_local", "rand()"}, ""},
+		{"Distributed", []string{"'{cluster}'", "dm"}, "expects (cluster, database, table, [sharding_key], [policy_name])"},
+		{"Log", nil, `engine "Log" is not supported`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine+"("+strings.Join(tt.params, ", ")+")", func(t *testing.T) {
+			tableResource := TableResource{Engine: tt.engine, EngineParams: tt.params, Columns: columns}
+			err := tableResource.ValidateEngineParams()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateEngineParams() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateEngineParams() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseEngineParams(t *testing.T) {
+	tests := []struct {
+		engine     string
+		engineFull string
+		want       []string
+	}{
+		{"MergeTree", "MergeTree ORDER BY key SETTINGS index_granularity = 8192", []string{}},
+		{"SummingMergeTree", "SummingMergeTree((value, total)) ORDER BY key", []string{"(value, total)"}},
+		{
+			"ReplicatedVersionedCollapsingMergeTree",
+			"ReplicatedVersionedCollapsingMergeTree('/clickhouse/tables/{shard}/dm/events', '{replica}', sign, version) ORDER BY key",
+			[]string{"'/clickhouse/tables/{shard}/dm/events'", "'{replica}'", "sign", "version"},
+		},
+		{"Distributed", "Distributed('{cluster}', 'dm', 'events_local', cityHash64(key))", []string{"'{cluster}'", "'dm'", "'events_local'", "cityHash64(key)"}},
+	}
+
+	for _, tt := range tests {
+		chTable := CHTable{Engine: tt.engine, EngineFull: tt.engineFull}
+		tableResource, diags := chTable.ToResource()
+		if len(diags) > 0 {
+			t.Fatalf("ToResource(%q) unexpected diagnostics: %v", tt.engineFull, diags)
+		}
+		if !reflect.DeepEqual(tableResource.EngineParams, tt.want) {
+			t.Errorf("ToResource(%q) engine params = %q, want %q", tt.engineFull, tableResource.EngineParams, tt.want)
+		}
+	}
+}
+
+func TestRequiresReplacement_EngineColumns(t *testing.T) {
+	tableResource := TableResource{OrderBy: []string{"key"}, Engine: "CollapsingMergeTree", EngineParams: []string{"sign"}}
+	diff := DiffColumns(testColumns("key", "Int64", "sign", "Int8"), testColumns("key", "Int64", "sign", "Int16"), false)
+	if !tableResource.RequiresReplacement(diff) {
+		t.Errorf("RequiresReplacement() = false, want true when the sign column is modified")
+	}
+}
+
+func TestCHTableToResource_Distributed(t *testing.T) {
+	chTable := CHTable{
+		Database:   "dm",
+		Name:       "events",
+		Engine:     "Distributed",
+		EngineFull: "Distributed('bi_cluster', 'dm', 'events_local', cityHash64(key), 'tiered')",
+	}
+	tableResource, diags := chTable.ToResource()
+	if len(diags) > 0 {
+		t.Fatalf("ToResource() unexpected diagnostics: %v", diags)
+	}
+
+	want := &DistributedResource{
+		Cluster:        "bi_cluster",
+		RemoteDatabase: "dm",
+		RemoteTable:    "events_local",
+		ShardingKey:    "cityHash64(key)",
+		PolicyName:     "tiered",
+	}
+	if !reflect.DeepEqual(tableResource.Distributed, want) {
+		t.Errorf("ToResource() distributed = %+v, want %+v", tableResource.Distributed, want)
+	}
+	if got := tableResource.Distributed.EngineParams(); !reflect.DeepEqual(got, tableResource.EngineParams) {
+		t.Errorf("EngineParams() = %q, want %q", got, tableResource.EngineParams)
+	}
+}
+
+func TestDistributedResourceEngineParams(t *testing.T) {
+	tests := []struct {
+		distributed DistributedResource
+		want        []string
+	}{
+		{DistributedResource{Cluster: "bi_cluster", RemoteDatabase: "dm", RemoteTable: "events_local"}, []string{"'bi_cluster'", "'dm'", "'events_local'"}},
+		{DistributedResource{Cluster: "{cluster}", RemoteDatabase: "dm", RemoteTable: "events_local", ShardingKey: "rand()"}, []string{"'{cluster}'", "'dm'", "'events_local'", "rand()"}},
+		{DistributedResource{Cluster: "bi_cluster", RemoteDatabase: "dm", RemoteTable: "events_local", PolicyName: "tiered"}, []string{"'bi_cluster'", "'dm'", "'events_local'", "rand()", "'tiered'"}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.distributed.EngineParams(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EngineParams() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package resourcetable
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
 
@@ -15,14 +17,26 @@ type CHTable struct {
 	Engine           string     `ch:"engine"`
 	Comment          string     `ch:"comment"`
 	CreateTableQuery string     `ch:"create_table_query"`
+	SortingKey       string     `ch:"sorting_key"`
+	PartitionKey     string     `ch:"partition_key"`
+	PrimaryKey       string     `ch:"primary_key"`
+	SamplingKey      string     `ch:"sampling_key"`
+	StoragePolicy    string     `ch:"storage_policy"`
+	TotalRows        *uint64    `ch:"total_rows"`
+	TotalBytes       *uint64    `ch:"total_bytes"`
+	UUID             string     `ch:"uuid"`
 	Columns          []CHColumn `ch:"columns"`
 }
 
 type CHColumn struct {
-	Database string `ch:"database"`
-	Table    string `ch:"table"`
-	Name     string `ch:"name"`
-	Type     string `ch:"type"`
+	Database          string `ch:"database"`
+	Table             string `ch:"table"`
+	Name              string `ch:"name"`
+	Type              string `ch:"type"`
+	DefaultKind       string `ch:"default_kind"`
+	DefaultExpression string `ch:"default_expression"`
+	CompressionCodec  string `ch:"compression_codec"`
+	Comment           string `ch:"comment"`
 }
 
 type TableResource struct {
@@ -36,11 +50,49 @@ type TableResource struct {
 	OrderBy      []string
 	Columns      []interface{}
 	PartitionBy  []PartitionByResource
+	// PartitionByExpression is the free-form alternative to PartitionBy
+	PartitionByExpression string
+	PrimaryKey            []string
+	SampleBy              string
+	TTL                   []TTLResource
+	Settings              map[string]string
+	Distributed           *DistributedResource
+	Replication           *ReplicationResource
+
+	// Computed values reported by system.tables
+	SortingKey    string
+	PartitionKey  string
+	StoragePolicy string
+	TotalRows     uint64
+	TotalBytes    uint64
+	UUID          string
+}
+
+type DistributedResource struct {
+	Cluster        string
+	RemoteDatabase string
+	RemoteTable    string
+	ShardingKey    string
+	PolicyName     string
 }
 
 type ColumnResource struct {
-	Name string
-	Type string
+	Name              string
+	Type              string
+	DefaultKind       string
+	DefaultExpression string
+	Codec             string
+	TTL               string
+	Comment           string
+}
+
+type TTLResource struct {
+	Expression string
+	Action     string
+	Target     string
+	Where      string
+	GroupBy    []string
+	Set        map[string]string
 }
 
 type PartitionByResource struct {
@@ -61,93 +113,166 @@ func (t *CHTable) ColumnsToResource() []interface{} {
 	return columnResources
 }
 
-func (t *CHTable) ToResource() (*TableResource, error) {
+// ColumnsDefinitionToResource maps columns along with their default expressions, codecs and comments.
+// Column TTLs are not available in system.columns, they are taken from the create query.
+func (t *CHTable) ColumnsDefinitionToResource(createQuery *common.CreateQuery) []interface{} {
+	var columnResources []interface{}
+	for _, column := range t.Columns {
+		ttl := ""
+		if columnDefinition := createQuery.Column(column.Name); columnDefinition != nil {
+			ttl = columnDefinition.TTL
+		}
+		columnResource := map[string]interface{}{
+			"name":               column.Name,
+			"type":               column.Type,
+			"default_kind":       column.DefaultKind,
+			"default_expression": column.DefaultExpression,
+			"codec":              strings.TrimSuffix(strings.TrimPrefix(column.CompressionCodec, "CODEC("), ")"),
+			"ttl":                ttl,
+			"comment":            column.Comment,
+		}
+		columnResources = append(columnResources, columnResource)
+	}
+
+	return columnResources
+}
+
+// ParseCreateQuery parses the table definition from create_table_query, or from engine_full when it is not available
+func (t *CHTable) ParseCreateQuery() (*common.CreateQuery, error) {
+	switch {
+	case t.CreateTableQuery != "":
+		return common.ParseCreateQuery(t.CreateTableQuery)
+	case t.EngineFull != "":
+		return common.ParseEngineFull(t.EngineFull)
+	default:
+		return &common.CreateQuery{}, nil
+	}
+}
+
+var partitionFunctionRegex = regexp.MustCompile(`^(toYYYYMM|toYYYYMMDD|toYYYYMMDDhhmmss)\((.+)\)$`)
+
+// ToResource builds the resource from the system tables. A create query the provider can't parse is not an error:
+// the resource is built from the system.tables fields, engine_full when it parses, and a warning is returned.
+func (t *CHTable) ToResource() (*TableResource, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	createQuery, err := t.ParseCreateQuery()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Can't parse the create query of table %s.%s", t.Database, t.Name),
+			Detail:   fmt.Sprintf("%v. The table is read from system.tables, settings, TTL and engine parameters that are only in the create query may show as changes.", err),
+		})
+		createQuery = &common.CreateQuery{}
+		if t.CreateTableQuery != "" && t.EngineFull != "" {
+			if engineQuery, err := common.ParseEngineFull(t.EngineFull); err == nil {
+				createQuery = engineQuery
+			}
+		}
+	}
+
 	tableResource := TableResource{
 		Database:   t.Database,
 		Name:       t.Name,
 		EngineFull: t.EngineFull,
 		Engine:     t.Engine,
-		Columns:    t.ColumnsToResource(),
+		Columns:    t.ColumnsDefinitionToResource(createQuery),
 	}
 
-	r, _ := regexp.Compile(`MergeTree\((?P<engine_params>[^)]*)\)`)
-	matches := r.FindStringSubmatch(t.EngineFull)
-	engineParamsIndex := r.SubexpIndex("engine_params")
 	engineParams := make([]string, 0)
-	if engineParamsIndex != -1 {
-
-		regex := regexp.MustCompile("[, ]+")
-		params := regex.Split(matches[r.SubexpIndex("engine_params")], -1)
-		engineParams = append(engineParams, params...)
+	if createQuery.Engine != nil {
+		engineParams = createQuery.Engine.Params
 	}
 
-	comment, cluster, err := common.UnmarshalComment(t.Comment)
-	if err != nil {
-		return nil, err
-	}
+	// Comments are stored verbatim, only tables created by older provider versions carry the cluster in them
+	comment, cluster, _ := common.ParseLegacyComment(t.Comment)
 
 	tableResource.Cluster = cluster
 	tableResource.Comment = comment
 	tableResource.EngineParams = engineParams
 
-	createQuery := t.CreateTableQuery
-	if createQuery == "" {
-		createQuery = t.EngineFull
-	}
-
-	orderByRegex := regexp.MustCompile(`(?i)ORDER\s+BY\s+(\([^)]+\)|[^(]+?)(?:\s+PARTITION|\s+COMMENT|\s+SETTINGS|$)`)
-	orderByMatches := orderByRegex.FindStringSubmatch(createQuery)
-	if len(orderByMatches) > 1 {
-		orderByStr := orderByMatches[1]
-		orderByStr = regexp.MustCompile(`^\(|\)$`).ReplaceAllString(orderByStr, "")
-		orderByStr = regexp.MustCompile(`\s+`).ReplaceAllString(orderByStr, " ")
-		orderByStr = regexp.MustCompile(`^\s+|\s+$`).ReplaceAllString(orderByStr, "")
-		orderByParts := regexp.MustCompile(`,\s*`).Split(orderByStr, -1)
-		tableResource.OrderBy = make([]string, 0)
-		for _, part := range orderByParts {
-			part = regexp.MustCompile(`^\s+|\s+$`).ReplaceAllString(part, "")
-			if part != "" {
-				tableResource.OrderBy = append(tableResource.OrderBy, part)
-			}
+	// sorting_key and partition_key are the authoritative keys of MergeTree tables,
+	// the create query is only used for engines that do not report them.
+	switch {
+	case t.SortingKey != "":
+		tableResource.OrderBy = common.SplitTopLevel(t.SortingKey)
+	case createQuery.HasClause("ORDER BY"):
+		tableResource.OrderBy = createQuery.OrderBy
+	}
+
+	partitionKey := createQuery.PartitionBy
+	tableResource.PartitionByExpression = createQuery.Clauses["PARTITION BY"]
+	if t.PartitionKey != "" {
+		partitionKey = common.SplitTuple(t.PartitionKey)
+		tableResource.PartitionByExpression = t.PartitionKey
+	}
+	for _, partitionBy := range partitionKey {
+		partitionByResource := PartitionByResource{By: partitionBy}
+		if matches := partitionFunctionRegex.FindStringSubmatch(partitionBy); matches != nil {
+			partitionByResource = PartitionByResource{By: matches[2], PartitionFunction: matches[1]}
 		}
+		tableResource.PartitionBy = append(tableResource.PartitionBy, partitionByResource)
 	}
 
-	partitionByRegex := regexp.MustCompile(`(?i)PARTITION\s+BY\s+((?:[^\s]|\([^)]+\))+?)(?:\s+(?:ORDER|COMMENT|SETTINGS)|\s*$)`)
-	partitionByMatches := partitionByRegex.FindStringSubmatch(createQuery)
-	if len(partitionByMatches) > 1 {
-		partitionByStr := partitionByMatches[1]
-		partitionByStr = regexp.MustCompile(`\s+`).ReplaceAllString(partitionByStr, " ")
-		partitionByStr = regexp.MustCompile(`^\s+|\s+$`).ReplaceAllString(partitionByStr, "")
+	if t.Engine == "Distributed" && len(engineParams) >= len(distributedEngine.Required) {
+		tableResource.Distributed = &DistributedResource{
+			Cluster:        common.UnquoteString(engineParams[0]),
+			RemoteDatabase: common.UnquoteString(engineParams[1]),
+			RemoteTable:    common.UnquoteString(engineParams[2]),
+		}
+		if len(engineParams) > 3 {
+			tableResource.Distributed.ShardingKey = engineParams[3]
+		}
+		if len(engineParams) > 4 {
+			tableResource.Distributed.PolicyName = common.UnquoteString(engineParams[4])
+		}
+	}
 
-		funcRegex := regexp.MustCompile(`^(toYYYYMM|toYYYYMMDD|toYYYYMMDDhhmmss)\(([^)]+)\)$`)
-		funcMatches := funcRegex.FindStringSubmatch(partitionByStr)
-		if len(funcMatches) > 2 {
-			tableResource.PartitionBy = []PartitionByResource{
-				{
-					By:                funcMatches[2],
-					PartitionFunction: funcMatches[1],
-				},
-			}
-		} else {
-			tableResource.PartitionBy = []PartitionByResource{
-				{
-					By:                partitionByStr,
-					PartitionFunction: "",
-				},
-			}
+	if replication, _ := splitReplicationParams(t.Engine, engineParams); replication != nil {
+		tableResource.Replication = &ReplicationResource{
+			ZooPath:     common.UnquoteString(replication[0]),
+			ReplicaName: common.UnquoteString(replication[1]),
 		}
 	}
 
-	return &tableResource, nil
+	tableResource.PrimaryKey = common.SplitTopLevel(t.PrimaryKey)
+	tableResource.SampleBy = t.SamplingKey
+	tableResource.SortingKey = t.SortingKey
+	tableResource.PartitionKey = t.PartitionKey
+	tableResource.StoragePolicy = t.StoragePolicy
+	tableResource.UUID = t.UUID
+	if t.TotalRows != nil {
+		tableResource.TotalRows = *t.TotalRows
+	}
+	if t.TotalBytes != nil {
+		tableResource.TotalBytes = *t.TotalBytes
+	}
+	tableResource.TTL = parseTTL(createQuery.TTL)
+	tableResource.Settings = createQuery.Settings
+	if tableResource.Settings == nil {
+		tableResource.Settings = make(map[string]string)
+	}
+
+	return &tableResource, diags
 }
 
 func (t *TableResource) GetColumnsResourceList() []ColumnResource {
+	return columnsResourceList(t.Columns)
+}
+
+func columnsResourceList(columns []interface{}) []ColumnResource {
 	var columnResources []ColumnResource
-	for _, column := range t.Columns {
-		columnResources = append(columnResources, ColumnResource{
-			Name: column.(map[string]interface{})["name"].(string),
-			Type: column.(map[string]interface{})["type"].(string),
-		})
+	for _, column := range columns {
+		columnMap := column.(map[string]interface{})
+		columnResource := ColumnResource{
+			Name: columnMap["name"].(string),
+			Type: columnMap["type"].(string),
+		}
+		columnResource.DefaultKind, _ = columnMap["default_kind"].(string)
+		columnResource.DefaultExpression, _ = columnMap["default_expression"].(string)
+		columnResource.Codec, _ = columnMap["codec"].(string)
+		columnResource.TTL, _ = columnMap["ttl"].(string)
+		columnResource.Comment, _ = columnMap["comment"].(string)
+		columnResources = append(columnResources, columnResource)
 	}
 	return columnResources
 }
@@ -162,6 +287,94 @@ func (t *TableResource) SetPartitionBy(partitionBy []interface{}) {
 	}
 }
 
+func (t *TableResource) SetTTL(ttl []interface{}) {
+	for _, rule := range ttl {
+		ruleMap := rule.(map[string]interface{})
+		ttlResource := TTLResource{
+			Expression: ruleMap["expression"].(string),
+			Action:     ruleMap["action"].(string),
+			Target:     ruleMap["target"].(string),
+			Where:      ruleMap["where"].(string),
+			GroupBy:    common.MapArrayInterfaceToArrayOfStrings(ruleMap["group_by"].([]interface{})),
+			Set:        make(map[string]string),
+		}
+		for column, expression := range ruleMap["set"].(map[string]interface{}) {
+			ttlResource.Set[column] = expression.(string)
+		}
+		t.TTL = append(t.TTL, ttlResource)
+	}
+}
+
+func (t *TableResource) TTLToResource() []interface{} {
+	ttl := make([]interface{}, 0)
+	for _, rule := range t.TTL {
+		set := make(map[string]interface{})
+		for column, expression := range rule.Set {
+			set[column] = expression
+		}
+		ttl = append(ttl, map[string]interface{}{
+			"expression": rule.Expression,
+			"action":     rule.Action,
+			"target":     rule.Target,
+			"where":      rule.Where,
+			"group_by":   rule.GroupBy,
+			"set":        set,
+		})
+	}
+	return ttl
+}
+
+func (t *TableResource) SetSettings(settings map[string]interface{}) {
+	t.Settings = make(map[string]string)
+	for name, value := range settings {
+		t.Settings[name] = value.(string)
+	}
+}
+
+func (t *TableResource) SetDistributed(distributed []interface{}) {
+	if len(distributed) == 0 || distributed[0] == nil {
+		return
+	}
+	distributedMap := distributed[0].(map[string]interface{})
+	t.Distributed = &DistributedResource{
+		Cluster:        distributedMap["cluster"].(string),
+		RemoteDatabase: distributedMap["remote_database"].(string),
+		RemoteTable:    distributedMap["remote_table"].(string),
+		ShardingKey:    distributedMap["sharding_key"].(string),
+		PolicyName:     distributedMap["policy_name"].(string),
+	}
+}
+
+func (t *TableResource) DistributedToResource() []interface{} {
+	distributed := make([]interface{}, 0)
+	if t.Distributed != nil {
+		distributed = append(distributed, map[string]interface{}{
+			"cluster":         t.Distributed.Cluster,
+			"remote_database": t.Distributed.RemoteDatabase,
+			"remote_table":    t.Distributed.RemoteTable,
+			"sharding_key":    t.Distributed.ShardingKey,
+			"policy_name":     t.Distributed.PolicyName,
+		})
+	}
+	return distributed
+}
+
+// EngineParams returns the Distributed engine parameters, a sharding key is required to set a policy
+func (d *DistributedResource) EngineParams() []string {
+	params := []string{common.QuoteString(d.Cluster), common.QuoteString(d.RemoteDatabase), common.QuoteString(d.RemoteTable)}
+	shardingKey := d.ShardingKey
+	if shardingKey == "" && d.PolicyName != "" {
+		shardingKey = "rand()"
+	}
+	if shardingKey != "" {
+		params = append(params, shardingKey)
+	}
+	if d.PolicyName != "" {
+		params = append(params, common.QuoteString(d.PolicyName))
+	}
+	return params
+}
+
 func (t *TableResource) HasColumn(columnName string) bool {
 	for _, column := range t.GetColumnsResourceList() {
 		if column.Name == columnName {
@@ -171,31 +384,130 @@ func (t *TableResource) HasColumn(columnName string) bool {
 	return false
 }
 
-func (t *TableResource) Validate(diags diag.Diagnostics) {
-	t.validateOrderBy(diags)
-	t.validatePartitionBy(diags)
+// Validate checks that the table keys and the engine parameters reference declared columns.
+// Column references are only checked when the table defines columns.
+func (t *TableResource) Validate() diag.Diagnostics {
+	var diags diag.Diagnostics
+	diags = append(diags, t.validateOrderBy()...)
+	diags = append(diags, t.validatePartitionBy()...)
+	diags = append(diags, t.validatePrimaryKey()...)
+	diags = append(diags, t.validateSampleBy()...)
+	diags = append(diags, t.validateEngineColumns()...)
+	return diags
+}
+
+func (t *TableResource) validateOrderBy() diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, orderBy := range t.OrderBy {
+		diags = append(diags, t.validateColumnReferences(orderBy, cty.GetAttrPath("order_by").IndexInt(i))...)
+	}
+	return diags
+}
+
+func (t *TableResource) validatePartitionBy() diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, partitionBy := range t.PartitionBy {
+		diags = append(diags, t.validateColumnReferences(partitionBy.By, cty.GetAttrPath("partition_by").IndexInt(i).GetAttr("by"))...)
+	}
+	if t.PartitionByExpression != "" {
+		diags = append(diags, t.validateColumnReferences(t.PartitionByExpression, cty.GetAttrPath("partition_by_expression"))...)
+	}
+	return diags
 }
 
-func (t *TableResource) validateOrderBy(diags diag.Diagnostics) {
-	for _, orderField := range t.OrderBy {
-		if !t.HasColumn(orderField) {
+// validatePrimaryKey checks the primary key is a prefix of the sorting key, when both are given
+func (t *TableResource) validatePrimaryKey() diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, primaryKey := range t.PrimaryKey {
+		path := cty.GetAttrPath("primary_key").IndexInt(i)
+		diags = append(diags, t.validateColumnReferences(primaryKey, path)...)
+		if len(t.OrderBy) > 0 && (i >= len(t.OrderBy) || NormalizeExpression(primaryKey) != NormalizeExpression(t.OrderBy[i])) {
 			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  "wrong value",
-				Detail:   fmt.Sprintf("order by field '%s' is not a column", orderField),
+				Severity:      diag.Error,
+				Summary:       "primary key is not a prefix of the sorting key",
+				Detail:        fmt.Sprintf("primary key element %s must match order_by element %d", primaryKey, i),
+				AttributePath: path,
 			})
 		}
 	}
+	return diags
+}
+
+// validateSampleBy checks the sampling expression is one of the primary key elements
+func (t *TableResource) validateSampleBy() diag.Diagnostics {
+	if t.SampleBy == "" {
+		return nil
+	}
+	path := cty.GetAttrPath("sample_by")
+	diags := t.validateColumnReferences(t.SampleBy, path)
+	primaryKey := t.PrimaryKey
+	if len(primaryKey) == 0 {
+		primaryKey = t.OrderBy
+	}
+	for _, key := range primaryKey {
+		if NormalizeExpression(key) == NormalizeExpression(t.SampleBy) {
+			return diags
+		}
+	}
+	return append(diags, diag.Diagnostic{
+		Severity:      diag.Error,
+		Summary:       "sampling expression is not part of the primary key",
+		Detail:        fmt.Sprintf("sample by %s must be one of the primary key elements", t.SampleBy),
+		AttributePath: path,
+	})
+}
+
+// validateEngineColumns checks the column parameters of the engine, like the sign or version columns
+func (t *TableResource) validateEngineColumns() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(t.Columns) == 0 {
+		return diags
+	}
+	named := namedEngineParams(t.Engine, t.EngineParams)
+	for i, param := range t.EngineParams {
+		definition, ok := named[param]
+		if !ok || definition.Kind != paramColumn && definition.Kind != paramColumns {
+			continue
+		}
+		for _, column := range common.SplitTuple(param) {
+			if !t.HasColumn(column) {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "unknown column",
+					Detail:        fmt.Sprintf("engine %s parameter %s references %s, which is not a column", t.Engine, definition.Name, column),
+					AttributePath: cty.GetAttrPath("engine_params").IndexInt(i),
+				})
+			}
+		}
+	}
+	return diags
 }
 
-func (t *TableResource) validatePartitionBy(diags diag.Diagnostics) {
-	for _, partitionBy := range t.PartitionBy {
-		if !t.HasColumn(partitionBy.By) {
+// validateColumnReferences checks the expression is well formed and all the columns it references are declared
+func (t *TableResource) validateColumnReferences(expression string, path cty.Path) diag.Diagnostics {
+	columns, err := common.ExpressionColumns(expression)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "invalid expression",
+			Detail:        fmt.Sprintf("%s: %v", expression, err),
+			AttributePath: path,
+		}}
+	}
+	if len(t.Columns) == 0 {
+		return nil
+	}
+	var diags diag.Diagnostics
+	for _, column := range columns {
+		// Subcolumns of Nested and Tuple columns are referenced as column.field
+		if !t.HasColumn(column) && !t.HasColumn(strings.SplitN(column, ".", 2)[0]) {
 			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  "wrong value",
-				Detail:   fmt.Sprintf("partition by field '%s' is not a column", partitionBy.By),
+				Severity:      diag.Error,
+				Summary:       "unknown column",
+				Detail:        fmt.Sprintf("%s references %s, which is not a column", expression, column),
+				AttributePath: path,
 			})
 		}
 	}
+	return diags
 }
@@ -0,0 +1,79 @@
+package resourcetable
+
+import (
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+const defaultReplicaName = "{replica}"
+
+type ReplicationResource struct {
+	ZooPath               string
+	ReplicaName           string
+	UseDefaultReplicaPath bool
+}
+
+func (t *TableResource) SetReplication(replication []interface{}) {
+	if len(replication) == 0 || replication[0] == nil {
+		return
+	}
+	replicationMap := replication[0].(map[string]interface{})
+	t.Replication = &ReplicationResource{
+		ZooPath:               replicationMap["zoo_path"].(string),
+		ReplicaName:           replicationMap["replica_name"].(string),
+		UseDefaultReplicaPath: replicationMap["use_default_replica_path"].(bool),
+	}
+}
+
+func (t *TableResource) ReplicationToResource() []interface{} {
+	replication := make([]interface{}, 0)
+	if t.Replication != nil {
+		replication = append(replication, map[string]interface{}{
+			"zoo_path":                 t.Replication.ZooPath,
+			"replica_name":             t.Replication.ReplicaName,
+			"use_default_replica_path": t.Replication.UseDefaultReplicaPath,
+		})
+	}
+	return replication
+}
+
+// expandReplicationPath replaces the {database} and {table} macros, ClickHouse stores them expanded anyway.
+// Other macros, like {shard} or {uuid}, are left to the server.
+func expandReplicationPath(path string, database string, table string) string {
+	return strings.NewReplacer("{database}", database, "{table}", table).Replace(path)
+}
+
+// AddReplicationParams prepends the ZooKeeper path and replica name to the engine params of Replicated tables
+// that do not define them. The path comes from the replication block or from the provider template,
+// when there is none or the server default_replica_path is requested both are omitted.
+func (t *TableResource) AddReplicationParams(pathTemplate string) {
+	if !isReplicatedEngine(t.Engine) {
+		return
+	}
+	if replication, _ := splitReplicationParams(t.Engine, t.EngineParams); replication != nil {
+		return
+	}
+
+	replication := ReplicationResource{}
+	if t.Replication != nil {
+		replication = *t.Replication
+	}
+	if replication.UseDefaultReplicaPath {
+		return
+	}
+	path := replication.ZooPath
+	if path == "" {
+		path = pathTemplate
+	}
+	if path == "" {
+		return
+	}
+	replicaName := replication.ReplicaName
+	if replicaName == "" {
+		replicaName = defaultReplicaName
+	}
+
+	params := []string{common.QuoteString(expandReplicationPath(path, t.Database, t.Name)), common.QuoteString(replicaName)}
+	t.EngineParams = append(params, t.EngineParams...)
+}
@@ -0,0 +1,99 @@
+package resourcetable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddReplicationParams(t *testing.T) {
+	const template = "/clickhouse/tables/{shard}/{database}/{table}"
+
+	tests := []struct {
+		name         string
+		engine       string
+		engineParams []string
+		replication  *ReplicationResource
+		template     string
+		want         []string
+	}{
+		{
+			name:     "path from provider template",
+			engine:   "ReplicatedMergeTree",
+			template: template,
+			want:     []string{"'/clickhouse/tables/{shard}/dm/v_bonus_operations'", "'{replica}'"},
+		},
+		{
+			name:         "engine params are kept after the replication ones",
+			engine:       "ReplicatedReplacingMergeTree",
+			engineParams: []string{"version"},
+			template:     template,
+			want:         []string{"'/clickhouse/tables/{shard}/dm/v_bonus_operations'", "'{replica}'", "version"},
+		},
+		{
+			name:        "path and replica from replication block",
+			engine:      "ReplicatedMergeTree",
+			replication: &ReplicationResource{ZooPath: "/clickhouse/tables/{uuid}/{shard}", ReplicaName: "{replica}_1"},
+			template:    template,
+			want:        []string{"'/clickhouse/tables/{uuid}/{shard}'", "'{replica}_1'"},
+		},
+		{
+			name:        "server default replica path",
+			engine:      "ReplicatedMergeTree",
+			replication: &ReplicationResource{UseDefaultReplicaPath: true},
+			template:    template,
+			want:        nil,
+		},
+		{
+			name:   "no template",
+			engine: "ReplicatedMergeTree",
+			want:   nil,
+		},
+		{
+			name:         "explicit engine params",
+			engine:       "ReplicatedMergeTree",
+			engineParams: []string{"'/clickhouse/tables/{shard}/dm/v_bonus_operations'", "'{replica}'"},
+			template:     "/other/{database}/{table}",
+			want:         []string{"'/clickhouse/tables/{shard}/dm/v_bonus_operations'", "'{replica}'"},
+		},
+		{
+			name:     "not replicated",
+			engine:   "MergeTree",
+			template: template,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tableResource := TableResource{
+				Database:     "dm",
+				Name:         "v_bonus_operations",
+				Engine:       tt.engine,
+				EngineParams: tt.engineParams,
+				Replication:  tt.replication,
+			}
+			tableResource.AddReplicationParams(tt.template)
+			if !reflect.DeepEqual(tableResource.EngineParams, tt.want) {
+				t.Errorf("AddReplicationParams() engine params = %q, want %q", tableResource.EngineParams, tt.want)
+			}
+		})
+	}
+}
+
+func TestCHTableToResource_Replication(t *testing.T) {
+	chTable := CHTable{
+		Database:   "dm",
+		Name:       "events",
+		Engine:     "ReplicatedReplacingMergeTree",
+		EngineFull: "ReplicatedReplacingMergeTree('/clickhouse/tables/{uuid}/{shard}', '{replica}', version) ORDER BY key",
+	}
+	tableResource, diags := chTable.ToResource()
+	if len(diags) > 0 {
+		t.Fatalf("ToResource() unexpected diagnostics: %v", diags)
+	}
+
+	want := &ReplicationResource{ZooPath: "/clickhouse/tables/{uuid}/{shard}", ReplicaName: "{replica}"}
+	if !reflect.DeepEqual(tableResource.Replication, want) {
+		t.Errorf("ToResource() replication = %+v, want %+v", tableResource.Replication, want)
+	}
+}
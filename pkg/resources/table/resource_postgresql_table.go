@@ -3,7 +3,6 @@ package resourcetable
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
@@ -212,7 +211,7 @@ func resourcePostgreSQLTableDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func buildCreatePostgreSQLTableSentence(resource PostgreSQLTableResource) (query string) {
-	parts := []string{fmt.Sprintf("CREATE TABLE %s.%s", resource.Database, resource.Name)}
+	parts := []string{fmt.Sprintf("CREATE TABLE %s", common.QuoteTableName(resource.Database, resource.Name))}
 
 	if len(resource.Columns) > 0 {
 		columnsList := buildColumnsSentence(resource.GetColumnsResourceList())
@@ -260,56 +259,16 @@ func (t *CHTable) ToPostgreSQLResource() (*PostgreSQLTableResource, error) {
 	}
 
 	//  PostgreSQL('host:port', 'database', 'table', 'user', 'password', 'schema')
-	postgresqlRegex := regexp.MustCompile(`PostgreSQL\(([^)]+)\)`)
-	matches := postgresqlRegex.FindStringSubmatch(t.EngineFull)
-	if len(matches) > 1 {
-		paramsStr := matches[1]
-		params := parsePostgreSQLParams(paramsStr)
-		tableResource.EngineParams = params
-	}
-
-	comment, _, err := common.UnmarshalComment(t.Comment)
+	createQuery, err := t.ParseCreateQuery()
 	if err != nil {
-		comment = strings.TrimSpace(t.Comment)
+		return nil, fmt.Errorf("parsing create table query: %v", err)
 	}
-	tableResource.Comment = comment
-
-	return &tableResource, nil
-}
-
-func parsePostgreSQLParams(paramsStr string) []string {
-	var params []string
-	var current strings.Builder
-	inQuotes := false
-	quoteChar := byte(0)
-
-	for i := 0; i < len(paramsStr); i++ {
-		char := paramsStr[i]
-
-		if (char == '\'' || char == '"') && (i == 0 || paramsStr[i-1] != '\\') {
-			if !inQuotes {
-				inQuotes = true
-				quoteChar = char
-			} else if char == quoteChar {
-				inQuotes = false
-				quoteChar = 0
-			}
-			current.WriteByte(char)
-		} else if char == ',' && !inQuotes {
-			param := strings.TrimSpace(current.String())
-			if param != "" {
-				params = append(params, param)
-			}
-			current.Reset()
-		} else {
-			current.WriteByte(char)
-		}
+	if createQuery.Engine != nil && len(createQuery.Engine.Params) > 0 {
+		tableResource.EngineParams = createQuery.Engine.Params
 	}
 
-	param := strings.TrimSpace(current.String())
-	if param != "" {
-		params = append(params, param)
-	}
+	comment, _, _ := common.ParseLegacyComment(t.Comment)
+	tableResource.Comment = comment
 
-	return params
+	return &tableResource, nil
 }
@@ -3,20 +3,35 @@ package resourcetable
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func ResourceTable() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Description: "Resource to manage tables",
 
+		SchemaVersion: 1,
+
 		CreateContext: resourceTableCreate,
 		ReadContext:   resourceTableRead,
 		UpdateContext: resourceTableUpdate,
 		DeleteContext: resourceTableDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTableImport,
+		},
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIf("column", columnsRequireReplacement),
+			customdiff.ForceNewIf("settings", settingsRequireReplacement),
+			validateEngineParams,
+			validateTTL,
+			validateTable,
+		),
 		Schema: map[string]*schema.Schema{
 			"database": {
 				Description: "DB Name where the table will bellow",
@@ -25,7 +40,7 @@ func ResourceTable() *schema.Resource {
 				ForceNew:    true,
 			},
 			"comment": {
-				Description: "Database comment, it will be codified in a json along with come metadata information (like cluster name in case of clustering)",
+				Description: "Table comment, stored as given",
 				Type:        schema.TypeString,
 				Optional:    true,
 				ForceNew:    false, // Можно изменять через ALTER TABLE
@@ -43,44 +58,127 @@ func ResourceTable() *schema.Resource {
 				ForceNew:    true,
 			},
 			"engine": {
-				Description:      "Table engine type (Supported types so far: Distributed, ReplicatedReplacingMergeTree, ReplacingMergeTree)",
+				Description:      "Table engine type, one of the MergeTree family engines (MergeTree, ReplacingMergeTree, SummingMergeTree, AggregatingMergeTree, CollapsingMergeTree, VersionedCollapsingMergeTree, GraphiteMergeTree), their Replicated variants or Distributed",
 				Type:             schema.TypeString,
 				Required:         true,
 				ForceNew:         true,
 				ValidateDiagFunc: ValidateOnClusterEngine,
 			},
 			"engine_params": {
-				Description: "Engine params in case the engine type requires them",
-				Type:        schema.TypeList,
-				Required:    true,
-				ForceNew:    true,
+				Description:   "Engine params in case the engine type requires them. Replicated engines take the ZooKeeper path and replica name first, both can be omitted when the server defines default ones. Distributed tables should rather use the `distributed` block",
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"distributed"},
 				Elem: &schema.Schema{
 					Type:     schema.TypeString,
 					ForceNew: true,
 				},
 			},
+			"distributed": {
+				Description:   "Distributed engine parameters, the engine must be Distributed",
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"engine_params"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cluster": {
+							Description: "Cluster the remote table is spread across, it must exist in system.clusters",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"remote_database": {
+							Description: "Database of the remote table",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"remote_table": {
+							Description: "Remote table name",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"sharding_key": {
+							Description:      "Sharding key expression, e.g. `rand()` or `cityHash64(user_id)`. ClickHouse requires one along with `policy_name`, `rand()` is used when it is not given",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressShardingKey,
+						},
+						"policy_name": {
+							Description: "Storage policy used to store temporary files for asynchronous sends",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+			"replication": {
+				Description: "Replication parameters of Replicated engines, used when `engine_params` does not start with the ZooKeeper path and replica name. The path defaults to the provider `replication_path_template`",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"zoo_path": {
+							Description:      "ZooKeeper path of the table, `{database}` and `{table}` macros are replaced by the provider and the rest by the server, e.g. `/clickhouse/tables/{shard}/{database}/{table}`",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressExpandedReplicationPath,
+						},
+						"replica_name": {
+							Description: "Replica name, `{replica}` macro by default",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+						},
+						"use_default_replica_path": {
+							Description: "Omit the ZooKeeper path and replica name, so the server `default_replica_path` and `default_replica_name` are used",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
 			"order_by": {
 				Description: "Order by columns to use as sorting key",
 				Type:        schema.TypeList,
 				Optional:    true,
 				ForceNew:    true,
 				Elem: &schema.Schema{
-					Type:     schema.TypeString,
-					ForceNew: true,
+					Type:             schema.TypeString,
+					ForceNew:         true,
+					DiffSuppressFunc: suppressEquivalentExpression,
 				},
 			},
 			"partition_by": {
-				Description: "Partition Key to split data",
-				Type:        schema.TypeList,
-				Optional:    true,
-				ForceNew:    true,
+				Description:   "Partition Key to split data",
+				Type:          schema.TypeList,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"partition_by_expression"},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"by": {
-							Description: "Column to use as part of the partition key",
-							Type:        schema.TypeString,
-							Required:    true,
-							ForceNew:    true,
+							Description:      "Column to use as part of the partition key",
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressEquivalentExpression,
 						},
 						"partition_function": {
 							Description:      "Partition function, could be empty or one of following: toYYYYMM, toYYYYMMDD or toYYYYMMDDhhmmss",
@@ -93,31 +191,191 @@ func ResourceTable() *schema.Resource {
 					},
 				},
 			},
-			"column": {
-				Description: "Column",
+			"partition_by_expression": {
+				Description:      "Partition key expression, e.g. `(toMonday(ts), intDiv(id, 1000000))`. Alternative to `partition_by` for keys it can't describe",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{"partition_by"},
+				DiffSuppressFunc: suppressEquivalentPartitionKey,
+			},
+			"primary_key": {
+				Description: "Primary key columns, it must be a prefix of `order_by`. Sorting key is used when not provided",
 				Type:        schema.TypeList,
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"sample_by": {
+				Description: "Sampling expression, it must be part of the primary key",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"ttl": {
+				Description: "Table TTL rules, modified in place",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"expression": {
+							Description:      "TTL expression, e.g. `event_time + INTERVAL 1 MONTH`",
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressEquivalentExpression,
+						},
+						"action": {
+							Description:      "Action to perform when the TTL expires, one of following: DELETE, TO DISK, TO VOLUME or GROUP BY",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "DELETE",
+							ValidateDiagFunc: ValidateTTLAction,
+						},
+						"target": {
+							Description: "Disk or volume name for TO DISK and TO VOLUME actions",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"where": {
+							Description:      "Condition the expired rows must match",
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressEquivalentExpression,
+						},
+						"group_by": {
+							Description: "Prefix of the primary key to group expired rows by, for GROUP BY action",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"set": {
+							Description: "Aggregations of the columns not in `group_by` for GROUP BY action, e.g. `{ value = \"max(value)\" }`",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							DiffSuppressFunc: suppressEquivalentExpression,
+						},
+					},
+				},
+			},
+			"settings": {
+				Description:      "Table settings, modified in place. Changing `index_granularity`, `index_granularity_bytes` or `enable_mixed_granularity_parts`, which ClickHouse doesn't allow to alter, requires replacing the table",
+				Type:             schema.TypeMap,
+				Optional:         true,
+				ValidateDiagFunc: common.ValidateSettingNames,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"sorting_key": {
+				Description: "Sorting key as reported by the server",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"partition_key": {
+				Description: "Partition key as reported by the server",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"storage_policy": {
+				Description: "Storage policy used by the table",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"total_rows": {
+				Description: "Total number of rows of the table, if known",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"total_bytes": {
+				Description: "Total number of bytes stored by the table, if known",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"uuid": {
+				Description: "Table UUID",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"detect_column_renames": {
+				Description: "Rename a column replaced at the same position by another one with a new name and the same definition, keeping its data. Otherwise the column is dropped and the new one added",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"column": {
+				Description: "Column. Columns are added, dropped, modified or renamed in place, see `detect_column_renames`. Changing the type of a column used by sorting or partition keys, renaming or dropping it requires replacing the table",
+				Type:        schema.TypeList,
+				Optional:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
 							Description: "Column Name",
 							Type:        schema.TypeString,
 							Required:    true,
-							ForceNew:    true,
 						},
 						"type": {
-							Description:      "Column Type",
+							Description:      "Column Type. Aliases (e.g. `INT` or `TEXT`) and spelling differences with the type reported by ClickHouse don't produce changes",
 							Type:             schema.TypeString,
 							Required:         true,
 							ValidateDiagFunc: ValidateType,
-							ForceNew:         true,
+							DiffSuppressFunc: suppressEquivalentDataType,
+						},
+						"default_kind": {
+							Description:      "Kind of the default expression, one of following (case insensitive): DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL. DEFAULT is used when only `default_expression` is given",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: ValidateDefaultKind,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return strings.EqualFold(old, new) || old == "DEFAULT" && new == ""
+							},
+						},
+						"default_expression": {
+							Description: "Default expression of the column. Spacing and keyword case differences with the expression reported by ClickHouse don't produce changes",
+							Type:        schema.TypeString,
+							Optional:    true,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return ExpressionsEquivalent(old, new)
+							},
+						},
+						"codec": {
+							Description: "Compression codecs without the CODEC keyword, e.g. `Delta, ZSTD(1)`. ClickHouse reports codecs with their default parameters (`Delta(8), ZSTD(1)`), a codec given without parameters matches them",
+							Type:        schema.TypeString,
+							Optional:    true,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return CodecsEquivalent(old, new)
+							},
+						},
+						"ttl": {
+							Description:      "Column TTL expression, e.g. `event_time + INTERVAL 1 MONTH`",
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressEquivalentExpression,
+						},
+						"comment": {
+							Description: "Column comment",
+							Type:        schema.TypeString,
+							Optional:    true,
 						},
 					},
 				},
 			},
 		},
 	}
+	// Version 0 stored the comment along with the cluster in a json
+	resource.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    resource.CoreConfigSchema().ImpliedType(),
+		Upgrade: upgradeTableStateV0,
+	}}
+	return resource
 }
 
 func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -136,10 +394,8 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) di
 		return diag.FromErr(fmt.Errorf("reading Clickhouse table: %v", err))
 	}
 
-	tableResource, err := chTable.ToResource()
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("transforming Clickhouse table to resource: %v", err))
-	}
+	tableResource, toResourceDiags := chTable.ToResource()
+	diags = append(diags, toResourceDiags...)
 
 	if err := d.Set("database", tableResource.Database); err != nil {
 		return diag.FromErr(fmt.Errorf("setting database: %v", err))
@@ -150,10 +406,36 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) di
 	if err := d.Set("engine", tableResource.Engine); err != nil {
 		return diag.FromErr(fmt.Errorf("setting engine: %v", err))
 	}
-	if err := d.Set("engine_params", tableResource.EngineParams); err != nil {
+	// Replication params derived from the replication block or the provider template are not part of engine_params,
+	// they are only kept there when the state already had them.
+	engineParams := tableResource.EngineParams
+	stateReplication, _ := splitReplicationParams(tableResource.Engine, common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{})))
+	if replication, params := splitReplicationParams(tableResource.Engine, engineParams); replication != nil && stateReplication == nil {
+		engineParams = params
+	}
+	if err := d.Set("engine_params", engineParams); err != nil {
 		return diag.FromErr(fmt.Errorf("setting engine_params: %v", err))
 	}
-	if err := d.Set("cluster", tableResource.Cluster); err != nil {
+	if tableResource.Replication != nil {
+		stateTableResource := TableResource{}
+		stateTableResource.SetReplication(d.Get("replication").([]interface{}))
+		if stateTableResource.Replication != nil {
+			tableResource.Replication.UseDefaultReplicaPath = stateTableResource.Replication.UseDefaultReplicaPath
+		}
+	}
+	if err := d.Set("replication", tableResource.ReplicationToResource()); err != nil {
+		return diag.FromErr(fmt.Errorf("setting replication: %v", err))
+	}
+	if err := d.Set("distributed", tableResource.DistributedToResource()); err != nil {
+		return diag.FromErr(fmt.Errorf("setting distributed: %v", err))
+	}
+	// Cluster is kept in the state (or given by the import id), tables created by older provider
+	// versions still report it through their comment.
+	cluster := tableResource.Cluster
+	if cluster == "" {
+		cluster = d.Get("cluster").(string)
+	}
+	if err := d.Set("cluster", cluster); err != nil {
 		return diag.FromErr(fmt.Errorf("setting cluster: %v", err))
 	}
 	if err := d.Set("order_by", tableResource.OrderBy); err != nil {
@@ -169,21 +451,86 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta any) di
 		}
 		partitionByList = append(partitionByList, partitionByMap)
 	}
+	// Only one of the partition key forms is kept, the one in use
+	partitionByExpression := ""
+	if d.Get("partition_by_expression").(string) != "" {
+		partitionByList = make([]interface{}, 0)
+		partitionByExpression = tableResource.PartitionByExpression
+	}
 	if err := d.Set("partition_by", partitionByList); err != nil {
 		return diag.FromErr(fmt.Errorf("setting partition_by: %v", err))
 	}
+	if err := d.Set("partition_by_expression", partitionByExpression); err != nil {
+		return diag.FromErr(fmt.Errorf("setting partition_by_expression: %v", err))
+	}
+	if err := d.Set("primary_key", tableResource.PrimaryKey); err != nil {
+		return diag.FromErr(fmt.Errorf("setting primary_key: %v", err))
+	}
+	if err := d.Set("sample_by", tableResource.SampleBy); err != nil {
+		return diag.FromErr(fmt.Errorf("setting sample_by: %v", err))
+	}
+	if err := d.Set("ttl", tableResource.TTLToResource()); err != nil {
+		return diag.FromErr(fmt.Errorf("setting ttl: %v", err))
+	}
+	// MergeTree tables always report the default index granularity, it is only kept when configured
+	if _, ok := d.Get("settings").(map[string]interface{})["index_granularity"]; !ok && tableResource.Settings["index_granularity"] == "8192" {
+		delete(tableResource.Settings, "index_granularity")
+	}
+	if err := d.Set("settings", tableResource.Settings); err != nil {
+		return diag.FromErr(fmt.Errorf("setting settings: %v", err))
+	}
 	if err := d.Set("column", tableResource.Columns); err != nil {
 		return diag.FromErr(fmt.Errorf("setting column: %v", err))
 	}
 	if err := d.Set("comment", tableResource.Comment); err != nil {
 		return diag.FromErr(fmt.Errorf("setting comment: %v", err))
 	}
+	if err := d.Set("sorting_key", tableResource.SortingKey); err != nil {
+		return diag.FromErr(fmt.Errorf("setting sorting_key: %v", err))
+	}
+	if err := d.Set("partition_key", tableResource.PartitionKey); err != nil {
+		return diag.FromErr(fmt.Errorf("setting partition_key: %v", err))
+	}
+	if err := d.Set("storage_policy", tableResource.StoragePolicy); err != nil {
+		return diag.FromErr(fmt.Errorf("setting storage_policy: %v", err))
+	}
+	if err := d.Set("total_rows", int(tableResource.TotalRows)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting total_rows: %v", err))
+	}
+	if err := d.Set("total_bytes", int(tableResource.TotalBytes)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting total_bytes: %v", err))
+	}
+	if err := d.Set("uuid", tableResource.UUID); err != nil {
+		return diag.FromErr(fmt.Errorf("setting uuid: %v", err))
+	}
 
-	d.SetId(tableResource.Cluster + ":" + database + ":" + tableName)
+	d.SetId(cluster + ":" + database + ":" + tableName)
 
 	return diags
 }
 
+func resourceTableImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "database", "table")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("database", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting database: %v", err)
+	}
+	if err := d.Set("name", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	if err := d.Set("detect_column_renames", false); err != nil {
+		return nil, fmt.Errorf("setting detect_column_renames: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -202,7 +549,7 @@ func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta any)
 	if commentRaw != nil {
 		commentStr = commentRaw.(string)
 	}
-	tableResource.Comment = common.GetComment(commentStr, tableResource.Cluster)
+	tableResource.Comment = commentStr
 	
 	columnRaw := d.Get("column")
 	if columnRaw != nil {
@@ -215,16 +562,14 @@ func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta any)
 		tableResource.Columns = []interface{}{}
 	}
 	
-	engineParamsRaw := d.Get("engine_params")
-	if engineParamsRaw != nil {
-		if engineParamsList, ok := engineParamsRaw.([]interface{}); ok {
-			tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(engineParamsList)
-		} else {
-			tableResource.EngineParams = []string{}
-		}
+	tableResource.SetDistributed(d.Get("distributed").([]interface{}))
+	if tableResource.Distributed != nil {
+		tableResource.EngineParams = tableResource.Distributed.EngineParams()
 	} else {
-		tableResource.EngineParams = []string{}
+		tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{}))
 	}
+	tableResource.SetReplication(d.Get("replication").([]interface{}))
+	tableResource.AddReplicationParams(client.ReplicationPathTemplate)
 	
 	orderByRaw := d.Get("order_by")
 	if orderByRaw != nil {
@@ -247,14 +592,19 @@ func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta any)
 	} else {
 		tableResource.PartitionBy = []PartitionByResource{}
 	}
+	tableResource.PartitionByExpression = d.Get("partition_by_expression").(string)
+
+	tableResource.PrimaryKey = common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{}))
+	tableResource.SampleBy = d.Get("sample_by").(string)
+	tableResource.SetTTL(d.Get("ttl").([]interface{}))
+	tableResource.SetSettings(d.Get("settings").(map[string]interface{}))
 
 	if tableResource.Cluster == "" {
 		tableResource.Cluster = client.DefaultCluster
 	}
 
-	tableResource.Validate(diags)
-	if diags.HasError() {
-		return diags
+	if validationDiags := tableResource.Validate(); validationDiags.HasError() {
+		return validationDiags
 	}
 
 	query := buildCreateOnClusterSentence(tableResource)
@@ -273,7 +623,65 @@ func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta any)
 	client := meta.(*common.ApiClient)
 	conn := client.ClickhouseConnection
 	chTableService := CHTableService{CHConnection: conn}
-	if d.HasChange("comment") {
+	if d.HasChange("column") {
+		tableResource := TableResource{}
+		tableResource.Database = d.Get("database").(string)
+		tableResource.Name = d.Get("name").(string)
+		tableResource.Cluster = d.Get("cluster").(string)
+		if tableResource.Cluster == "" {
+			tableResource.Cluster = client.DefaultCluster
+		}
+
+		oldColumns, newColumns := d.GetChange("column")
+		diff := DiffColumns(columnsResourceList(oldColumns.([]interface{})), columnsResourceList(newColumns.([]interface{})), d.Get("detect_column_renames").(bool))
+		err := chTableService.AlterTableColumns(ctx, tableResource, diff)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("updating table columns: %v", err))
+		}
+	}
+	if d.HasChange("ttl") {
+		tableResource := TableResource{}
+		tableResource.Database = d.Get("database").(string)
+		tableResource.Name = d.Get("name").(string)
+		tableResource.Cluster = d.Get("cluster").(string)
+		if tableResource.Cluster == "" {
+			tableResource.Cluster = client.DefaultCluster
+		}
+		tableResource.SetTTL(d.Get("ttl").([]interface{}))
+
+		err := chTableService.ModifyTableTTL(ctx, tableResource)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("updating table TTL: %v", err))
+		}
+	}
+	if d.HasChange("settings") {
+		tableResource := TableResource{}
+		tableResource.Database = d.Get("database").(string)
+		tableResource.Name = d.Get("name").(string)
+		tableResource.Cluster = d.Get("cluster").(string)
+		if tableResource.Cluster == "" {
+			tableResource.Cluster = client.DefaultCluster
+		}
+		oldSettings, newSettings := d.GetChange("settings")
+		tableResource.SetSettings(newSettings.(map[string]interface{}))
+		oldTableResource := TableResource{}
+		oldTableResource.SetSettings(oldSettings.(map[string]interface{}))
+
+		err := chTableService.ModifyTableSettings(ctx, tableResource, oldTableResource.Settings)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("updating table settings: %v", err))
+		}
+	}
+	// Tables created by older provider versions keep the comment in a json, decoded on read, it is rewritten verbatim
+	legacyComment := false
+	if !d.HasChange("comment") {
+		chTable, err := chTableService.GetTable(ctx, d.Get("database").(string), d.Get("name").(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("reading table comment: %v", err))
+		}
+		_, _, legacyComment = common.ParseLegacyComment(chTable.Comment)
+	}
+	if d.HasChange("comment") || legacyComment {
 		tableResource := TableResource{}
 		tableResource.Database = d.Get("database").(string)
 		tableResource.Name = d.Get("name").(string)
@@ -287,7 +695,7 @@ func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta any)
 		if commentRaw != nil {
 			commentStr = commentRaw.(string)
 		}
-		tableResource.Comment = common.GetComment(commentStr, tableResource.Cluster)
+		tableResource.Comment = commentStr
 
 		err := chTableService.UpdateTableComment(ctx, tableResource, commentStr)
 		if err != nil {
@@ -297,6 +705,173 @@ func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta any)
 	return resourceTableRead(ctx, d, meta)
 }
 
+// columnsRequireReplacement tells if column changes touch sorting or partition key columns,
+// which ClickHouse doesn't allow to alter.
+func columnsRequireReplacement(ctx context.Context, d *schema.ResourceDiff, meta any) bool {
+	if d.Id() == "" || !d.HasChange("column") {
+		return false
+	}
+
+	oldColumns, newColumns := d.GetChange("column")
+	diff := DiffColumns(columnsResourceList(oldColumns.([]interface{})), columnsResourceList(newColumns.([]interface{})), d.Get("detect_column_renames").(bool))
+
+	// Keys that currently exist on the server
+	oldOrderBy, _ := d.GetChange("order_by")
+	oldPartitionBy, _ := d.GetChange("partition_by")
+	oldPartitionByExpression, _ := d.GetChange("partition_by_expression")
+	tableResource := TableResource{
+		OrderBy:               common.MapArrayInterfaceToArrayOfStrings(oldOrderBy.([]interface{})),
+		PartitionByExpression: oldPartitionByExpression.(string),
+		Engine:                d.Get("engine").(string),
+		EngineParams:          common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{})),
+	}
+	tableResource.SetPartitionBy(oldPartitionBy.([]interface{}))
+
+	return tableResource.RequiresReplacement(diff)
+}
+
+// settingsRequireReplacement tells if settings changes touch readonly settings
+func settingsRequireReplacement(ctx context.Context, d *schema.ResourceDiff, meta any) bool {
+	if d.Id() == "" || !d.HasChange("settings") {
+		return false
+	}
+
+	oldSettings, newSettings := d.GetChange("settings")
+	var oldResource, newResource TableResource
+	oldResource.SetSettings(oldSettings.(map[string]interface{}))
+	newResource.SetSettings(newSettings.(map[string]interface{}))
+	return readonlySettingsChanged(oldResource.Settings, newResource.Settings)
+}
+
+// validateEngineParams checks the engine parameters, taken from the distributed block when it is configured
+func validateEngineParams(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	tableResource := TableResource{Engine: d.Get("engine").(string)}
+	if isBlockConfigured(d.GetRawConfig(), "distributed") {
+		if tableResource.Engine != "Distributed" {
+			return fmt.Errorf("distributed block can only be used with Distributed engine, got %s", tableResource.Engine)
+		}
+		if !d.NewValueKnown("distributed") {
+			return nil
+		}
+		tableResource.SetDistributed(d.Get("distributed").([]interface{}))
+		if err := validateDistributedCluster(ctx, tableResource.Distributed.Cluster, meta); err != nil {
+			return err
+		}
+		tableResource.EngineParams = tableResource.Distributed.EngineParams()
+	} else {
+		if !d.NewValueKnown("engine_params") {
+			return nil
+		}
+		tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{}))
+	}
+	return tableResource.ValidateEngineParams()
+}
+
+// validateDistributedCluster checks the cluster exists, clusters given by macros are resolved by the server so they are not checked
+func validateDistributedCluster(ctx context.Context, cluster string, meta any) error {
+	client, ok := meta.(*common.ApiClient)
+	if !ok || cluster == "" || strings.Contains(cluster, "{") {
+		return nil
+	}
+	chTableService := CHTableService{CHConnection: client.ClickhouseConnection}
+	exists, err := chTableService.ClusterExists(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("checking distributed cluster: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("distributed cluster %q does not exist in system.clusters", cluster)
+	}
+	return nil
+}
+
+func isBlockConfigured(config cty.Value, key string) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	value := config.GetAttr(key)
+	return !value.IsNull() && (!value.IsKnown() || value.LengthInt() > 0)
+}
+
+func validateTTL(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	tableResource := TableResource{}
+	tableResource.SetTTL(d.Get("ttl").([]interface{}))
+	for i, rule := range tableResource.TTL {
+		switch rule.Action {
+		case "TO DISK", "TO VOLUME":
+			if rule.Target == "" {
+				return fmt.Errorf("ttl.%d: target is required for %s action", i, rule.Action)
+			}
+		case "GROUP BY":
+			if len(rule.GroupBy) == 0 {
+				return fmt.Errorf("ttl.%d: group_by is required for GROUP BY action", i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTable checks at plan time that the table keys and engine parameters reference declared columns.
+// Values that are not known yet are left out of the validation.
+func validateTable(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	tableResource := TableResource{Engine: d.Get("engine").(string)}
+	if d.NewValueKnown("column") {
+		tableResource.Columns = d.Get("column").([]interface{})
+	}
+	if d.NewValueKnown("order_by") {
+		tableResource.OrderBy = common.MapArrayInterfaceToArrayOfStrings(d.Get("order_by").([]interface{}))
+	}
+	if d.NewValueKnown("partition_by") {
+		tableResource.SetPartitionBy(d.Get("partition_by").([]interface{}))
+	}
+	if d.NewValueKnown("partition_by_expression") {
+		tableResource.PartitionByExpression = d.Get("partition_by_expression").(string)
+	}
+	if d.NewValueKnown("primary_key") {
+		tableResource.PrimaryKey = common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{}))
+	}
+	if d.NewValueKnown("sample_by") {
+		tableResource.SampleBy = d.Get("sample_by").(string)
+	}
+	if !isBlockConfigured(d.GetRawConfig(), "distributed") && d.NewValueKnown("engine_params") {
+		tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{}))
+	}
+	return common.DiagnosticsToError(tableResource.Validate())
+}
+
+func suppressEquivalentDataType(k, old, new string, d *schema.ResourceData) bool {
+	return DataTypesEquivalent(old, new)
+}
+
+func suppressEquivalentExpression(k, old, new string, d *schema.ResourceData) bool {
+	return NormalizeExpression(old) == NormalizeExpression(new)
+}
+
+// suppressExpandedReplicationPath compares the configured path with the one read back, where the provider macros are expanded
+func suppressExpandedReplicationPath(k, old, new string, d *schema.ResourceData) bool {
+	return old == expandReplicationPath(new, d.Get("database").(string), d.Get("name").(string))
+}
+
+// suppressShardingKey ignores the rand() sharding key added when only policy_name is given
+func suppressShardingKey(k, old, new string, d *schema.ResourceData) bool {
+	if new == "" && NormalizeExpression(old) == "rand()" && d.Get("distributed.0.policy_name").(string) != "" {
+		return true
+	}
+	return suppressEquivalentExpression(k, old, new, d)
+}
+
+func suppressEquivalentPartitionKey(k, old, new string, d *schema.ResourceData) bool {
+	oldKey, newKey := common.SplitTuple(NormalizeExpression(old)), common.SplitTuple(NormalizeExpression(new))
+	if len(oldKey) != len(newKey) {
+		return false
+	}
+	for i := range oldKey {
+		if oldKey[i] != newKey[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func resourceTableDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 	client := meta.(*common.ApiClient)
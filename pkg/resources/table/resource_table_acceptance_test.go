@@ -20,7 +20,7 @@ func TestAccResourceTable(t *testing.T) {
 		Providers: testutils.Provider(),
 		Steps: []resource.TestStep{
 			{
-				Config: tableConfigWithName(testResourceTableDatabaseName, testResourceTableTableName),
+				Config: tableConfigWithName(testResourceTableDatabaseName, testResourceTableTableName, ""),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(
 						"clickhouse_db.new_db_resource", "name", regexp.MustCompile("^"+testResourceTableDatabaseName)),
@@ -45,11 +45,24 @@ func TestAccResourceTable(t *testing.T) {
 					resource.TestCheckResourceAttr("clickhouse_table.table", "column.2.type", "DateTime"),
 				),
 			},
+			// ADD A COLUMN IN PLACE
+			{
+				Config: tableConfigWithName(testResourceTableDatabaseName, testResourceTableTableName, `
+		column {
+			name= "newCol"
+			type= "Nullable(String)"
+		}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_table.table", "column.#", "4"),
+					resource.TestCheckResourceAttr("clickhouse_table.table", "column.3.name", "newCol"),
+					resource.TestCheckResourceAttr("clickhouse_table.table", "column.3.type", "Nullable(String)"),
+				),
+			},
 		},
 	})
 }
 
-func tableConfigWithName(database string, tableName string) string {
+func tableConfigWithName(database string, tableName string, extraColumns string) string {
 	s := `
 	resource "clickhouse_db" "new_db_resource" {
 		name = "%_database_%"
@@ -73,7 +86,7 @@ func tableConfigWithName(database string, tableName string) string {
 		column {
 			name= "eventTime"
 			type= "DateTime"
-		}
+		}%_extraColumns_%
 		partition_by {
 			by = "eventTime"
 			partition_function = "toYYYYMM"
@@ -83,5 +96,6 @@ func tableConfigWithName(database string, tableName string) string {
 
 	s = strings.Replace(s, "%_database_%", database, -1)
 	s = strings.Replace(s, "%_tableName_%", tableName, -1)
+	s = strings.Replace(s, "%_extraColumns_%", extraColumns, -1)
 	return s
 }
@@ -1,10 +1,13 @@
 package resourcetable
 
 import (
+	"context"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -47,7 +50,7 @@ func TestResourceTableCreate_ExactTerragruntData(t *testing.T) {
 	tableResource.Name = resourceData.Get("name").(string)
 	tableResource.Columns = resourceData.Get("column").([]interface{})
 	tableResource.Engine = resourceData.Get("engine").(string)
-	tableResource.Comment = common.GetComment(resourceData.Get("comment").(string), tableResource.Cluster)
+	tableResource.Comment = resourceData.Get("comment").(string)
 	tableResource.EngineParams = common.MapArrayInterfaceToArrayOfStrings(resourceData.Get("engine_params").([]interface{}))
 
 	// Обрабатываем order_by
@@ -99,4 +102,293 @@ func TestResourceTableCreate_ExactTerragruntData(t *testing.T) {
 	}
 }
 
+func TestResourceTableImport_ParsesId(t *testing.T) {
+	resourceData := schema.TestResourceDataRaw(t, ResourceTable().Schema, map[string]interface{}{})
+	resourceData.SetId("bi_cluster:dm:v_bonus_operations")
 
+	imported, err := resourceTableImport(context.Background(), resourceData, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("expected 1 imported resource, got %d", len(imported))
+	}
+	if got := imported[0].Get("cluster").(string); got != "bi_cluster" {
+		t.Errorf("cluster = %q, want %q", got, "bi_cluster")
+	}
+	if got := imported[0].Get("database").(string); got != "dm" {
+		t.Errorf("database = %q, want %q", got, "dm")
+	}
+	if got := imported[0].Get("name").(string); got != "v_bonus_operations" {
+		t.Errorf("name = %q, want %q", got, "v_bonus_operations")
+	}
+
+	for _, id := range []string{"dm:v_bonus_operations", "bi_cluster:dm:", "a:b:c:d"} {
+		resourceData.SetId(id)
+		if _, err := resourceTableImport(context.Background(), resourceData, nil); err == nil {
+			t.Errorf("expected error for import id %q", id)
+		}
+	}
+}
+
+func TestCHTableToResource_ForeignTable(t *testing.T) {
+	chTable := CHTable{
+		Database:         "dm",
+		Name:             "events",
+		Engine:           "MergeTree",
+		EngineFull:       "MergeTree PARTITION BY toYYYYMM(eventTime) ORDER BY key SETTINGS index_granularity = 8192",
+		Comment:          "Events written by the ingestion service",
+		CreateTableQuery: "CREATE TABLE dm.events (`key` Int64, `eventTime` DateTime) ENGINE = MergeTree PARTITION BY toYYYYMM(eventTime) ORDER BY key SETTINGS index_granularity = 8192",
+		Columns: []CHColumn{
+			{Database: "dm", Table: "events", Name: "key", Type: "Int64"},
+			{Database: "dm", Table: "events", Name: "eventTime", Type: "DateTime"},
+		},
+	}
+
+	tableResource, diags := chTable.ToResource()
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if tableResource.Comment != chTable.Comment {
+		t.Errorf("comment = %q, want %q", tableResource.Comment, chTable.Comment)
+	}
+	if tableResource.Cluster != "" {
+		t.Errorf("cluster = %q, want empty", tableResource.Cluster)
+	}
+	if len(tableResource.EngineParams) != 0 {
+		t.Errorf("engine params = %v, want none", tableResource.EngineParams)
+	}
+	if len(tableResource.OrderBy) != 1 || tableResource.OrderBy[0] != "key" {
+		t.Errorf("order by = %v, want [key]", tableResource.OrderBy)
+	}
+	if len(tableResource.PartitionBy) != 1 || tableResource.PartitionBy[0].By != "eventTime" || tableResource.PartitionBy[0].PartitionFunction != "toYYYYMM" {
+		t.Errorf("partition by = %v, want toYYYYMM(eventTime)", tableResource.PartitionBy)
+	}
+	if len(tableResource.Columns) != 2 {
+		t.Errorf("columns = %v, want 2 columns", tableResource.Columns)
+	}
+}
+
+func TestCHTableToResource_SystemKeys(t *testing.T) {
+	totalRows := uint64(42)
+	chTable := CHTable{
+		Database:         "dm",
+		Name:             "events",
+		Engine:           "MergeTree",
+		CreateTableQuery: "CREATE TABLE dm.events (`key` Int64, `ts` DateTime) ENGINE = MergeTree PARTITION BY toYYYYMM(ts) ORDER BY key",
+		SortingKey:       "key, intHash32(key)",
+		PartitionKey:     "(toYYYYMMDD(ts), key)",
+		StoragePolicy:    "default",
+		TotalRows:        &totalRows,
+		UUID:             "5ba6f7d2-0d1e-4b52-9f6b-6a7d0e3c4f11",
+	}
+
+	tableResource, diags := chTable.ToResource()
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if want := []string{"key", "intHash32(key)"}; !reflect.DeepEqual(tableResource.OrderBy, want) {
+		t.Errorf("order by = %q, want %q", tableResource.OrderBy, want)
+	}
+	wantPartitionBy := []PartitionByResource{{By: "ts", PartitionFunction: "toYYYYMMDD"}, {By: "key"}}
+	if !reflect.DeepEqual(tableResource.PartitionBy, wantPartitionBy) {
+		t.Errorf("partition by = %+v, want %+v", tableResource.PartitionBy, wantPartitionBy)
+	}
+	if tableResource.TotalRows != 42 || tableResource.TotalBytes != 0 || tableResource.StoragePolicy != "default" || tableResource.UUID != chTable.UUID {
+		t.Errorf("computed values = %+v", tableResource)
+	}
+}
+
+func TestCHTableToResource_UnparsableCreateQuery(t *testing.T) {
+	chTable := CHTable{
+		Database:         "dm",
+		Name:             "events",
+		Engine:           "ReplacingMergeTree",
+		EngineFull:       "ReplacingMergeTree(version) ORDER BY key SETTINGS index_granularity = 4096",
+		CreateTableQuery: "CREATE TABLE dm.events (`key` Int64, `version` UInt64) ENGINE = ReplacingMergeTree(version) ORDER BY key ((",
+		SortingKey:       "key",
+		PartitionKey:     "toYYYYMM(ts)",
+		Columns: []CHColumn{
+			{Database: "dm", Table: "events", Name: "key", Type: "Int64"},
+			{Database: "dm", Table: "events", Name: "version", Type: "UInt64"},
+		},
+	}
+
+	tableResource, diags := chTable.ToResource()
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("diagnostics = %v, want a warning", diags)
+	}
+	if want := []string{"version"}; !reflect.DeepEqual(tableResource.EngineParams, want) {
+		t.Errorf("engine params = %q, want %q", tableResource.EngineParams, want)
+	}
+	if want := []string{"key"}; !reflect.DeepEqual(tableResource.OrderBy, want) {
+		t.Errorf("order by = %q, want %q", tableResource.OrderBy, want)
+	}
+	wantPartitionBy := []PartitionByResource{{By: "ts", PartitionFunction: "toYYYYMM"}}
+	if !reflect.DeepEqual(tableResource.PartitionBy, wantPartitionBy) {
+		t.Errorf("partition by = %+v, want %+v", tableResource.PartitionBy, wantPartitionBy)
+	}
+	if want := map[string]string{"index_granularity": "4096"}; !reflect.DeepEqual(tableResource.Settings, want) {
+		t.Errorf("settings = %v, want %v", tableResource.Settings, want)
+	}
+	if len(tableResource.Columns) != 2 {
+		t.Errorf("columns = %v, want 2 columns", tableResource.Columns)
+	}
+}
+
+func TestBuildCreateOnClusterSentence_PartitionByExpression(t *testing.T) {
+	tableResource := TableResource{
+		Database:              "dm",
+		Name:                  "events",
+		Engine:                "MergeTree",
+		OrderBy:               []string{"key"},
+		PartitionByExpression: "(toMonday(ts), intDiv(id, 1000000))",
+		PartitionBy:           []PartitionByResource{{By: "ts", PartitionFunction: "toYYYYMM"}},
+	}
+
+	got := buildCreateOnClusterSentence(tableResource)
+	if !strings.Contains(got, "PARTITION BY (toMonday(ts), intDiv(id, 1000000))") || strings.Contains(got, "toYYYYMM") {
+		t.Errorf("buildCreateOnClusterSentence() = %q, want the partition by expression", got)
+	}
+}
+
+func TestTableResourceValidate(t *testing.T) {
+	columns := []interface{}{
+		map[string]interface{}{"name": "key", "type": "Int64"},
+		map[string]interface{}{"name": "ts", "type": "DateTime"},
+		map[string]interface{}{"name": "version", "type": "UInt64"},
+	}
+
+	tests := []struct {
+		name          string
+		tableResource TableResource
+		want          []string
+	}{
+		{
+			name: "valid",
+			tableResource: TableResource{
+				Engine:       "ReplacingMergeTree",
+				EngineParams: []string{"version"},
+				OrderBy:      []string{"key", "intHash32(key)", "ts"},
+				PartitionBy:  []PartitionByResource{{By: "ts", PartitionFunction: "toYYYYMM"}},
+				PrimaryKey:   []string{"key", "intHash32(key)"},
+				SampleBy:     "intHash32(key)",
+			},
+		},
+		{
+			name: "unknown columns",
+			tableResource: TableResource{
+				Engine:                "ReplacingMergeTree",
+				EngineParams:          []string{"ver"},
+				OrderBy:               []string{"key", "cityHash64(missing)"},
+				PartitionBy:           []PartitionByResource{{By: "event_time"}},
+				PartitionByExpression: "toMonday(day)",
+			},
+			want: []string{
+				"order_by.1: cityHash64(missing) references missing, which is not a column",
+				"partition_by.0.by: event_time references event_time, which is not a column",
+				"partition_by_expression: toMonday(day) references day, which is not a column",
+				"engine_params.0: engine ReplacingMergeTree parameter ver references ver, which is not a column",
+			},
+		},
+		{
+			name: "primary key and sampling",
+			tableResource: TableResource{
+				Engine:     "MergeTree",
+				OrderBy:    []string{"key", "ts"},
+				PrimaryKey: []string{"ts"},
+				SampleBy:   "key",
+			},
+			want: []string{
+				"primary_key.0: primary key element ts must match order_by element 0",
+				"sample_by: sample by key must be one of the primary key elements",
+			},
+		},
+		{
+			name: "invalid expression",
+			tableResource: TableResource{
+				Engine:  "MergeTree",
+				OrderBy: []string{"toDate(ts"},
+			},
+			want: []string{"order_by.0: toDate(ts: unbalanced parentheses"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tableResource.Columns = columns
+			err := common.DiagnosticsToError(tt.tableResource.Validate())
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected errors %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSuppressShardingKey(t *testing.T) {
+	distributed := func(policyName string) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, ResourceTable().Schema, map[string]interface{}{
+			"distributed": []interface{}{map[string]interface{}{
+				"cluster":         "bi_cluster",
+				"remote_database": "dm",
+				"remote_table":    "events_local",
+				"policy_name":     policyName,
+			}},
+		})
+	}
+
+	tests := []struct {
+		name       string
+		policyName string
+		old        string
+		new        string
+		want       bool
+	}{
+		{"rand() added along with policy_name", "tiered", "rand()", "", true},
+		{"rand() without policy_name", "", "rand()", "", false},
+		{"equivalent expressions", "", "cityHash64(user_id)", "cityHash64(user_id)  ", true},
+		{"changed sharding key", "tiered", "rand()", "cityHash64(user_id)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressShardingKey("distributed.0.sharding_key", tt.old, tt.new, distributed(tt.policyName)); got != tt.want {
+				t.Errorf("suppressShardingKey(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppressExpandedReplicationPath(t *testing.T) {
+	resourceData := schema.TestResourceDataRaw(t, ResourceTable().Schema, map[string]interface{}{
+		"database": "dm",
+		"name":     "events",
+	})
+
+	tests := []struct {
+		old  string
+		new  string
+		want bool
+	}{
+		{"/clickhouse/tables/{shard}/dm/events", "/clickhouse/tables/{shard}/{database}/{table}", true},
+		{"/clickhouse/tables/{shard}/dm/events", "/clickhouse/tables/{shard}/dm/events", true},
+		{"/clickhouse/tables/{shard}/dm/events", "/clickhouse/tables/{shard}/{database}/events_v2", false},
+	}
+
+	for _, tt := range tests {
+		if got := suppressExpandedReplicationPath("replication.0.zoo_path", tt.old, tt.new, resourceData); got != tt.want {
+			t.Errorf("suppressExpandedReplicationPath(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
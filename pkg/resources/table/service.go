@@ -13,8 +13,8 @@ type CHTableService struct {
 }
 
 func (ts *CHTableService) GetDBTables(ctx context.Context, database string) ([]CHTable, error) {
-	query := fmt.Sprintf("SELECT database, name FROM system.tables where database = '%s'", database)
-	rows, err := (*ts.CHConnection).Query(ctx, query)
+	ctx = common.QueryParameters(ctx, map[string]string{"database": database})
+	rows, err := (*ts.CHConnection).Query(ctx, "SELECT database, name FROM system.tables where database = {database:String}")
 
 	if err != nil {
 		return nil, fmt.Errorf("reading tables from Clickhouse: %v", err)
@@ -34,8 +34,8 @@ func (ts *CHTableService) GetDBTables(ctx context.Context, database string) ([]C
 }
 
 func (ts *CHTableService) GetTable(ctx context.Context, database string, table string) (*CHTable, error) {
-	query := fmt.Sprintf("SELECT database, name, engine_full, engine, comment, create_table_query FROM system.tables where database = '%s' and name = '%s'", database, table)
-	row := (*ts.CHConnection).QueryRow(ctx, query)
+	query := "SELECT database, name, engine_full, engine, comment, create_table_query, sorting_key, partition_key, primary_key, sampling_key, storage_policy, total_rows, total_bytes, toString(uuid) AS uuid FROM system.tables where database = {database:String} and name = {table:String}"
+	row := (*ts.CHConnection).QueryRow(common.QueryParameters(ctx, map[string]string{"database": database, "table": table}), query)
 
 	if row.Err() != nil {
 		return nil, fmt.Errorf("reading table from Clickhouse: %v", row.Err())
@@ -55,13 +55,20 @@ func (ts *CHTableService) GetTable(ctx context.Context, database string, table s
 	return &chTable, nil
 }
 
+func (ts *CHTableService) ClusterExists(ctx context.Context, cluster string) (bool, error) {
+	ctx = common.QueryParameters(ctx, map[string]string{"cluster": cluster})
+	row := (*ts.CHConnection).QueryRow(ctx, "SELECT count() FROM system.clusters WHERE cluster = {cluster:String}")
+
+	var count uint64
+	if err := row.Scan(&count); err != nil {
+		return false, fmt.Errorf("reading cluster from Clickhouse: %v", err)
+	}
+	return count > 0, nil
+}
+
 func (ts *CHTableService) getTableColumns(ctx context.Context, database string, table string) ([]CHColumn, error) {
-	query := fmt.Sprintf(
-		"SELECT database, table, name, type FROM system.columns WHERE database = '%s' AND table = '%s'",
-		database,
-		table,
-	)
-	rows, err := (*ts.CHConnection).Query(ctx, query)
+	query := "SELECT database, table, name, type, default_kind, default_expression, compression_codec, comment FROM system.columns WHERE database = {database:String} AND table = {table:String} ORDER BY position"
+	rows, err := (*ts.CHConnection).Query(common.QueryParameters(ctx, map[string]string{"database": database, "table": table}), query)
 
 	if err != nil {
 		return nil, fmt.Errorf("reading columns from Clickhouse: %v", err)
@@ -87,11 +94,10 @@ func (ts *CHTableService) CreateTable(ctx context.Context, tableResource TableRe
 	}
 
 	if originalComment != "" {
-		commentQuery := fmt.Sprintf("ALTER TABLE %s.%s %s MODIFY COMMENT '%s'",
-			tableResource.Database,
-			tableResource.Name,
+		commentQuery := fmt.Sprintf("ALTER TABLE %s %s MODIFY COMMENT %s",
+			common.QuoteTableName(tableResource.Database, tableResource.Name),
 			common.GetClusterStatement(tableResource.Cluster),
-			tableResource.Comment)
+			common.QuoteString(tableResource.Comment))
 		err = (*ts.CHConnection).Exec(ctx, commentQuery)
 		if err != nil {
 			return fmt.Errorf("setting table comment: %v", err)
@@ -102,11 +108,10 @@ func (ts *CHTableService) CreateTable(ctx context.Context, tableResource TableRe
 }
 
 func (ts *CHTableService) UpdateTableComment(ctx context.Context, tableResource TableResource, originalComment string) error {
-	commentQuery := fmt.Sprintf("ALTER TABLE %s.%s %s MODIFY COMMENT '%s'",
-		tableResource.Database,
-		tableResource.Name,
+	commentQuery := fmt.Sprintf("ALTER TABLE %s %s MODIFY COMMENT %s",
+		common.QuoteTableName(tableResource.Database, tableResource.Name),
 		common.GetClusterStatement(tableResource.Cluster),
-		tableResource.Comment)
+		common.QuoteString(tableResource.Comment))
 	err := (*ts.CHConnection).Exec(ctx, commentQuery)
 	if err != nil {
 		return fmt.Errorf("updating table comment: %v", err)
@@ -114,8 +119,37 @@ func (ts *CHTableService) UpdateTableComment(ctx context.Context, tableResource
 	return nil
 }
 
+func (ts *CHTableService) AlterTableColumns(ctx context.Context, tableResource TableResource, diff ColumnsDiff) error {
+	for _, query := range buildAlterColumnsSentences(tableResource, diff) {
+		err := (*ts.CHConnection).Exec(ctx, query)
+		if err != nil {
+			return fmt.Errorf("altering table columns. SQL: %s, error: %v", query, err)
+		}
+	}
+	return nil
+}
+
+func (ts *CHTableService) ModifyTableTTL(ctx context.Context, tableResource TableResource) error {
+	query := buildModifyTTLSentence(tableResource)
+	err := (*ts.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("modifying table TTL. SQL: %s, error: %v", query, err)
+	}
+	return nil
+}
+
+func (ts *CHTableService) ModifyTableSettings(ctx context.Context, tableResource TableResource, oldSettings map[string]string) error {
+	for _, query := range buildModifySettingsSentences(tableResource, oldSettings) {
+		err := (*ts.CHConnection).Exec(ctx, query)
+		if err != nil {
+			return fmt.Errorf("modifying table settings. SQL: %s, error: %v", query, err)
+		}
+	}
+	return nil
+}
+
 func (ts *CHTableService) DeleteTable(ctx context.Context, tableResource TableResource) error {
-	query := fmt.Sprintf("DROP TABLE %s.%s %s", tableResource.Database, tableResource.Name, common.GetClusterStatement(tableResource.Cluster))
+	query := fmt.Sprintf("DROP TABLE %s %s", common.QuoteTableName(tableResource.Database, tableResource.Name), common.GetClusterStatement(tableResource.Cluster))
 	err := (*ts.CHConnection).Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("deleting Clickhouse table: %v", err)
@@ -133,10 +167,9 @@ func (ts *CHTableService) CreatePostgreSQLTable(ctx context.Context, tableResour
 	}
 
 	if originalComment != "" {
-		commentQuery := fmt.Sprintf("ALTER TABLE %s.%s MODIFY COMMENT '%s'",
-			tableResource.Database,
-			tableResource.Name,
-			tableResource.Comment)
+		commentQuery := fmt.Sprintf("ALTER TABLE %s MODIFY COMMENT %s",
+			common.QuoteTableName(tableResource.Database, tableResource.Name),
+			common.QuoteString(tableResource.Comment))
 		err = (*ts.CHConnection).Exec(ctx, commentQuery)
 		if err != nil {
 			return fmt.Errorf("setting PostgreSQL table comment: %v", err)
@@ -147,10 +180,9 @@ func (ts *CHTableService) CreatePostgreSQLTable(ctx context.Context, tableResour
 }
 
 func (ts *CHTableService) UpdatePostgreSQLTableComment(ctx context.Context, tableResource PostgreSQLTableResource, originalComment string) error {
-	commentQuery := fmt.Sprintf("ALTER TABLE %s.%s MODIFY COMMENT '%s'",
-		tableResource.Database,
-		tableResource.Name,
-		tableResource.Comment)
+	commentQuery := fmt.Sprintf("ALTER TABLE %s MODIFY COMMENT %s",
+		common.QuoteTableName(tableResource.Database, tableResource.Name),
+		common.QuoteString(tableResource.Comment))
 	err := (*ts.CHConnection).Exec(ctx, commentQuery)
 	if err != nil {
 		return fmt.Errorf("updating PostgreSQL table comment: %v", err)
@@ -159,7 +191,7 @@ func (ts *CHTableService) UpdatePostgreSQLTableComment(ctx context.Context, tabl
 }
 
 func (ts *CHTableService) DeletePostgreSQLTable(ctx context.Context, tableResource PostgreSQLTableResource) error {
-	query := fmt.Sprintf("DROP TABLE %s.%s", tableResource.Database, tableResource.Name)
+	query := fmt.Sprintf("DROP TABLE %s", common.QuoteTableName(tableResource.Database, tableResource.Name))
 	err := (*ts.CHConnection).Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("deleting Clickhouse PostgreSQL table: %v", err)
@@ -2,18 +2,103 @@ package resourcetable
 
 import (
 	"fmt"
-	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"sort"
 	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
 )
 
+// buildColumnSentence renders a column definition, the type is omitted when it is empty, like in MODIFY COLUMN
+func buildColumnSentence(col ColumnResource) string {
+	parts := []string{common.QuoteIdentifier(col.Name)}
+	if col.Type != "" {
+		parts = append(parts, col.Type)
+	}
+	if defaultKind := col.GetDefaultKind(); defaultKind != "" {
+		parts = append(parts, defaultKind)
+		if col.DefaultExpression != "" {
+			parts = append(parts, col.DefaultExpression)
+		}
+	}
+	if col.Comment != "" {
+		parts = append(parts, fmt.Sprintf("COMMENT %s", common.QuoteString(col.Comment)))
+	}
+	if col.Codec != "" {
+		parts = append(parts, fmt.Sprintf("CODEC(%s)", col.Codec))
+	}
+	if col.TTL != "" {
+		parts = append(parts, fmt.Sprintf("TTL %s", col.TTL))
+	}
+	return strings.Join(parts, " ")
+}
+
+// removedColumnProperties returns the properties set on the old column definition that are not present anymore,
+// as MODIFY COLUMN keeps the ones not given
+func removedColumnProperties(modification ColumnModification) []string {
+	var properties []string
+	if from, to := modification.From.GetDefaultKind(), modification.To.GetDefaultKind(); from != "" && from != "EPHEMERAL" && to == "" {
+		properties = append(properties, from)
+	}
+	if modification.From.Comment != "" && modification.To.Comment == "" {
+		properties = append(properties, "COMMENT")
+	}
+	if modification.From.Codec != "" && modification.To.Codec == "" {
+		properties = append(properties, "CODEC")
+	}
+	if modification.From.TTL != "" && modification.To.TTL == "" {
+		properties = append(properties, "TTL")
+	}
+	return properties
+}
+
 func buildColumnsSentence(cols []ColumnResource) []string {
 	outColumn := make([]string, 0)
 	for _, col := range cols {
-		outColumn = append(outColumn, fmt.Sprintf("%s %s", col.Name, col.Type))
+		outColumn = append(outColumn, buildColumnSentence(col))
 	}
 	return outColumn
 }
 
+func buildColumnPositionSentence(after string) string {
+	if after == "" {
+		return "FIRST"
+	}
+	return fmt.Sprintf("AFTER %s", common.QuoteIdentifier(after))
+}
+
+// buildAlterColumnsSentences returns one ALTER TABLE statement per column operation, in the order they must be run
+func buildAlterColumnsSentences(resource TableResource, diff ColumnsDiff) []string {
+	alterTable := buildAlterTableSentence(resource)
+
+	var queries []string
+	for _, rename := range diff.Renamed {
+		queries = append(queries, fmt.Sprintf("%s RENAME COLUMN %s TO %s", alterTable, common.QuoteIdentifier(rename.From), common.QuoteIdentifier(rename.To)))
+	}
+	for _, column := range diff.Dropped {
+		queries = append(queries, fmt.Sprintf("%s DROP COLUMN %s", alterTable, common.QuoteIdentifier(column.Name)))
+	}
+	for _, modification := range diff.Modified {
+		for _, property := range removedColumnProperties(modification) {
+			queries = append(queries, fmt.Sprintf("%s MODIFY COLUMN %s REMOVE %s", alterTable, common.QuoteIdentifier(modification.To.Name), property))
+		}
+		// The type is only given when it changes, so metadata changes are allowed on key columns too
+		column := modification.To
+		if DataTypesEquivalent(modification.From.Type, column.Type) {
+			column.Type = ""
+		}
+		if columnSentence := buildColumnSentence(column); columnSentence != common.QuoteIdentifier(column.Name) {
+			queries = append(queries, fmt.Sprintf("%s MODIFY COLUMN %s", alterTable, columnSentence))
+		}
+	}
+	for _, position := range diff.Added {
+		queries = append(queries, fmt.Sprintf("%s ADD COLUMN %s %s", alterTable, buildColumnSentence(position.Column), buildColumnPositionSentence(position.After)))
+	}
+	for _, position := range diff.Moved {
+		queries = append(queries, fmt.Sprintf("%s MODIFY COLUMN %s %s", alterTable, buildColumnSentence(position.Column), buildColumnPositionSentence(position.After)))
+	}
+	return queries
+}
+
 func buildPartitionBySentence(partitionBy []PartitionByResource) string {
 	if len(partitionBy) > 0 {
 		partitionBySentenceItems := make([]string, 0)
@@ -24,7 +109,10 @@ func buildPartitionBySentence(partitionBy []PartitionByResource) string {
 				partitionBySentenceItems = append(partitionBySentenceItems, fmt.Sprintf("%v(%v)", partitionByItem.PartitionFunction, partitionByItem.By))
 			}
 		}
-		return fmt.Sprintf("PARTITION BY %v", strings.Join(partitionBySentenceItems, ", "))
+		if len(partitionBySentenceItems) > 1 {
+			return fmt.Sprintf("PARTITION BY (%v)", strings.Join(partitionBySentenceItems, ", "))
+		}
+		return fmt.Sprintf("PARTITION BY %v", partitionBySentenceItems[0])
 	}
 	return ""
 }
@@ -40,11 +128,107 @@ func buildOrderBySentence(orderBy []string) string {
 	return ""
 }
 
+func buildPrimaryKeySentence(primaryKey []string) string {
+	if len(primaryKey) > 1 {
+		return fmt.Sprintf("PRIMARY KEY (%v)", strings.Join(primaryKey, ", "))
+	}
+	return fmt.Sprintf("PRIMARY KEY %v", strings.Join(primaryKey, ", "))
+}
+
+func buildTTLSentence(ttl []TTLResource) string {
+	rules := make([]string, 0)
+	for _, rule := range ttl {
+		ruleParts := []string{rule.Expression}
+		switch rule.Action {
+		case "TO DISK", "TO VOLUME":
+			ruleParts = append(ruleParts, fmt.Sprintf("%s %s", rule.Action, common.QuoteString(rule.Target)))
+		}
+		if rule.Where != "" {
+			ruleParts = append(ruleParts, fmt.Sprintf("WHERE %s", rule.Where))
+		}
+		if rule.Action == "GROUP BY" {
+			ruleParts = append(ruleParts, fmt.Sprintf("GROUP BY %s", strings.Join(rule.GroupBy, ", ")))
+			if len(rule.Set) > 0 {
+				var assignments []string
+				for _, column := range sortedKeys(rule.Set) {
+					assignments = append(assignments, fmt.Sprintf("%s = %s", column, rule.Set[column]))
+				}
+				ruleParts = append(ruleParts, fmt.Sprintf("SET %s", strings.Join(assignments, ", ")))
+			}
+		}
+		rules = append(rules, strings.Join(ruleParts, " "))
+	}
+	return strings.Join(rules, ", ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func buildAlterTableSentence(resource TableResource) string {
+	alterTable := fmt.Sprintf("ALTER TABLE %s", common.QuoteTableName(resource.Database, resource.Name))
+	if resource.Cluster != "" {
+		alterTable = fmt.Sprintf("%s %s", alterTable, common.GetClusterStatement(resource.Cluster))
+	}
+	return alterTable
+}
+
+func buildModifyTTLSentence(resource TableResource) string {
+	if len(resource.TTL) == 0 {
+		return fmt.Sprintf("%s REMOVE TTL", buildAlterTableSentence(resource))
+	}
+	return fmt.Sprintf("%s MODIFY TTL %s", buildAlterTableSentence(resource), buildTTLSentence(resource.TTL))
+}
+
+// readonlySettings are the MergeTree settings ClickHouse doesn't allow to alter, the table has to be recreated
+var readonlySettings = []string{"index_granularity", "index_granularity_bytes", "enable_mixed_granularity_parts"}
+
+// readonlySettingsChanged tells if a readonly setting is added, removed or changed from oldSettings to newSettings
+func readonlySettingsChanged(oldSettings map[string]string, newSettings map[string]string) bool {
+	for _, name := range readonlySettings {
+		oldValue, oldOk := oldSettings[name]
+		newValue, newOk := newSettings[name]
+		if oldOk != newOk || oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
+
+// buildModifySettingsSentences returns the statements to change and reset settings from oldSettings to the resource ones
+func buildModifySettingsSentences(resource TableResource, oldSettings map[string]string) []string {
+	var queries []string
+	changed := make(map[string]string)
+	for name, value := range resource.Settings {
+		if oldValue, ok := oldSettings[name]; !ok || oldValue != value {
+			changed[name] = value
+		}
+	}
+	if len(changed) > 0 {
+		queries = append(queries, fmt.Sprintf("%s MODIFY SETTING %s", buildAlterTableSentence(resource), common.BuildSettingsSentence(changed)))
+	}
+	var removed []string
+	for _, name := range sortedKeys(oldSettings) {
+		if _, ok := resource.Settings[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		queries = append(queries, fmt.Sprintf("%s RESET SETTING %s", buildAlterTableSentence(resource), strings.Join(removed, ", ")))
+	}
+	return queries
+}
+
 func buildCreateOnClusterSentence(resource TableResource) (query string) {
-	parts := []string{fmt.Sprintf("CREATE TABLE %s.%s", resource.Database, resource.Name)}	
+	parts := []string{fmt.Sprintf("CREATE TABLE %s", common.QuoteTableName(resource.Database, resource.Name))}
 	if resource.Cluster != "" {
 		parts = append(parts, common.GetClusterStatement(resource.Cluster))
-	}	
+	}
 	if len(resource.Columns) > 0 {
 		columnsList := buildColumnsSentence(resource.GetColumnsResourceList())
 		parts = append(parts, "("+strings.Join(columnsList, ", ")+")")
@@ -59,13 +243,23 @@ func buildCreateOnClusterSentence(resource TableResource) (query string) {
 	if len(resource.OrderBy) > 0 {
 		parts = append(parts, buildOrderBySentence(resource.OrderBy))
 	}
-	if len(resource.PartitionBy) > 0 {
+	if resource.PartitionByExpression != "" {
+		parts = append(parts, fmt.Sprintf("PARTITION BY %s", resource.PartitionByExpression))
+	} else if len(resource.PartitionBy) > 0 {
 		parts = append(parts, buildPartitionBySentence(resource.PartitionBy))
 	}
 
-	// Добавляем SETTINGS по умолчанию для MergeTree движков
-	if strings.Contains(resource.Engine, "MergeTree") {
-		parts = append(parts, "SETTINGS index_granularity = 8192")
+	if len(resource.PrimaryKey) > 0 {
+		parts = append(parts, buildPrimaryKeySentence(resource.PrimaryKey))
+	}
+	if resource.SampleBy != "" {
+		parts = append(parts, fmt.Sprintf("SAMPLE BY %s", resource.SampleBy))
+	}
+	if len(resource.TTL) > 0 {
+		parts = append(parts, fmt.Sprintf("TTL %s", buildTTLSentence(resource.TTL)))
+	}
+	if len(resource.Settings) > 0 {
+		parts = append(parts, fmt.Sprintf("SETTINGS %s", common.BuildSettingsSentence(resource.Settings)))
 	}
 
 	return strings.Join(parts, " ")
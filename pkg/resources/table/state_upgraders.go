@@ -0,0 +1,13 @@
+package resourcetable
+
+import (
+	"context"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+// upgradeTableStateV0 migrates tables created while comments were stored as {"comment":"...","cluster":"..."}.
+// Upgraders only rewrite the state, the comment on the server is rewritten verbatim by the next update.
+func upgradeTableStateV0(ctx context.Context, rawState map[string]interface{}, meta any) (map[string]interface{}, error) {
+	return common.UpgradeLegacyCommentState(rawState), nil
+}
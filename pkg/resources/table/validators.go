@@ -26,57 +26,48 @@ func ValidatePartitionBy(inValue any, p hashicorpcty.Path) diag.Diagnostics {
 	return diags
 }
 
-func ValidateType(inValue any, p hashicorpcty.Path) diag.Diagnostics {
-	value := inValue.(string)
-	baseType := value
-	if len(value) > 9 && value[:9] == "Nullable(" {
-		parenCount := 0
-		endIdx := -1
-		// Ищем закрывающую скобку для Nullable, учитывая вложенные скобки
-		for i := 9; i < len(value); i++ {
-			if value[i] == '(' {
-				parenCount++
-			} else if value[i] == ')' {
-				if parenCount == 0 {
-					// Нашли закрывающую скобку для Nullable
-					endIdx = i
-					break
-				}
-				parenCount--
-			}
-		}
-		if endIdx >= 0 {
-			baseType = value[9:endIdx]
+func ValidateDefaultKind(inValue any, p hashicorpcty.Path) diag.Diagnostics {
+	validate := v.New()
+	value := strings.ToUpper(inValue.(string))
+	allowedDefaultKinds := "DEFAULT MATERIALIZED ALIAS EPHEMERAL"
+	validation := fmt.Sprintf("oneof=%v", allowedDefaultKinds)
+	var diags diag.Diagnostics
+	if validate.Var(value, validation) != nil {
+		diag := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "wrong value",
+			Detail:   fmt.Sprintf("%q is not %q", value, allowedDefaultKinds),
 		}
+		diags = append(diags, diag)
 	}
-	simpleType := baseType
-	// Извлекаем базовый тип, убирая параметры в скобках (например, Decimal(10, 2) -> Decimal)
-	if idx := strings.Index(baseType, "("); idx > 0 {
-		simpleType = baseType[:idx]
-	}
-
-	allowedTypes := []string{
-		"UInt8", "UInt16", "UInt32", "UInt64", "UInt128", "UInt256",
-		"Int8", "Int16", "Int32", "Int64", "Int128", "Int256",
-		"Float32", "Float64",
-		"Bool", "String", "UUID", "Date", "Date32", "DateTime", "DateTime64",
-		"LowCardinality", "JSON", "Decimal",
-	}
+	return diags
+}
 
-	found := false
-	for _, allowedType := range allowedTypes {
-		if simpleType == allowedType {
-			found = true
-			break
+func ValidateTTLAction(inValue any, p hashicorpcty.Path) diag.Diagnostics {
+	value := inValue.(string)
+	allowedActions := []string{"DELETE", "TO DISK", "TO VOLUME", "GROUP BY"}
+	var diags diag.Diagnostics
+	for _, allowedAction := range allowedActions {
+		if value == allowedAction {
+			return diags
 		}
 	}
+	diags = append(diags, diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "wrong value",
+		Detail:   fmt.Sprintf("%q is not one of %q", value, allowedActions),
+	})
+	return diags
+}
 
+func ValidateType(inValue any, p hashicorpcty.Path) diag.Diagnostics {
+	value := inValue.(string)
 	var diags diag.Diagnostics
-	if !found {
+	if _, err := ParseDataType(value); err != nil {
 		diag := diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "wrong value",
-			Detail:   fmt.Sprintf("%q is not a valid type. Allowed types: %v", value, allowedTypes),
+			Detail:   fmt.Sprintf("%q is not a valid type: %v", value, err),
 		}
 		diags = append(diags, diag)
 	}
@@ -86,16 +77,14 @@ func ValidateType(inValue any, p hashicorpcty.Path) diag.Diagnostics {
 func ValidateOnClusterEngine(inValue any, p hashicorpcty.Path) diag.Diagnostics {
 	validate := v.New()
 	value := inValue.(string)
-	mergeTreeTypes := "ReplacingMergeTree"
-	replicatedTypes := "ReplicatedMergeTree"
-	distributedTypes := "Distributed"
-	validation := fmt.Sprintf("oneof=%v %v %v", replicatedTypes, distributedTypes, mergeTreeTypes)
+	supportedEngines := strings.Join(SupportedEngines(), " ")
+	validation := fmt.Sprintf("oneof=%v", supportedEngines)
 	var diags diag.Diagnostics
 	if validate.Var(value, validation) != nil {
 		diag := diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "wrong value",
-			Detail:   fmt.Sprintf("%q is not %q %q %q", value, replicatedTypes, distributedTypes, mergeTreeTypes),
+			Detail:   fmt.Sprintf("%q is not %q", value, supportedEngines),
 		}
 		diags = append(diags, diag)
 	}
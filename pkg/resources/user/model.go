@@ -6,19 +6,22 @@ import (
 )
 
 type CHUser struct {
-	Name  string   `ch:"name"`
-	Roles []string `ch:"default_roles_list"`
+	Name             string   `ch:"name"`
+	Roles            []string `ch:"default_roles_list"`
+	SettingsProfiles []string
 }
 
 type UserResource struct {
-	Name     string
-	Password string
-	Roles    *schema.Set
+	Name             string
+	Password         string
+	Roles            *schema.Set // nil when the roles of the user are not managed
+	SettingsProfiles []string
 }
 
 func (u *CHUser) ToUserResource() *UserResource {
 	return &UserResource{
-		Name:  u.Name,
-		Roles: common.StringListToSet(u.Roles),
+		Name:             u.Name,
+		Roles:            common.StringListToSet(u.Roles),
+		SettingsProfiles: u.SettingsProfiles,
 	}
 }
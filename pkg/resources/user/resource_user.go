@@ -15,6 +15,9 @@ func ResourceUser() *schema.Resource {
 		UpdateContext: resourceUserUpdate,
 		ReadContext:   resourceUserRead,
 		DeleteContext: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Description: "User name",
@@ -22,14 +25,24 @@ func ResourceUser() *schema.Resource {
 				Required:    true,
 			},
 			"password": {
-				Description: "User password",
+				Description: "User password. It can't be read back from Clickhouse, so it is unknown after an import: the plan shows it as changed and the next apply sets the configured value",
 				Type:        schema.TypeString,
 				Required:    true,
+				Sensitive:   true,
 			},
 			"roles": {
-				Description: "User role",
+				Description: "Roles granted to the user as default roles. They are only managed when set, leave it unset when the roles of the user are granted with `clickhouse_role_grant`",
 				Type:        schema.TypeSet,
 				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"settings_profiles": {
+				Description: "Settings profiles assigned to the user, later profiles take precedence. It replaces any other setting of the user",
+				Type:        schema.TypeList,
+				Optional:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -50,6 +63,11 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource user read: %v", err))
 	}
+	if user == nil {
+		// User removed outside of terraform
+		d.SetId("")
+		return diags
+	}
 
 	if err := d.Set("name", user.Name); err != nil {
 		return diag.FromErr(err)
@@ -57,11 +75,26 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 	if err := d.Set("roles", &user.Roles); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("settings_profiles", user.SettingsProfiles); err != nil {
+		return diag.FromErr(err)
+	}
 	d.SetId(user.Name)
 
 	return diags
 }
 
+// Users are imported by name. Password is not stored in Clickhouse in plain text, so it is imported
+// as unknown, an empty value in the state, and the configured one is applied on the next update.
+func resourceUserImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if err := d.Set("name", d.Id()); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	if err := d.Set("password", ""); err != nil {
+		return nil, fmt.Errorf("setting password: %v", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -73,9 +106,10 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 	rolesSet := d.Get("roles").(*schema.Set)
 	chUserService := CHUserService{CHConnection: conn}
 	chUser, err := chUserService.CreateUser(ctx, UserResource{
-		Name:     userName,
-		Password: password,
-		Roles:    rolesSet,
+		Name:             userName,
+		Password:         password,
+		Roles:            rolesSet,
+		SettingsProfiles: common.MapArrayInterfaceToArrayOfStrings(d.Get("settings_profiles").([]interface{})),
 	})
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("resource user create: %v", err))
@@ -95,13 +129,18 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	planUserName := d.Get("name").(string)
 	planPassword := d.Get("password").(string)
-	planRoles := d.Get("roles").(*schema.Set)
+	// Roles are left untouched when they are not configured, they may be granted by clickhouse_role_grant
+	var planRoles *schema.Set
+	if !d.GetRawConfig().GetAttr("roles").IsNull() {
+		planRoles = d.Get("roles").(*schema.Set)
+	}
 
 	// After modify original role grants, we need to update default roles
 	chUser, err := chUserService.UpdateUser(ctx, UserResource{
-		Name:     planUserName,
-		Password: planPassword,
-		Roles:    planRoles,
+		Name:             planUserName,
+		Password:         planPassword,
+		Roles:            planRoles,
+		SettingsProfiles: common.MapArrayInterfaceToArrayOfStrings(d.Get("settings_profiles").([]interface{})),
 	}, d)
 	if err != nil {
 		return diag.FromErr(err)
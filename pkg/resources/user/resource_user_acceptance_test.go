@@ -102,6 +102,13 @@ func generateTestSteps() []resource.TestStep {
 			),
 		})
 	}
+	// Import the user in its last state, password can't be read back from the server
+	testSteps = append(testSteps, resource.TestStep{
+		ResourceName:            userResource,
+		ImportState:             true,
+		ImportStateVerify:       true,
+		ImportStateVerifyIgnore: []string{"password"},
+	})
 	return testSteps
 }
 
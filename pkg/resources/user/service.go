@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	resourcesettingsprofile "github.com/Fox052-byte/terraform-provider-clickhouse/pkg/resources/settingsprofile"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"strings"
 )
@@ -14,9 +15,8 @@ type CHUserService struct {
 }
 
 func (us *CHUserService) GetUser(ctx context.Context, userName string) (*CHUser, error) {
-	roleQuery := fmt.Sprintf("SELECT name, default_roles_list FROM system.users WHERE name = '%s'", userName)
-
-	rows, err := (*us.CHConnection).Query(ctx, roleQuery)
+	ctx = common.QueryParameters(ctx, map[string]string{"name": userName})
+	rows, err := (*us.CHConnection).Query(ctx, "SELECT name, default_roles_list FROM system.users WHERE name = {name:String}")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching user: %s", err)
 	}
@@ -29,23 +29,32 @@ func (us *CHUserService) GetUser(ctx context.Context, userName string) (*CHUser,
 		return nil, fmt.Errorf("error scanning user: %s", err)
 	}
 
+	chSettingsProfileService := resourcesettingsprofile.CHSettingsProfileService{CHConnection: us.CHConnection}
+	chUser.SettingsProfiles, err = chSettingsProfileService.GetAssignedProfiles(ctx, "user_name", userName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user settings profiles: %s", err)
+	}
+
 	return &chUser, nil
 }
 
 func (us *CHUserService) CreateUser(ctx context.Context, userPlan UserResource) (*CHUser, error) {
 	var rolesList []string
 
-	for _, role := range userPlan.Roles.List() {
-		rolesList = append(rolesList, role.(string))
+	if userPlan.Roles != nil {
+		rolesList = common.StringSetToList(userPlan.Roles)
 	}
 	query := fmt.Sprintf(
-		"CREATE USER %s IDENTIFIED WITH sha256_password BY '%s'",
-		userPlan.Name,
-		userPlan.Password,
+		"CREATE USER %s IDENTIFIED WITH sha256_password BY %s",
+		common.QuoteIdentifier(userPlan.Name),
+		common.QuoteString(userPlan.Password),
 	)
 
 	if len(rolesList) > 0 {
-		query = fmt.Sprintf("%s DEFAULT ROLE %s", query, strings.Join(rolesList, ","))
+		query = fmt.Sprintf("%s DEFAULT ROLE %s", query, strings.Join(common.QuoteIdentifiers(rolesList), ","))
+	}
+	if len(userPlan.SettingsProfiles) > 0 {
+		query = fmt.Sprintf("%s %s", query, resourcesettingsprofile.BuildProfilesClause(userPlan.SettingsProfiles))
 	}
 	err := (*us.CHConnection).Exec(ctx, query)
 	if err != nil {
@@ -67,7 +76,7 @@ func (us *CHUserService) UpdateUser(ctx context.Context, userPlan UserResource,
 
 	userNameHasChange := resourceData.HasChange("name")
 	userPasswordHasChange := resourceData.HasChange("password")
-	userRolesHasChange := resourceData.HasChange("roles")
+	userRolesHasChange := userPlan.Roles != nil && resourceData.HasChange("roles")
 
 	var grantRoles []string
 	var revokeRoles []string
@@ -92,14 +101,14 @@ func (us *CHUserService) UpdateUser(ctx context.Context, userPlan UserResource,
 	}
 
 	if len(grantRoles) > 0 {
-		err := conn.Exec(ctx, fmt.Sprintf("GRANT %s TO %s", strings.Join(grantRoles, ","), stateUserName))
+		err := conn.Exec(ctx, fmt.Sprintf("GRANT %s TO %s", strings.Join(common.QuoteIdentifiers(grantRoles), ","), common.QuoteIdentifier(stateUserName.(string))))
 		if err != nil {
 			return nil, fmt.Errorf("error granting roles to user: %s", err)
 		}
 	}
 
 	if len(revokeRoles) > 0 {
-		err := conn.Exec(ctx, fmt.Sprintf("REVOKE %s FROM %s", strings.Join(revokeRoles, ","), stateUserName))
+		err := conn.Exec(ctx, fmt.Sprintf("REVOKE %s FROM %s", strings.Join(common.QuoteIdentifiers(revokeRoles), ","), common.QuoteIdentifier(stateUserName.(string))))
 		if err != nil {
 			return nil, fmt.Errorf("error revoking roles from user: %s", err)
 		}
@@ -107,22 +116,36 @@ func (us *CHUserService) UpdateUser(ctx context.Context, userPlan UserResource,
 
 	var changeNameClause string
 	var changePasswordClause string
+	var changeSettingsClause string
 
 	if userNameHasChange {
-		changeNameClause = fmt.Sprintf(" RENAME TO %s", userPlan.Name)
+		changeNameClause = fmt.Sprintf(" RENAME TO %s", common.QuoteIdentifier(userPlan.Name))
 	}
 
 	if userPasswordHasChange {
-		changePasswordClause = fmt.Sprintf(" IDENTIFIED with sha256_password BY '%s'", userPlan.Password)
+		changePasswordClause = fmt.Sprintf(" IDENTIFIED with sha256_password BY %s", common.QuoteString(userPlan.Password))
+	}
+
+	if resourceData.HasChange("settings_profiles") {
+		changeSettingsClause = " " + resourcesettingsprofile.BuildProfilesClause(userPlan.SettingsProfiles)
 	}
 
 	// After modify original role grants, we need to update default roles
+	var changeDefaultRolesClause string
+	if userPlan.Roles != nil {
+		defaultRoles := "NONE"
+		if userPlan.Roles.Len() > 0 {
+			defaultRoles = strings.Join(common.QuoteIdentifiers(common.StringSetToList(userPlan.Roles)), ",")
+		}
+		changeDefaultRolesClause = " DEFAULT ROLE " + defaultRoles
+	}
 	query := fmt.Sprintf(
-		"ALTER USER %s%s%s DEFAULT ROLE %s",
-		stateUserName,
+		"ALTER USER %s%s%s%s%s",
+		common.QuoteIdentifier(stateUserName.(string)),
 		changeNameClause,
 		changePasswordClause,
-		strings.Join(common.StringSetToList(userPlan.Roles), ","),
+		changeDefaultRolesClause,
+		changeSettingsClause,
 	)
 	err = conn.Exec(ctx, query)
 	if err != nil {
@@ -133,5 +156,5 @@ func (us *CHUserService) UpdateUser(ctx context.Context, userPlan UserResource,
 }
 
 func (us *CHUserService) DeleteUser(ctx context.Context, name string) error {
-	return (*us.CHConnection).Exec(ctx, fmt.Sprintf("DROP USER %s", name))
+	return (*us.CHConnection).Exec(ctx, fmt.Sprintf("DROP USER %s", common.QuoteIdentifier(name)))
 }
@@ -0,0 +1,141 @@
+package resourceview
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+// currentUserDefiner is the DEFINER value resolved by the server to the user creating the view
+const currentUserDefiner = "CURRENT_USER"
+
+type CHView struct {
+	Database         string `ch:"database"`
+	Name             string `ch:"name"`
+	AsSelect         string `ch:"as_select"`
+	Comment          string `ch:"comment"`
+	CreateTableQuery string `ch:"create_table_query"`
+}
+
+// refreshRegex splits the REFRESH clause of refreshable materialized views,
+// e.g. EVERY 1 DAY OFFSET 2 HOUR RANDOMIZE FOR 1 HOUR DEPENDS ON db.events
+var refreshRegex = regexp.MustCompile(`(?is)^(EVERY|AFTER)\s+(.+?)(?:\s+OFFSET\s+(.+?))?(?:\s+RANDOMIZE\s+FOR\s+(.+?))?(?:\s+DEPENDS\s+ON\s+(.+?))?$`)
+
+type ViewResource struct {
+	Database    string
+	Name        string
+	Cluster     string
+	Query       string
+	Comment     string
+	SQLSecurity string
+	Definer     string
+	OrReplace   bool
+}
+
+func (v *CHView) ToResource() (*ViewResource, error) {
+	viewResource := ViewResource{
+		Database: v.Database,
+		Name:     v.Name,
+		Query:    v.AsSelect,
+		Comment:  v.Comment,
+	}
+	if v.CreateTableQuery == "" {
+		return &viewResource, nil
+	}
+
+	createQuery, err := common.ParseCreateQuery(v.CreateTableQuery)
+	if err != nil {
+		return nil, fmt.Errorf("parsing create query of view %s.%s: %v", v.Database, v.Name, err)
+	}
+	// as_select is only reported by recent servers
+	if viewResource.Query == "" {
+		viewResource.Query = createQuery.Select
+	}
+	viewResource.SQLSecurity = strings.ToUpper(createQuery.Clauses["SQL SECURITY"])
+	if definer, ok := createQuery.Clauses["DEFINER"]; ok {
+		definer = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(definer), "="))
+		viewResource.Definer = strings.Trim(definer, "`\"")
+	}
+	return &viewResource, nil
+}
+
+type MaterializedViewResource struct {
+	Database string
+	Name     string
+	Cluster  string
+	Query    string
+	Comment  string
+	// To is the database qualified target table, inner storage is used when empty
+	To           string
+	Engine       string
+	EngineParams []string
+	OrderBy      []string
+	PartitionBy  string
+	PrimaryKey   []string
+	Settings     map[string]string
+	Populate     bool
+	Refresh      *RefreshResource
+}
+
+type RefreshResource struct {
+	// Kind is EVERY or AFTER
+	Kind         string
+	Interval     string
+	Offset       string
+	RandomizeFor string
+	DependsOn    []string
+	Append       bool
+}
+
+func (v *CHView) ToMaterializedViewResource() (*MaterializedViewResource, error) {
+	createQuery, err := common.ParseCreateQuery(v.CreateTableQuery)
+	if err != nil {
+		return nil, fmt.Errorf("parsing create query of materialized view %s.%s: %v", v.Database, v.Name, err)
+	}
+
+	viewResource := MaterializedViewResource{
+		Database:    v.Database,
+		Name:        v.Name,
+		Query:       v.AsSelect,
+		Comment:     v.Comment,
+		To:          createQuery.To,
+		OrderBy:     createQuery.OrderBy,
+		PartitionBy: createQuery.Clauses["PARTITION BY"],
+		PrimaryKey:  createQuery.PrimaryKey,
+		Settings:    createQuery.Settings,
+	}
+	if viewResource.Query == "" {
+		viewResource.Query = createQuery.Select
+	}
+	if createQuery.Engine != nil {
+		viewResource.Engine = createQuery.Engine.Name
+		viewResource.EngineParams = createQuery.Engine.Params
+	}
+	if refresh, ok := createQuery.Clauses["REFRESH"]; ok {
+		viewResource.Refresh, err = parseRefresh(refresh)
+		if err != nil {
+			return nil, err
+		}
+		viewResource.Refresh.Append = createQuery.HasClause("APPEND")
+	}
+	return &viewResource, nil
+}
+
+func parseRefresh(refresh string) (*RefreshResource, error) {
+	parts := refreshRegex.FindStringSubmatch(strings.TrimSpace(refresh))
+	if parts == nil {
+		return nil, fmt.Errorf("unexpected refresh clause %q", refresh)
+	}
+	refreshResource := RefreshResource{
+		Kind:         strings.ToUpper(parts[1]),
+		Interval:     parts[2],
+		Offset:       parts[3],
+		RandomizeFor: parts[4],
+	}
+	if parts[5] != "" {
+		refreshResource.DependsOn = common.SplitTopLevel(parts[5])
+	}
+	return &refreshResource, nil
+}
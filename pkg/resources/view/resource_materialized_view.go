@@ -0,0 +1,397 @@
+package resourceview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceMaterializedView() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage materialized views, writing either to a `to` table or to an inner table defined by `engine`, and optionally refreshed periodically",
+
+		CreateContext: resourceMaterializedViewCreate,
+		ReadContext:   resourceMaterializedViewRead,
+		UpdateContext: resourceMaterializedViewUpdate,
+		DeleteContext: resourceMaterializedViewDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMaterializedViewImport,
+		},
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIf("query", materializedViewQueryRequiresReplacement),
+			customdiff.ForceNewIfChange("refresh", func(ctx context.Context, old, new, meta any) bool {
+				return len(old.([]interface{})) != len(new.([]interface{}))
+			}),
+		),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Description: "DB Name where the materialized view will bellow",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "Materialized view Name",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the materialized view is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"query": {
+				Description:      "SELECT query of the materialized view, modified in place with ALTER TABLE ... MODIFY QUERY. Changes recreate refreshable materialized views",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentQuery,
+			},
+			"comment": {
+				Description: "Materialized view comment, stored as given",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"to": {
+				Description:      "Database qualified table the materialized view writes to, e.g. `awesome_database.events_daily`",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"to", "engine"},
+				ConflictsWith:    []string{"populate"},
+				DiffSuppressFunc: suppressEquivalentQuery,
+			},
+			"engine": {
+				Description: "Engine of the inner table storing the materialized view rows, when there is no `to` table",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"engine_params": {
+				Description:  "Engine params of the inner table",
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"engine"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"order_by": {
+				Description:  "Order by columns of the inner table",
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"engine"},
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					DiffSuppressFunc: suppressEquivalentQuery,
+				},
+			},
+			"partition_by": {
+				Description:      "Partition key expression of the inner table, e.g. `toYYYYMM(event_date)`",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				RequiredWith:     []string{"engine"},
+				DiffSuppressFunc: suppressEquivalentQuery,
+			},
+			"primary_key": {
+				Description:  "Primary key columns of the inner table, a prefix of `order_by`",
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"engine"},
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					DiffSuppressFunc: suppressEquivalentQuery,
+				},
+			},
+			"settings": {
+				Description:      "Settings of the inner table",
+				Type:             schema.TypeMap,
+				Optional:         true,
+				ForceNew:         true,
+				RequiredWith:     []string{"engine"},
+				ValidateDiagFunc: common.ValidateSettingNames,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"populate": {
+				Description:   "Fill the inner table with the rows already in the source table on creation. It can't be used with `to` or `refresh`",
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ForceNew:      true,
+				ConflictsWith: []string{"refresh"},
+			},
+			"refresh": {
+				Description: "Refresh schedule making the materialized view refreshable, the query is run periodically instead of on inserts. Adding or removing it recreates the materialized view",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kind": {
+							Description:  "EVERY to refresh on a fixed schedule or AFTER to refresh a given time after the last refresh",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"EVERY", "AFTER"}, false),
+						},
+						"interval": {
+							Description:      "Refresh interval, e.g. `1 HOUR`",
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressEquivalentInterval,
+						},
+						"offset": {
+							Description:      "Offset of EVERY refreshes within the interval, e.g. `5 MINUTE`",
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressEquivalentInterval,
+						},
+						"randomize_for": {
+							Description:      "Random delay added to each refresh, e.g. `1 MINUTE`",
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressEquivalentInterval,
+						},
+						"depends_on": {
+							Description: "Database qualified refreshable materialized views that must be refreshed first",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								DiffSuppressFunc: suppressEquivalentQuery,
+							},
+						},
+						"append": {
+							Description: "Append the refreshed rows to the target table instead of replacing its content",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func materializedViewQueryRequiresReplacement(ctx context.Context, d *schema.ResourceDiff, meta any) bool {
+	old, new := d.GetChange("query")
+	return len(d.Get("refresh").([]interface{})) > 0 && common.NormalizeQuery(old.(string)) != common.NormalizeQuery(new.(string))
+}
+
+func resourceMaterializedViewRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	conn := client.ClickhouseConnection
+
+	database := d.Get("database").(string)
+	viewName := d.Get("name").(string)
+
+	chViewService := CHViewService{CHConnection: conn}
+	chView, err := chViewService.GetMaterializedView(ctx, database, viewName)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse materialized view: %v", err))
+	}
+	if chView == nil {
+		d.SetId("")
+		return diags
+	}
+
+	viewResource, err := chView.ToMaterializedViewResource()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("transforming Clickhouse materialized view to resource: %v", err))
+	}
+
+	if err := d.Set("database", viewResource.Database); err != nil {
+		return diag.FromErr(fmt.Errorf("setting database: %v", err))
+	}
+	if err := d.Set("name", viewResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("query", viewResource.Query); err != nil {
+		return diag.FromErr(fmt.Errorf("setting query: %v", err))
+	}
+	if err := d.Set("comment", viewResource.Comment); err != nil {
+		return diag.FromErr(fmt.Errorf("setting comment: %v", err))
+	}
+	if err := d.Set("to", viewResource.To); err != nil {
+		return diag.FromErr(fmt.Errorf("setting to: %v", err))
+	}
+	if err := d.Set("engine", viewResource.Engine); err != nil {
+		return diag.FromErr(fmt.Errorf("setting engine: %v", err))
+	}
+	if err := d.Set("engine_params", viewResource.EngineParams); err != nil {
+		return diag.FromErr(fmt.Errorf("setting engine_params: %v", err))
+	}
+	if err := d.Set("order_by", viewResource.OrderBy); err != nil {
+		return diag.FromErr(fmt.Errorf("setting order_by: %v", err))
+	}
+	if err := d.Set("partition_by", viewResource.PartitionBy); err != nil {
+		return diag.FromErr(fmt.Errorf("setting partition_by: %v", err))
+	}
+	if err := d.Set("primary_key", viewResource.PrimaryKey); err != nil {
+		return diag.FromErr(fmt.Errorf("setting primary_key: %v", err))
+	}
+	// The server reports the default index_granularity even when it was not set
+	if _, ok := d.Get("settings").(map[string]interface{})["index_granularity"]; !ok && viewResource.Settings["index_granularity"] == "8192" {
+		delete(viewResource.Settings, "index_granularity")
+	}
+	if err := d.Set("settings", viewResource.Settings); err != nil {
+		return diag.FromErr(fmt.Errorf("setting settings: %v", err))
+	}
+	if err := d.Set("refresh", refreshToList(viewResource.Refresh)); err != nil {
+		return diag.FromErr(fmt.Errorf("setting refresh: %v", err))
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + database + ":" + viewName)
+
+	return diags
+}
+
+func resourceMaterializedViewImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "database", "view")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("database", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting database: %v", err)
+	}
+	if err := d.Set("name", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	if err := d.Set("populate", false); err != nil {
+		return nil, fmt.Errorf("setting populate: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceMaterializedViewCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chViewService := CHViewService{CHConnection: client.ClickhouseConnection}
+
+	viewResource := materializedViewResourceFromData(d, client)
+	if err := chViewService.CreateMaterializedView(ctx, viewResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + viewResource.Database + ":" + viewResource.Name)
+
+	return resourceMaterializedViewRead(ctx, d, meta)
+}
+
+func resourceMaterializedViewUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chViewService := CHViewService{CHConnection: client.ClickhouseConnection}
+
+	viewResource := materializedViewResourceFromData(d, client)
+	if d.HasChange("query") {
+		if err := chViewService.ModifyMaterializedViewQuery(ctx, viewResource); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if d.HasChange("refresh") && viewResource.Refresh != nil {
+		if err := chViewService.ModifyMaterializedViewRefresh(ctx, viewResource); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if d.HasChange("comment") {
+		comment := ViewResource{Database: viewResource.Database, Name: viewResource.Name, Cluster: viewResource.Cluster, Comment: viewResource.Comment}
+		if err := chViewService.UpdateViewComment(ctx, comment); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceMaterializedViewRead(ctx, d, meta)
+}
+
+func resourceMaterializedViewDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chViewService := CHViewService{CHConnection: client.ClickhouseConnection}
+
+	viewResource := ViewResource{
+		Database: d.Get("database").(string),
+		Name:     d.Get("name").(string),
+		Cluster:  d.Get("cluster").(string),
+	}
+	if viewResource.Cluster == "" {
+		viewResource.Cluster = client.DefaultCluster
+	}
+	if err := chViewService.DeleteView(ctx, viewResource); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func materializedViewResourceFromData(d *schema.ResourceData, client *common.ApiClient) MaterializedViewResource {
+	viewResource := MaterializedViewResource{
+		Database:     d.Get("database").(string),
+		Name:         d.Get("name").(string),
+		Cluster:      d.Get("cluster").(string),
+		Query:        d.Get("query").(string),
+		Comment:      d.Get("comment").(string),
+		To:           d.Get("to").(string),
+		Engine:       d.Get("engine").(string),
+		EngineParams: common.MapArrayInterfaceToArrayOfStrings(d.Get("engine_params").([]interface{})),
+		OrderBy:      common.MapArrayInterfaceToArrayOfStrings(d.Get("order_by").([]interface{})),
+		PartitionBy:  d.Get("partition_by").(string),
+		PrimaryKey:   common.MapArrayInterfaceToArrayOfStrings(d.Get("primary_key").([]interface{})),
+		Settings:     map[string]string{},
+		Populate:     d.Get("populate").(bool),
+	}
+	if viewResource.Cluster == "" {
+		viewResource.Cluster = client.DefaultCluster
+	}
+	for name, value := range d.Get("settings").(map[string]interface{}) {
+		viewResource.Settings[name] = value.(string)
+	}
+	if refresh := d.Get("refresh").([]interface{}); len(refresh) > 0 && refresh[0] != nil {
+		refreshMap := refresh[0].(map[string]interface{})
+		viewResource.Refresh = &RefreshResource{
+			Kind:         refreshMap["kind"].(string),
+			Interval:     refreshMap["interval"].(string),
+			Offset:       refreshMap["offset"].(string),
+			RandomizeFor: refreshMap["randomize_for"].(string),
+			DependsOn:    common.MapArrayInterfaceToArrayOfStrings(refreshMap["depends_on"].([]interface{})),
+			Append:       refreshMap["append"].(bool),
+		}
+	}
+	return viewResource
+}
+
+func refreshToList(refresh *RefreshResource) []interface{} {
+	if refresh == nil {
+		return []interface{}{}
+	}
+	return []interface{}{map[string]interface{}{
+		"kind":          refresh.Kind,
+		"interval":      refresh.Interval,
+		"offset":        refresh.Offset,
+		"randomize_for": refresh.RandomizeFor,
+		"depends_on":    refresh.DependsOn,
+		"append":        refresh.Append,
+	}}
+}
+
+func suppressEquivalentInterval(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(common.NormalizeQuery(old), common.NormalizeQuery(new))
+}
@@ -0,0 +1,91 @@
+package resourceview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceMaterializedViewDatabaseName = "test_materialized_view_database"
+
+func TestAccResourceMaterializedView(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: materializedViewConfig(testResourceMaterializedViewDatabaseName, "SELECT key, count() AS total FROM test_materialized_view_database.events GROUP BY key"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_materialized_view.to_table", "to", testResourceMaterializedViewDatabaseName+".totals"),
+					resource.TestCheckResourceAttr("clickhouse_materialized_view.inner", "engine", "SummingMergeTree"),
+					resource.TestCheckResourceAttr("clickhouse_materialized_view.inner", "order_by.#", "1"),
+					resource.TestCheckResourceAttr("clickhouse_materialized_view.inner", "order_by.0", "key"),
+				),
+			},
+			// MODIFY QUERY IN PLACE
+			{
+				Config: materializedViewConfig(testResourceMaterializedViewDatabaseName, "SELECT key, count() AS total FROM test_materialized_view_database.events WHERE key > 0 GROUP BY key"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_materialized_view.to_table", "to", testResourceMaterializedViewDatabaseName+".totals"),
+				),
+			},
+		},
+	})
+}
+
+func materializedViewConfig(database string, query string) string {
+	s := `
+	resource "clickhouse_db" "new_db_resource" {
+		name = "%_database_%"
+	}
+
+	resource "clickhouse_table" "events" {
+		database = clickhouse_db.new_db_resource.name
+		name = "events"
+		engine = "MergeTree"
+		order_by = ["key"]
+		column {
+			name = "key"
+			type = "Int64"
+		}
+	}
+
+	resource "clickhouse_table" "totals" {
+		database = clickhouse_db.new_db_resource.name
+		name = "totals"
+		engine = "SummingMergeTree"
+		order_by = ["key"]
+		column {
+			name = "key"
+			type = "Int64"
+		}
+		column {
+			name = "total"
+			type = "UInt64"
+		}
+	}
+
+	resource "clickhouse_materialized_view" "to_table" {
+		database = clickhouse_db.new_db_resource.name
+		name = "totals_mv"
+		to = "%_database_%.totals"
+		query = "%_query_%"
+		depends_on = [clickhouse_table.events, clickhouse_table.totals]
+	}
+
+	resource "clickhouse_materialized_view" "inner" {
+		database = clickhouse_db.new_db_resource.name
+		name = "inner_mv"
+		engine = "SummingMergeTree"
+		order_by = ["key"]
+		populate = true
+		query = "%_query_%"
+		depends_on = [clickhouse_table.events]
+	}
+	`
+	s = strings.Replace(s, "%_database_%", database, -1)
+	s = strings.Replace(s, "%_query_%", query, -1)
+	return s
+}
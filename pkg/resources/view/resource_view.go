@@ -0,0 +1,231 @@
+package resourceview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceView() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage views, including parameterized views whose query references `{name:Type}` parameters",
+
+		CreateContext: resourceViewCreate,
+		ReadContext:   resourceViewRead,
+		UpdateContext: resourceViewUpdate,
+		DeleteContext: resourceViewDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceViewImport,
+		},
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIf("query", queryRequiresReplacement),
+			customdiff.ForceNewIf("sql_security", requiresReplacement),
+			customdiff.ForceNewIf("definer", requiresReplacement),
+		),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Description: "DB Name where the view will bellow",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "View Name",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"cluster": {
+				Description: "Cluster Name, the view is created ON CLUSTER when set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"query": {
+				Description:      "SELECT query of the view. Differences in whitespace, comments, identifier quotes, keyword case and parentheses around AND and OR operands with the query formatted by the server are ignored",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentQuery,
+			},
+			"comment": {
+				Description: "View comment, stored as given",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"sql_security": {
+				Description:  "SQL SECURITY of the view, one of DEFINER, INVOKER or NONE",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"DEFINER", "INVOKER", "NONE"}, false),
+			},
+			"definer": {
+				Description: "User the view query runs as when `sql_security` is DEFINER, or CURRENT_USER",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"or_replace": {
+				Description: "Apply changes of `query`, `sql_security` and `definer` in place with CREATE OR REPLACE VIEW instead of recreating the view",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+// requiresReplacement tells if changes must recreate the view, as they are only applied in place with or_replace
+func requiresReplacement(ctx context.Context, d *schema.ResourceDiff, meta any) bool {
+	return !d.Get("or_replace").(bool)
+}
+
+func queryRequiresReplacement(ctx context.Context, d *schema.ResourceDiff, meta any) bool {
+	old, new := d.GetChange("query")
+	return requiresReplacement(ctx, d, meta) && common.NormalizeQuery(old.(string)) != common.NormalizeQuery(new.(string))
+}
+
+func resourceViewRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := meta.(*common.ApiClient)
+	conn := client.ClickhouseConnection
+
+	database := d.Get("database").(string)
+	viewName := d.Get("name").(string)
+
+	chViewService := CHViewService{CHConnection: conn}
+	chView, err := chViewService.GetView(ctx, database, viewName)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading Clickhouse view: %v", err))
+	}
+	if chView == nil {
+		d.SetId("")
+		return diags
+	}
+
+	viewResource, err := chView.ToResource()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("transforming Clickhouse view to resource: %v", err))
+	}
+
+	if err := d.Set("database", viewResource.Database); err != nil {
+		return diag.FromErr(fmt.Errorf("setting database: %v", err))
+	}
+	if err := d.Set("name", viewResource.Name); err != nil {
+		return diag.FromErr(fmt.Errorf("setting name: %v", err))
+	}
+	if err := d.Set("query", viewResource.Query); err != nil {
+		return diag.FromErr(fmt.Errorf("setting query: %v", err))
+	}
+	if err := d.Set("comment", viewResource.Comment); err != nil {
+		return diag.FromErr(fmt.Errorf("setting comment: %v", err))
+	}
+	if err := d.Set("sql_security", viewResource.SQLSecurity); err != nil {
+		return diag.FromErr(fmt.Errorf("setting sql_security: %v", err))
+	}
+	// The server resolves CURRENT_USER to the user that created the view
+	if !strings.EqualFold(d.Get("definer").(string), currentUserDefiner) {
+		if err := d.Set("definer", viewResource.Definer); err != nil {
+			return diag.FromErr(fmt.Errorf("setting definer: %v", err))
+		}
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + database + ":" + viewName)
+
+	return diags
+}
+
+func resourceViewImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	idParts, err := common.ParseImportId(d.Id(), "cluster", "database", "view")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("cluster", idParts[0]); err != nil {
+		return nil, fmt.Errorf("setting cluster: %v", err)
+	}
+	if err := d.Set("database", idParts[1]); err != nil {
+		return nil, fmt.Errorf("setting database: %v", err)
+	}
+	if err := d.Set("name", idParts[2]); err != nil {
+		return nil, fmt.Errorf("setting name: %v", err)
+	}
+	if err := d.Set("or_replace", false); err != nil {
+		return nil, fmt.Errorf("setting or_replace: %v", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceViewCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chViewService := CHViewService{CHConnection: client.ClickhouseConnection}
+
+	viewResource := viewResourceFromData(d, client)
+	if err := chViewService.CreateView(ctx, viewResource); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cluster").(string) + ":" + viewResource.Database + ":" + viewResource.Name)
+
+	return resourceViewRead(ctx, d, meta)
+}
+
+func resourceViewUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*common.ApiClient)
+	chViewService := CHViewService{CHConnection: client.ClickhouseConnection}
+
+	viewResource := viewResourceFromData(d, client)
+	if d.HasChanges("query", "sql_security", "definer") {
+		// The view is only updated in place with or_replace, otherwise these changes force a new view
+		if err := chViewService.ReplaceView(ctx, viewResource); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if d.HasChange("comment") {
+		if err := chViewService.UpdateViewComment(ctx, viewResource); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceViewRead(ctx, d, meta)
+}
+
+func resourceViewDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*common.ApiClient)
+	chViewService := CHViewService{CHConnection: client.ClickhouseConnection}
+
+	viewResource := viewResourceFromData(d, client)
+	if err := chViewService.DeleteView(ctx, viewResource); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func viewResourceFromData(d *schema.ResourceData, client *common.ApiClient) ViewResource {
+	viewResource := ViewResource{
+		Database:    d.Get("database").(string),
+		Name:        d.Get("name").(string),
+		Cluster:     d.Get("cluster").(string),
+		Query:       d.Get("query").(string),
+		Comment:     d.Get("comment").(string),
+		SQLSecurity: d.Get("sql_security").(string),
+		Definer:     d.Get("definer").(string),
+		OrReplace:   d.Get("or_replace").(bool),
+	}
+	if viewResource.Cluster == "" {
+		viewResource.Cluster = client.DefaultCluster
+	}
+	return viewResource
+}
+
+func suppressEquivalentQuery(k, old, new string, d *schema.ResourceData) bool {
+	return common.NormalizeQuery(old) == common.NormalizeQuery(new)
+}
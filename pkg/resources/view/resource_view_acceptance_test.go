@@ -0,0 +1,69 @@
+package resourceview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/testutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testResourceViewDatabaseName = "test_view_database"
+const testResourceViewName = "view_test"
+
+func TestAccResourceView(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:  func() { testutils.TestAccPreCheck(t) },
+		Providers: testutils.Provider(),
+		Steps: []resource.TestStep{
+			{
+				Config: viewConfig(testResourceViewDatabaseName, testResourceViewName, "select key from test_view_database.events;", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_view.view", "name", testResourceViewName),
+					resource.TestCheckResourceAttr("clickhouse_view.view", "database", testResourceViewDatabaseName),
+					resource.TestCheckResourceAttr("clickhouse_view.view", "comment", "first"),
+				),
+			},
+			// REPLACE THE QUERY IN PLACE
+			{
+				Config: viewConfig(testResourceViewDatabaseName, testResourceViewName, "SELECT key FROM test_view_database.events WHERE key > {min_key:Int64}", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("clickhouse_view.view", "comment", "second"),
+				),
+			},
+		},
+	})
+}
+
+func viewConfig(database string, viewName string, query string, comment string) string {
+	s := `
+	resource "clickhouse_db" "new_db_resource" {
+		name = "%_database_%"
+	}
+
+	resource "clickhouse_table" "events" {
+		database = clickhouse_db.new_db_resource.name
+		name = "events"
+		engine = "MergeTree"
+		order_by = ["key"]
+		column {
+			name = "key"
+			type = "Int64"
+		}
+	}
+
+	resource "clickhouse_view" "view" {
+		database = clickhouse_db.new_db_resource.name
+		name = "%_viewName_%"
+		query = "%_query_%"
+		comment = "%_comment_%"
+		or_replace = true
+		depends_on = [clickhouse_table.events]
+	}
+	`
+	s = strings.Replace(s, "%_database_%", database, -1)
+	s = strings.Replace(s, "%_viewName_%", viewName, -1)
+	s = strings.Replace(s, "%_query_%", query, -1)
+	s = strings.Replace(s, "%_comment_%", comment, -1)
+	return s
+}
@@ -0,0 +1,170 @@
+package resourceview
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildCreateViewSentence(t *testing.T) {
+	tests := []struct {
+		name      string
+		view      ViewResource
+		orReplace bool
+		want      string
+	}{
+		{
+			name: "plain view",
+			view: ViewResource{Database: "db", Name: "events_view", Query: "SELECT * FROM db.events;\n"},
+			want: "CREATE VIEW `db`.`events_view` AS SELECT * FROM db.events",
+		},
+		{
+			name:      "replaced on cluster with comment",
+			view:      ViewResource{Database: "db", Name: "events_view", Cluster: "main", Query: "SELECT 1", Comment: "it's a view"},
+			orReplace: true,
+			want:      "CREATE OR REPLACE VIEW `db`.`events_view` ON CLUSTER `main` AS SELECT 1 COMMENT 'it\\'s a view'",
+		},
+		{
+			name: "sql security",
+			view: ViewResource{Database: "db", Name: "v", Definer: "alice", SQLSecurity: "DEFINER", Query: "SELECT * FROM db.events WHERE user = {user:String}"},
+			want: "CREATE VIEW `db`.`v` DEFINER = `alice` SQL SECURITY DEFINER AS SELECT * FROM db.events WHERE user = {user:String}",
+		},
+		{
+			name: "current user definer",
+			view: ViewResource{Database: "db", Name: "v", Definer: "CURRENT_USER", SQLSecurity: "DEFINER", Query: "SELECT 1"},
+			want: "CREATE VIEW `db`.`v` DEFINER = CURRENT_USER SQL SECURITY DEFINER AS SELECT 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildCreateViewSentence(tt.view, tt.orReplace); got != tt.want {
+				t.Errorf("buildCreateViewSentence() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildModifyCommentSentence(t *testing.T) {
+	got := buildModifyCommentSentence(ViewResource{Database: "db", Name: "v", Comment: "new"})
+	if want := "ALTER TABLE `db`.`v` MODIFY COMMENT 'new'"; got != want {
+		t.Errorf("buildModifyCommentSentence() = %s, want %s", got, want)
+	}
+
+	got = buildModifyCommentSentence(ViewResource{Database: "db", Name: "v", Cluster: "main", Comment: "daily  totals\tby day"})
+	if want := "ALTER TABLE `db`.`v` ON CLUSTER `main` MODIFY COMMENT 'daily  totals\tby day'"; got != want {
+		t.Errorf("buildModifyCommentSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestCHViewToResource(t *testing.T) {
+	chView := CHView{
+		Database:         "db",
+		Name:             "v",
+		AsSelect:         "SELECT a FROM db.t",
+		Comment:          "some comment",
+		CreateTableQuery: "CREATE VIEW db.v (`a` UInt8) DEFINER = `alice` SQL SECURITY DEFINER AS SELECT a FROM db.t COMMENT 'some comment'",
+	}
+	viewResource, err := chView.ToResource()
+	if err != nil {
+		t.Fatalf("ToResource() unexpected error: %v", err)
+	}
+	want := ViewResource{Database: "db", Name: "v", Query: "SELECT a FROM db.t", Comment: "some comment", SQLSecurity: "DEFINER", Definer: "alice"}
+	if *viewResource != want {
+		t.Errorf("ToResource() = %+v, want %+v", *viewResource, want)
+	}
+
+	// Servers without as_select
+	chView.AsSelect = ""
+	viewResource, err = chView.ToResource()
+	if err != nil {
+		t.Fatalf("ToResource() unexpected error: %v", err)
+	}
+	if viewResource.Query != "SELECT a FROM db.t" {
+		t.Errorf("ToResource() query = %q, want the create query select", viewResource.Query)
+	}
+}
+
+func TestBuildCreateMaterializedViewSentence(t *testing.T) {
+	tests := []struct {
+		name string
+		view MaterializedViewResource
+		want string
+	}{
+		{
+			name: "to table",
+			view: MaterializedViewResource{Database: "db", Name: "mv", To: "db.events_daily", Query: "SELECT toDate(ts) AS day, count() AS total FROM db.events GROUP BY day"},
+			want: "CREATE MATERIALIZED VIEW `db`.`mv` TO `db`.`events_daily` AS SELECT toDate(ts) AS day, count() AS total FROM db.events GROUP BY day",
+		},
+		{
+			name: "inner storage populated on cluster",
+			view: MaterializedViewResource{
+				Database: "db", Name: "mv", Cluster: "main", Engine: "SummingMergeTree", EngineParams: []string{"total"},
+				OrderBy: []string{"day"}, PartitionBy: "toYYYYMM(day)", Settings: map[string]string{"index_granularity": "1024", "storage_policy": "tiered"},
+				Populate: true, Query: "SELECT 1", Comment: "daily",
+			},
+			want: "CREATE MATERIALIZED VIEW `db`.`mv` ON CLUSTER `main` ENGINE = SummingMergeTree(total) PARTITION BY toYYYYMM(day) ORDER BY (day) SETTINGS index_granularity = 1024, storage_policy = 'tiered' POPULATE AS SELECT 1 COMMENT 'daily'",
+		},
+		{
+			name: "refreshable",
+			view: MaterializedViewResource{
+				Database: "db", Name: "mv", To: "db.totals", Query: "SELECT count() FROM db.events",
+				Refresh: &RefreshResource{Kind: "EVERY", Interval: "1 HOUR", Offset: "5 MINUTE", DependsOn: []string{"db.upstream"}, Append: true},
+			},
+			want: "CREATE MATERIALIZED VIEW `db`.`mv` REFRESH EVERY 1 HOUR OFFSET 5 MINUTE DEPENDS ON `db`.`upstream` APPEND TO `db`.`totals` AS SELECT count() FROM db.events",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildCreateMaterializedViewSentence(tt.view); got != tt.want {
+				t.Errorf("buildCreateMaterializedViewSentence() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAlterMaterializedViewSentences(t *testing.T) {
+	if got, want := buildModifyQuerySentence("db", "mv", "", "SELECT 2;"), "ALTER TABLE `db`.`mv` MODIFY QUERY SELECT 2"; got != want {
+		t.Errorf("buildModifyQuerySentence() = %s, want %s", got, want)
+	}
+	if got, want := buildModifyQuerySentence("db", "mv", "main", "SELECT 'a  b'"), "ALTER TABLE `db`.`mv` ON CLUSTER `main` MODIFY QUERY SELECT 'a  b'"; got != want {
+		t.Errorf("buildModifyQuerySentence() = %s, want %s", got, want)
+	}
+	view := MaterializedViewResource{Database: "db", Name: "mv", Cluster: "main", Refresh: &RefreshResource{Kind: "AFTER", Interval: "30 MINUTE"}}
+	if got, want := buildModifyRefreshSentence(view), "ALTER TABLE `db`.`mv` ON CLUSTER `main` MODIFY REFRESH AFTER 30 MINUTE"; got != want {
+		t.Errorf("buildModifyRefreshSentence() = %s, want %s", got, want)
+	}
+}
+
+func TestCHViewToMaterializedViewResource(t *testing.T) {
+	chView := CHView{
+		Database: "db",
+		Name:     "mv",
+		AsSelect: "SELECT count() AS total FROM db.events",
+		CreateTableQuery: "CREATE MATERIALIZED VIEW db.mv REFRESH EVERY 1 DAY OFFSET 2 HOUR RANDOMIZE FOR 10 MINUTE DEPENDS ON db.a, db.b APPEND TO db.totals " +
+			"(`total` UInt64) AS SELECT count() AS total FROM db.events",
+	}
+	viewResource, err := chView.ToMaterializedViewResource()
+	if err != nil {
+		t.Fatalf("ToMaterializedViewResource() unexpected error: %v", err)
+	}
+	if viewResource.To != "db.totals" || viewResource.Engine != "" {
+		t.Errorf("ToMaterializedViewResource() to = %q, engine = %q", viewResource.To, viewResource.Engine)
+	}
+	wantRefresh := RefreshResource{Kind: "EVERY", Interval: "1 DAY", Offset: "2 HOUR", RandomizeFor: "10 MINUTE", DependsOn: []string{"db.a", "db.b"}, Append: true}
+	if !reflect.DeepEqual(viewResource.Refresh, &wantRefresh) {
+		t.Errorf("ToMaterializedViewResource() refresh = %+v, want %+v", viewResource.Refresh, wantRefresh)
+	}
+
+	chView.CreateTableQuery = "CREATE MATERIALIZED VIEW db.mv (`day` Date, `total` UInt64) ENGINE = SummingMergeTree(total) PARTITION BY toYYYYMM(day) ORDER BY day " +
+		"SETTINGS index_granularity = 8192 AS SELECT toDate(ts) AS day, count() AS total FROM db.events GROUP BY day"
+	viewResource, err = chView.ToMaterializedViewResource()
+	if err != nil {
+		t.Fatalf("ToMaterializedViewResource() unexpected error: %v", err)
+	}
+	if viewResource.Engine != "SummingMergeTree" || !reflect.DeepEqual(viewResource.EngineParams, []string{"total"}) ||
+		!reflect.DeepEqual(viewResource.OrderBy, []string{"day"}) || viewResource.PartitionBy != "toYYYYMM(day)" ||
+		viewResource.Settings["index_granularity"] != "8192" || viewResource.Refresh != nil {
+		t.Errorf("ToMaterializedViewResource() = %+v", viewResource)
+	}
+}
@@ -0,0 +1,106 @@
+package resourceview
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+type CHViewService struct {
+	CHConnection *driver.Conn
+}
+
+// GetView returns the view, or nil when it does not exist
+func (vs *CHViewService) GetView(ctx context.Context, database string, name string) (*CHView, error) {
+	return vs.getView(ctx, database, name, "View")
+}
+
+// GetMaterializedView returns the materialized view, or nil when it does not exist
+func (vs *CHViewService) GetMaterializedView(ctx context.Context, database string, name string) (*CHView, error) {
+	return vs.getView(ctx, database, name, "MaterializedView")
+}
+
+func (vs *CHViewService) getView(ctx context.Context, database string, name string, engine string) (*CHView, error) {
+	query := "SELECT database, name, as_select, comment, create_table_query FROM system.tables where database = {database:String} and name = {view:String} and engine = {engine:String}"
+	row := (*vs.CHConnection).QueryRow(common.QueryParameters(ctx, map[string]string{"database": database, "view": name, "engine": engine}), query)
+
+	if row.Err() != nil {
+		return nil, fmt.Errorf("reading view from Clickhouse: %v", row.Err())
+	}
+
+	var chView CHView
+	err := row.ScanStruct(&chView)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning Clickhouse view row: %v", err)
+	}
+	return &chView, nil
+}
+
+func (vs *CHViewService) CreateView(ctx context.Context, viewResource ViewResource) error {
+	query := buildCreateViewSentence(viewResource, false)
+	err := (*vs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse view: %v", err)
+	}
+	return nil
+}
+
+func (vs *CHViewService) ReplaceView(ctx context.Context, viewResource ViewResource) error {
+	query := buildCreateViewSentence(viewResource, true)
+	err := (*vs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("replacing Clickhouse view: %v", err)
+	}
+	return nil
+}
+
+func (vs *CHViewService) UpdateViewComment(ctx context.Context, viewResource ViewResource) error {
+	err := (*vs.CHConnection).Exec(ctx, buildModifyCommentSentence(viewResource))
+	if err != nil {
+		return fmt.Errorf("updating view comment: %v", err)
+	}
+	return nil
+}
+
+func (vs *CHViewService) CreateMaterializedView(ctx context.Context, viewResource MaterializedViewResource) error {
+	query := buildCreateMaterializedViewSentence(viewResource)
+	err := (*vs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("creating Clickhouse materialized view: %v", err)
+	}
+	return nil
+}
+
+func (vs *CHViewService) ModifyMaterializedViewQuery(ctx context.Context, viewResource MaterializedViewResource) error {
+	query := buildModifyQuerySentence(viewResource.Database, viewResource.Name, viewResource.Cluster, viewResource.Query)
+	err := (*vs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("modifying materialized view query. SQL: %s, error: %v", query, err)
+	}
+	return nil
+}
+
+func (vs *CHViewService) ModifyMaterializedViewRefresh(ctx context.Context, viewResource MaterializedViewResource) error {
+	query := buildModifyRefreshSentence(viewResource)
+	err := (*vs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("modifying materialized view refresh. SQL: %s, error: %v", query, err)
+	}
+	return nil
+}
+
+func (vs *CHViewService) DeleteView(ctx context.Context, viewResource ViewResource) error {
+	query := fmt.Sprintf("DROP VIEW %s %s", common.QuoteTableName(viewResource.Database, viewResource.Name), common.GetClusterStatement(viewResource.Cluster))
+	err := (*vs.CHConnection).Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("deleting Clickhouse view: %v", err)
+	}
+	return nil
+}
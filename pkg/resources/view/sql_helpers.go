@@ -0,0 +1,139 @@
+package resourceview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fox052-byte/terraform-provider-clickhouse/pkg/common"
+)
+
+func buildCreateViewSentence(viewResource ViewResource, orReplace bool) string {
+	statement := "CREATE VIEW"
+	if orReplace {
+		statement = "CREATE OR REPLACE VIEW"
+	}
+	parts := []string{statement, common.QuoteTableName(viewResource.Database, viewResource.Name)}
+	if clusterStatement := common.GetClusterStatement(viewResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if viewResource.Definer != "" {
+		definer := viewResource.Definer
+		if !strings.EqualFold(definer, currentUserDefiner) {
+			definer = common.QuoteIdentifier(definer)
+		}
+		parts = append(parts, "DEFINER = "+definer)
+	}
+	if viewResource.SQLSecurity != "" {
+		parts = append(parts, "SQL SECURITY "+viewResource.SQLSecurity)
+	}
+	parts = append(parts, "AS", trimQuery(viewResource.Query))
+	if viewResource.Comment != "" {
+		parts = append(parts, "COMMENT "+common.QuoteString(viewResource.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildModifyCommentSentence(viewResource ViewResource) string {
+	return buildAlterViewSentence(viewResource.Database, viewResource.Name, viewResource.Cluster, "MODIFY COMMENT "+common.QuoteString(viewResource.Comment))
+}
+
+// buildAlterViewSentence returns the ALTER TABLE statement running the command on the view, the command is kept verbatim
+// as it may hold literals
+func buildAlterViewSentence(database string, name string, cluster string, command string) string {
+	parts := []string{"ALTER TABLE", common.QuoteTableName(database, name)}
+	if clusterStatement := common.GetClusterStatement(cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	parts = append(parts, command)
+	return strings.Join(parts, " ")
+}
+
+// trimQuery drops the trailing semicolon a query may be written with, as the view query is followed by its comment
+func trimQuery(query string) string {
+	query = strings.TrimSpace(query)
+	for strings.HasSuffix(query, ";") {
+		query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+	}
+	return query
+}
+
+func buildCreateMaterializedViewSentence(viewResource MaterializedViewResource) string {
+	parts := []string{"CREATE MATERIALIZED VIEW", common.QuoteTableName(viewResource.Database, viewResource.Name)}
+	if clusterStatement := common.GetClusterStatement(viewResource.Cluster); clusterStatement != "" {
+		parts = append(parts, clusterStatement)
+	}
+	if viewResource.Refresh != nil {
+		parts = append(parts, buildRefreshClause(*viewResource.Refresh))
+		if viewResource.Refresh.Append {
+			parts = append(parts, "APPEND")
+		}
+	}
+	if viewResource.To != "" {
+		parts = append(parts, "TO "+quoteQualifiedName(viewResource.To))
+	}
+	if viewResource.Engine != "" {
+		parts = append(parts, buildInnerStorageClauses(viewResource)...)
+	}
+	if viewResource.Populate {
+		parts = append(parts, "POPULATE")
+	}
+	parts = append(parts, "AS", trimQuery(viewResource.Query))
+	if viewResource.Comment != "" {
+		parts = append(parts, "COMMENT "+common.QuoteString(viewResource.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildInnerStorageClauses(viewResource MaterializedViewResource) []string {
+	var parts []string
+	if len(viewResource.EngineParams) > 0 {
+		parts = append(parts, fmt.Sprintf("ENGINE = %s(%s)", viewResource.Engine, strings.Join(viewResource.EngineParams, ", ")))
+	} else {
+		parts = append(parts, "ENGINE = "+viewResource.Engine)
+	}
+	if viewResource.PartitionBy != "" {
+		parts = append(parts, "PARTITION BY "+viewResource.PartitionBy)
+	}
+	if len(viewResource.PrimaryKey) > 0 {
+		parts = append(parts, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(viewResource.PrimaryKey, ", ")))
+	}
+	if len(viewResource.OrderBy) > 0 {
+		parts = append(parts, fmt.Sprintf("ORDER BY (%s)", strings.Join(viewResource.OrderBy, ", ")))
+	}
+	if len(viewResource.Settings) > 0 {
+		parts = append(parts, "SETTINGS "+common.BuildSettingsSentence(viewResource.Settings))
+	}
+	return parts
+}
+
+func buildRefreshClause(refresh RefreshResource) string {
+	parts := []string{"REFRESH", refresh.Kind, refresh.Interval}
+	if refresh.Offset != "" {
+		parts = append(parts, "OFFSET", refresh.Offset)
+	}
+	if refresh.RandomizeFor != "" {
+		parts = append(parts, "RANDOMIZE FOR", refresh.RandomizeFor)
+	}
+	if len(refresh.DependsOn) > 0 {
+		dependencies := make([]string, 0, len(refresh.DependsOn))
+		for _, dependency := range refresh.DependsOn {
+			dependencies = append(dependencies, quoteQualifiedName(dependency))
+		}
+		parts = append(parts, "DEPENDS ON", strings.Join(dependencies, ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+func buildModifyQuerySentence(database string, name string, cluster string, query string) string {
+	return buildAlterViewSentence(database, name, cluster, "MODIFY QUERY "+trimQuery(query))
+}
+
+func buildModifyRefreshSentence(viewResource MaterializedViewResource) string {
+	return buildAlterViewSentence(viewResource.Database, viewResource.Name, viewResource.Cluster, "MODIFY "+buildRefreshClause(*viewResource.Refresh))
+}
+
+// quoteQualifiedName quotes a table name given as database.table, or just table
+func quoteQualifiedName(name string) string {
+	parts := common.SplitQualifiedName(name)
+	return strings.Join(common.QuoteIdentifiers(parts), ".")
+}